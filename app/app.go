@@ -8,12 +8,20 @@ import (
 	"knb/app/services"
 	"knb/db"
 	"log"
+	"time"
+)
+
+const (
+	noShowCheckInterval     = time.Minute
+	accountDeletionInterval = time.Hour
+	presenceCleanupInterval = time.Hour
 )
 
 type Application struct {
 	config     *config.Config
 	httpServer *httpServer
 	db         *db.DB
+	scheduler  *scheduler
 }
 
 func NewApplication(config *config.Config) *Application {
@@ -21,6 +29,7 @@ func NewApplication(config *config.Config) *Application {
 		config:     config,
 		httpServer: new(httpServer),
 		db:         new(db.DB),
+		scheduler:  newScheduler(),
 	}
 }
 
@@ -29,7 +38,19 @@ func (app *Application) Run() {
 		log.Fatal(err.Error())
 	}
 
-	if err := app.runHttpServer(); err != nil {
+	service := services.NewService(
+		repositories.NewRepository(app.db.DB()),
+		app.config,
+	)
+	if err := service.Leaderboard.Backfill(); err != nil {
+		log.Printf("Failed to backfill the leaderboards: %s\n", err.Error())
+	}
+	if err := service.Stats.Backfill(); err != nil {
+		log.Printf("Failed to backfill the stats: %s\n", err.Error())
+	}
+	app.runScheduler(service)
+
+	if err := app.runHttpServer(service); err != nil {
 		log.Fatalf("Error occured while running HTTP server: %s\n", err.Error())
 	}
 
@@ -48,18 +69,20 @@ func (app *Application) connectDB() error {
 	return nil
 }
 
-func (app *Application) runHttpServer() error {
+func (app *Application) runScheduler(service *services.Service) {
+	app.scheduler.every(noShowCheckInterval, "tournament no-shows", service.Tournament.CheckNoShows)
+	app.scheduler.every(accountDeletionInterval, "account deletions", service.Player.DeleteDue)
+	app.scheduler.every(presenceCleanupInterval, "presence cleanup", service.Presence.Cleanup)
+}
+
+func (app *Application) runHttpServer(service *services.Service) error {
 	return app.httpServer.run(
 		app.config.AppPort,
-		handlers.NewHandler(
-			services.NewService(
-				repositories.NewRepository(app.db.DB()),
-				app.config,
-			),
-		).InitRoutes(app.config.HandlerMode),
+		handlers.NewHandler(service).InitRoutes(app.config.HandlerMode),
 	)
 }
 
 func (app *Application) Shutdown() {
+	app.scheduler.shutdown()
 	println("Off")
 }
@@ -0,0 +1,69 @@
+package bots
+
+import (
+	"fmt"
+	"knb/app/dictionary"
+	"knb/app/interfaces"
+	"math/rand/v2"
+	"sync"
+)
+
+const defaultMarkovOrder = 2
+
+type Factory func(rnd *rand.Rand) interfaces.BotStrategy
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[dictionary.BotStrategy]Factory)
+)
+
+// Register makes an external strategy available under its name next to the built-in ones.
+func Register(name dictionary.BotStrategy, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[name] = factory
+}
+
+func NewStrategy(name dictionary.BotStrategy, rnd *rand.Rand) (interfaces.BotStrategy, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if ok {
+		return factory(rnd), nil
+	}
+
+	switch name {
+	case dictionary.BotStrategyRandom:
+		return newRandomStrategy(rnd), nil
+	case dictionary.BotStrategyFrequency:
+		return newFrequencyStrategy(rnd), nil
+	case dictionary.BotStrategyMarkov:
+		return newMarkovStrategy(rnd, defaultMarkovOrder), nil
+	case dictionary.BotStrategyWinStay:
+		return newWinStayStrategy(rnd), nil
+	}
+
+	return nil, fmt.Errorf("unknown bot strategy %q", name)
+}
+
+func Strategies() []dictionary.BotStrategy {
+	strategies := []dictionary.BotStrategy{
+		dictionary.BotStrategyRandom,
+		dictionary.BotStrategyFrequency,
+		dictionary.BotStrategyMarkov,
+		dictionary.BotStrategyWinStay,
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for name := range registry {
+		strategies = append(strategies, name)
+	}
+
+	return strategies
+}
+
+func randomThrow(rnd *rand.Rand) dictionary.Throw {
+	return dictionary.Throws[rnd.IntN(len(dictionary.Throws))]
+}
@@ -0,0 +1,90 @@
+package bots
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/rules"
+	"sort"
+)
+
+type roundOutcome int
+
+const (
+	outcomeDraw roundOutcome = iota
+	outcomeWin
+	outcomeLoss
+)
+
+type round struct {
+	number    uint
+	own       dictionary.Throw
+	opponents []dictionary.Throw
+	outcome   roundOutcome
+}
+
+// history groups the moves of a game into rounds seen from the bot's seat.
+// Rounds the bot did not play in are skipped.
+func history(botId uuid.UUID, moves []entities.GameMove) []round {
+	byRound := make(map[uint][]entities.GameMove)
+	for _, move := range moves {
+		byRound[move.Round] = append(byRound[move.Round], move)
+	}
+
+	rounds := make([]round, 0, len(byRound))
+	for number, roundMoves := range byRound {
+		r := round{number: number}
+		played := false
+		for _, move := range roundMoves {
+			if move.PlayerID == botId {
+				r.own = move.Throw
+				played = true
+			} else {
+				r.opponents = append(r.opponents, move.Throw)
+			}
+		}
+		if !played || len(r.opponents) == 0 {
+			continue
+		}
+
+		r.outcome = outcomeDraw
+		if losers := rules.Losers(roundMoves); len(losers) > 0 {
+			r.outcome = outcomeWin
+			for _, loser := range losers {
+				if loser == botId {
+					r.outcome = outcomeLoss
+				}
+			}
+		}
+
+		rounds = append(rounds, r)
+	}
+
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i].number < rounds[j].number
+	})
+
+	return rounds
+}
+
+func opponentThrows(rounds []round) []dictionary.Throw {
+	throws := make([]dictionary.Throw, 0, len(rounds))
+	for _, r := range rounds {
+		throws = append(throws, r.opponents...)
+	}
+
+	return throws
+}
+
+func mostLikely(counts map[dictionary.Throw]int) (dictionary.Throw, bool) {
+	var best dictionary.Throw
+	bestCount := 0
+	for _, throw := range dictionary.Throws {
+		if counts[throw] > bestCount {
+			best = throw
+			bestCount = counts[throw]
+		}
+	}
+
+	return best, bestCount > 0
+}
@@ -0,0 +1,126 @@
+package bots
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/rules"
+	"math/rand/v2"
+	"strings"
+)
+
+type randomStrategy struct {
+	rnd *rand.Rand
+}
+
+func newRandomStrategy(rnd *rand.Rand) *randomStrategy {
+	return &randomStrategy{rnd}
+}
+
+func (s *randomStrategy) Name() dictionary.BotStrategy {
+	return dictionary.BotStrategyRandom
+}
+
+func (s *randomStrategy) Next(_ uuid.UUID, _ []entities.GameMove) dictionary.Throw {
+	return randomThrow(s.rnd)
+}
+
+// frequencyStrategy counters the throw the opponents have shown most often.
+type frequencyStrategy struct {
+	rnd *rand.Rand
+}
+
+func newFrequencyStrategy(rnd *rand.Rand) *frequencyStrategy {
+	return &frequencyStrategy{rnd}
+}
+
+func (s *frequencyStrategy) Name() dictionary.BotStrategy {
+	return dictionary.BotStrategyFrequency
+}
+
+func (s *frequencyStrategy) Next(botId uuid.UUID, moves []entities.GameMove) dictionary.Throw {
+	counts := make(map[dictionary.Throw]int, len(dictionary.Throws))
+	for _, throw := range opponentThrows(history(botId, moves)) {
+		counts[throw]++
+	}
+
+	if expected, ok := mostLikely(counts); ok {
+		return rules.Counter(expected)
+	}
+
+	return randomThrow(s.rnd)
+}
+
+// markovStrategy predicts the next opponent throw from what followed
+// the same sequence of their last `order` throws earlier in the game.
+type markovStrategy struct {
+	rnd   *rand.Rand
+	order int
+}
+
+func newMarkovStrategy(rnd *rand.Rand, order int) *markovStrategy {
+	return &markovStrategy{rnd, order}
+}
+
+func (s *markovStrategy) Name() dictionary.BotStrategy {
+	return dictionary.BotStrategyMarkov
+}
+
+func (s *markovStrategy) Next(botId uuid.UUID, moves []entities.GameMove) dictionary.Throw {
+	throws := opponentThrows(history(botId, moves))
+	if len(throws) <= s.order {
+		return randomThrow(s.rnd)
+	}
+
+	transitions := make(map[string]map[dictionary.Throw]int)
+	for i := s.order; i < len(throws); i++ {
+		key := sequenceKey(throws[i-s.order : i])
+		if transitions[key] == nil {
+			transitions[key] = make(map[dictionary.Throw]int, len(dictionary.Throws))
+		}
+		transitions[key][throws[i]]++
+	}
+
+	if expected, ok := mostLikely(transitions[sequenceKey(throws[len(throws)-s.order:])]); ok {
+		return rules.Counter(expected)
+	}
+
+	return randomThrow(s.rnd)
+}
+
+func sequenceKey(throws []dictionary.Throw) string {
+	parts := make([]string, 0, len(throws))
+	for _, throw := range throws {
+		parts = append(parts, string(throw))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// winStayStrategy repeats a winning (or drawn) throw and after a loss
+// switches to the throw that beats the one it lost to.
+type winStayStrategy struct {
+	rnd *rand.Rand
+}
+
+func newWinStayStrategy(rnd *rand.Rand) *winStayStrategy {
+	return &winStayStrategy{rnd}
+}
+
+func (s *winStayStrategy) Name() dictionary.BotStrategy {
+	return dictionary.BotStrategyWinStay
+}
+
+func (s *winStayStrategy) Next(botId uuid.UUID, moves []entities.GameMove) dictionary.Throw {
+	rounds := history(botId, moves)
+	if len(rounds) == 0 {
+		return randomThrow(s.rnd)
+	}
+
+	last := rounds[len(rounds)-1]
+	if last.outcome != outcomeLoss {
+		return last.own
+	}
+
+	return rules.Counter(rules.Counter(last.own))
+}
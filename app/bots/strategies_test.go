@@ -0,0 +1,105 @@
+package bots
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"math/rand/v2"
+	"testing"
+)
+
+var (
+	gameId   = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	botId    = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	playerId = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+)
+
+type strategyTestCase struct {
+	strategy dictionary.BotStrategy
+	own      []dictionary.Throw
+	opponent []dictionary.Throw
+	expected dictionary.Throw
+	name     string
+}
+
+// gameMoves plays the throws of the bot and its opponent round by round.
+func gameMoves(own, opponent []dictionary.Throw) []entities.GameMove {
+	moves := make([]entities.GameMove, 0, len(own)+len(opponent))
+	for index := range own {
+		round := uint(index + 1)
+		moves = append(moves,
+			*entities.NewGameMove(gameId, botId, round, own[index]),
+			*entities.NewGameMove(gameId, playerId, round, opponent[index]),
+		)
+	}
+
+	return moves
+}
+
+func TestStrategies(t *testing.T) {
+	testCases := []strategyTestCase{
+		{
+			strategy: dictionary.BotStrategyFrequency,
+			own:      []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowRock, dictionary.ThrowRock},
+			opponent: []dictionary.Throw{dictionary.ThrowPaper, dictionary.ThrowRock, dictionary.ThrowPaper},
+			expected: dictionary.ThrowScissors,
+			name:     "frequency counters the most shown throw",
+		},
+		{
+			strategy: dictionary.BotStrategyMarkov,
+			own: []dictionary.Throw{
+				dictionary.ThrowRock, dictionary.ThrowRock, dictionary.ThrowRock, dictionary.ThrowRock, dictionary.ThrowRock,
+			},
+			opponent: []dictionary.Throw{
+				dictionary.ThrowRock, dictionary.ThrowPaper, dictionary.ThrowScissors, dictionary.ThrowRock, dictionary.ThrowPaper,
+			},
+			expected: dictionary.ThrowRock,
+			name:     "markov counters what followed the last sequence",
+		},
+		{
+			strategy: dictionary.BotStrategyWinStay,
+			own:      []dictionary.Throw{dictionary.ThrowPaper, dictionary.ThrowRock},
+			opponent: []dictionary.Throw{dictionary.ThrowScissors, dictionary.ThrowScissors},
+			expected: dictionary.ThrowRock,
+			name:     "win-stay repeats a win",
+		},
+		{
+			strategy: dictionary.BotStrategyWinStay,
+			own:      []dictionary.Throw{dictionary.ThrowPaper},
+			opponent: []dictionary.Throw{dictionary.ThrowPaper},
+			expected: dictionary.ThrowPaper,
+			name:     "win-stay repeats a draw",
+		},
+		{
+			strategy: dictionary.BotStrategyWinStay,
+			own:      []dictionary.Throw{dictionary.ThrowRock},
+			opponent: []dictionary.Throw{dictionary.ThrowPaper},
+			expected: dictionary.ThrowScissors,
+			name:     "win-stay beats the throw it lost to",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			strategy, err := NewStrategy(tCase.strategy, rand.New(rand.NewPCG(1, 2)))
+			if !assert.NoError(tt, err) {
+				return
+			}
+			assert.Equal(tt, tCase.strategy, strategy.Name())
+			assert.Equal(tt, tCase.expected, strategy.Next(botId, gameMoves(tCase.own, tCase.opponent)))
+		})
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	_, err := NewStrategy("unknown", rand.New(rand.NewPCG(1, 2)))
+	assert.Error(t, err)
+
+	for _, name := range Strategies() {
+		strategy, err := NewStrategy(name, rand.New(rand.NewPCG(1, 2)))
+		if assert.NoError(t, err) {
+			assert.Equal(t, name, strategy.Name())
+		}
+	}
+}
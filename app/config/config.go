@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"github.com/joho/godotenv"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -17,7 +20,78 @@ const (
 	postgresPassword = "POSTGRES_PASSWORD"
 	postgresDatabase = "POSTGRES_DATABASE"
 
-	tokenSigningKey = "TOKEN_SIGNING_KEY"
+	tokenSigningKey      = "TOKEN_SIGNING_KEY"
+	tokenKeysDir         = "TOKEN_KEYS_DIR"
+	tokenActiveKeyId     = "TOKEN_ACTIVE_KEY_ID"
+	tokenRetiredKeys     = "TOKEN_RETIRED_KEYS"
+	tokenKeyGraceMinutes = "TOKEN_KEY_GRACE_MINUTES"
+	tokenIssuer          = "TOKEN_ISSUER"
+	tokenAudience        = "TOKEN_AUDIENCE"
+	tokenLeewaySeconds   = "TOKEN_LEEWAY_SECONDS"
+	totpIssuer           = "TOTP_ISSUER"
+
+	appPublicUrl     = "APP_PUBLIC_URL"
+	passwordResetUrl = "APP_PASSWORD_RESET_URL"
+	avatarDir        = "AVATAR_DIR"
+
+	defaultAvatarDir = "storage/avatars"
+
+	accountDeletionCoolingOffDays        = "ACCOUNT_DELETION_COOLING_OFF_DAYS"
+	defaultAccountDeletionCoolingOffDays = 14
+
+	mailDriver    = "MAIL_DRIVER"
+	mailFrom      = "MAIL_FROM"
+	mailOutboxDir = "MAIL_OUTBOX_DIR"
+	smtpHost      = "SMTP_HOST"
+	smtpPort      = "SMTP_PORT"
+	smtpUser      = "SMTP_USER"
+	smtpPassword  = "SMTP_PASSWORD"
+
+	playerRequestsPerMinute = "RATE_LIMIT_PLAYER_REQUESTS_PER_MINUTE"
+	botRequestsPerMinute    = "RATE_LIMIT_BOT_REQUESTS_PER_MINUTE"
+	botGamesPerHour         = "RATE_LIMIT_BOT_GAMES_PER_HOUR"
+	chatMessagesPerMinute   = "RATE_LIMIT_CHAT_MESSAGES_PER_MINUTE"
+
+	defaultPlayerRequestsPerMinute = 120
+	defaultBotRequestsPerMinute    = 60
+	defaultBotGamesPerHour         = 30
+	defaultChatMessagesPerMinute   = 10
+
+	loginThrottleStore           = "LOGIN_THROTTLE_STORE"
+	loginThrottleAccountAttempts = "LOGIN_THROTTLE_ACCOUNT_ATTEMPTS"
+	loginThrottleIpAttempts      = "LOGIN_THROTTLE_IP_ATTEMPTS"
+	loginThrottleBaseLockout     = "LOGIN_THROTTLE_BASE_LOCKOUT_SECONDS"
+	loginThrottleMaxLockout      = "LOGIN_THROTTLE_MAX_LOCKOUT_MINUTES"
+
+	presenceStore      = "PRESENCE_STORE"
+	presenceTtlMinutes = "PRESENCE_TTL_MINUTES"
+
+	chatProfanityWords = "CHAT_PROFANITY_WORDS"
+
+	LoginThrottleStoreMemory   = "memory"
+	LoginThrottleStorePostgres = "postgres"
+
+	defaultLoginThrottleAccountAttempts = 5
+	defaultLoginThrottleIpAttempts      = 20
+	defaultLoginThrottleBaseLockout     = 30
+	defaultLoginThrottleMaxLockout      = 15
+
+	PresenceStoreMemory   = "memory"
+	PresenceStorePostgres = "postgres"
+
+	defaultPresenceTtlMinutes = 5
+
+	MailDriverSmtp   = "smtp"
+	MailDriverOutbox = "outbox"
+
+	defaultMailFrom = "no-reply@knb.local"
+	defaultSmtpPort = "587"
+
+	defaultTokenKeyGraceMinutes = 60
+	defaultTokenIssuer          = "knb"
+	defaultTokenAudience        = "knb"
+	defaultTokenLeewaySeconds   = 30
+	defaultTotpIssuer           = "knb"
 )
 
 type DbConfig struct {
@@ -31,13 +105,84 @@ type DbConfig struct {
 
 type AuthConfig struct {
 	TokenSigningKey string
+	// TokenKeysDir holds PEM private keys named <kid>.pem used for asymmetric token signing.
+	TokenKeysDir     string
+	TokenActiveKeyId string
+	// TokenRetiredKeys maps a key id to the moment it was retired, tokens signed with it
+	// are still accepted for TokenKeyGracePeriod after that.
+	TokenRetiredKeys    map[string]time.Time
+	TokenKeyGracePeriod time.Duration
+	TokenIssuer         string
+	TokenAudience       string
+	// TokenLeeway is the clock skew tolerated when checking exp, nbf and iat.
+	TokenLeeway time.Duration
+	// TotpIssuer is the account label shown in the authenticator apps.
+	TotpIssuer string
+}
+
+type MailConfig struct {
+	// Driver is smtp or outbox, the outbox writes the messages to OutboxDir or to the log when it's empty.
+	Driver       string
+	From         string
+	OutboxDir    string
+	SmtpHost     string
+	SmtpPort     string
+	SmtpUser     string
+	SmtpPassword string
+}
+
+type RateLimitConfig struct {
+	PlayerRequestsPerMinute int
+	BotRequestsPerMinute    int
+	BotGamesPerHour         int
+	ChatMessagesPerMinute   int
+}
+
+// LoginThrottleConfig locks an account or an IP out after the free failed logins,
+// every next failure doubles the lockout from BaseLockout up to MaxLockout.
+type LoginThrottleConfig struct {
+	// Store is memory for a single instance or postgres to share the counters between instances.
+	Store               string
+	AccountFreeAttempts int
+	IpFreeAttempts      int
+	BaseLockout         time.Duration
+	MaxLockout          time.Duration
+}
+
+func (l LoginThrottleConfig) InMemory() bool {
+	return l.Store == LoginThrottleStoreMemory
+}
+
+// PresenceConfig counts a player as online for TTL after their last request or heartbeat.
+type PresenceConfig struct {
+	// Store is memory for a single instance or postgres to share the presence between instances.
+	Store string
+	TTL   time.Duration
+}
+
+func (p PresenceConfig) InMemory() bool {
+	return p.Store == PresenceStoreMemory
 }
 
 type Config struct {
-	AppPort     string
-	HandlerMode string
+	AppPort string
+	// AppPublicUrl is the address the links sent to players point to.
+	AppPublicUrl string
+	// PasswordResetUrl is the page the password reset links open, the token is added as the query.
+	PasswordResetUrl string
+	// AvatarDir is where the uploaded avatars are stored.
+	AvatarDir string
+	// AccountDeletionCoolingOff is how long a player can take back the deletion of their account.
+	AccountDeletionCoolingOff time.Duration
+	// ChatProfanityWords are masked in the chat messages.
+	ChatProfanityWords []string
+	HandlerMode        string
 	DbConfig
 	AuthConfig
+	MailConfig
+	RateLimitConfig
+	LoginThrottleConfig
+	PresenceConfig
 }
 
 func (c *Config) Init(envFilePath string) (*Config, error) {
@@ -93,9 +238,75 @@ func (c *Config) Init(envFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	retiredKeys, err := retiredKeysEnvValue(env, tokenRetiredKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	keyGraceMinutes, err := optionalIntEnvValue(env, tokenKeyGraceMinutes, defaultTokenKeyGraceMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	leewaySeconds, err := optionalIntEnvValue(env, tokenLeewaySeconds, defaultTokenLeewaySeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	mailConfig, err := readMailConfig(env)
+	if err != nil {
+		return nil, err
+	}
+
+	loginThrottleConfig, err := readLoginThrottleConfig(env)
+	if err != nil {
+		return nil, err
+	}
+
+	presenceConfig, err := readPresenceConfig(env)
+	if err != nil {
+		return nil, err
+	}
+
+	playerRequestsLimit, err := optionalIntEnvValue(env, playerRequestsPerMinute, defaultPlayerRequestsPerMinute)
+	if err != nil {
+		return nil, err
+	}
+
+	botRequestsLimit, err := optionalIntEnvValue(env, botRequestsPerMinute, defaultBotRequestsPerMinute)
+	if err != nil {
+		return nil, err
+	}
+
+	botGamesLimit, err := optionalIntEnvValue(env, botGamesPerHour, defaultBotGamesPerHour)
+	if err != nil {
+		return nil, err
+	}
+
+	chatMessagesLimit, err := optionalIntEnvValue(env, chatMessagesPerMinute, defaultChatMessagesPerMinute)
+	if err != nil {
+		return nil, err
+	}
+
+	coolingOffDays, err := optionalIntEnvValue(
+		env,
+		accountDeletionCoolingOffDays,
+		defaultAccountDeletionCoolingOffDays,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	publicUrl := strings.TrimRight(optionalEnvValue(env, appPublicUrl, "http://localhost:"+appPort), "/")
+
 	return &Config{
-		AppPort:     appPort,
-		HandlerMode: apiMode,
+		AppPort:                   appPort,
+		AppPublicUrl:              publicUrl,
+		PasswordResetUrl:          optionalEnvValue(env, passwordResetUrl, publicUrl+"/auth/password/reset"),
+		AvatarDir:                 optionalEnvValue(env, avatarDir, defaultAvatarDir),
+		AccountDeletionCoolingOff: time.Duration(coolingOffDays) * 24 * time.Hour,
+		ChatProfanityWords:        listEnvValue(env, chatProfanityWords),
+		HandlerMode:               apiMode,
 		DbConfig: DbConfig{
 			Host:     dbHost,
 			Port:     dbPort,
@@ -105,8 +316,102 @@ func (c *Config) Init(envFilePath string) (*Config, error) {
 			SslMode:  "disable",
 		},
 		AuthConfig: AuthConfig{
-			TokenSigningKey: authTokenSigningKey,
+			TokenSigningKey:     authTokenSigningKey,
+			TokenKeysDir:        env[tokenKeysDir],
+			TokenActiveKeyId:    env[tokenActiveKeyId],
+			TokenRetiredKeys:    retiredKeys,
+			TokenKeyGracePeriod: time.Duration(keyGraceMinutes) * time.Minute,
+			TokenIssuer:         optionalEnvValue(env, tokenIssuer, defaultTokenIssuer),
+			TokenAudience:       optionalEnvValue(env, tokenAudience, defaultTokenAudience),
+			TokenLeeway:         time.Duration(leewaySeconds) * time.Second,
+			TotpIssuer:          optionalEnvValue(env, totpIssuer, defaultTotpIssuer),
 		},
+		MailConfig: *mailConfig,
+		RateLimitConfig: RateLimitConfig{
+			PlayerRequestsPerMinute: playerRequestsLimit,
+			BotRequestsPerMinute:    botRequestsLimit,
+			BotGamesPerHour:         botGamesLimit,
+			ChatMessagesPerMinute:   chatMessagesLimit,
+		},
+		LoginThrottleConfig: *loginThrottleConfig,
+		PresenceConfig:      *presenceConfig,
+	}, nil
+}
+
+func readMailConfig(env map[string]string) (*MailConfig, error) {
+	mailConfig := &MailConfig{
+		Driver:    optionalEnvValue(env, mailDriver, MailDriverOutbox),
+		From:      optionalEnvValue(env, mailFrom, defaultMailFrom),
+		OutboxDir: env[mailOutboxDir],
+	}
+
+	switch mailConfig.Driver {
+	case MailDriverOutbox:
+	case MailDriverSmtp:
+		host, err := envValue(env, smtpHost)
+		if err != nil {
+			return nil, err
+		}
+		mailConfig.SmtpHost = host
+		mailConfig.SmtpPort = optionalEnvValue(env, smtpPort, defaultSmtpPort)
+		mailConfig.SmtpUser = env[smtpUser]
+		mailConfig.SmtpPassword = env[smtpPassword]
+	default:
+		return nil, fmt.Errorf("%s must be %s or %s", mailDriver, MailDriverSmtp, MailDriverOutbox)
+	}
+
+	return mailConfig, nil
+}
+
+func readLoginThrottleConfig(env map[string]string) (*LoginThrottleConfig, error) {
+	store := optionalEnvValue(env, loginThrottleStore, LoginThrottleStorePostgres)
+	if store != LoginThrottleStoreMemory && store != LoginThrottleStorePostgres {
+		return nil, fmt.Errorf(
+			"%s must be %s or %s",
+			loginThrottleStore, LoginThrottleStoreMemory, LoginThrottleStorePostgres,
+		)
+	}
+
+	accountAttempts, err := optionalIntEnvValue(env, loginThrottleAccountAttempts, defaultLoginThrottleAccountAttempts)
+	if err != nil {
+		return nil, err
+	}
+	ipAttempts, err := optionalIntEnvValue(env, loginThrottleIpAttempts, defaultLoginThrottleIpAttempts)
+	if err != nil {
+		return nil, err
+	}
+	baseLockoutSeconds, err := optionalIntEnvValue(env, loginThrottleBaseLockout, defaultLoginThrottleBaseLockout)
+	if err != nil {
+		return nil, err
+	}
+	maxLockoutMinutes, err := optionalIntEnvValue(env, loginThrottleMaxLockout, defaultLoginThrottleMaxLockout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginThrottleConfig{
+		Store:               store,
+		AccountFreeAttempts: accountAttempts,
+		IpFreeAttempts:      ipAttempts,
+		BaseLockout:         time.Duration(baseLockoutSeconds) * time.Second,
+		MaxLockout:          time.Duration(maxLockoutMinutes) * time.Minute,
+	}, nil
+}
+
+func readPresenceConfig(env map[string]string) (*PresenceConfig, error) {
+	store := optionalEnvValue(env, presenceStore, PresenceStoreMemory)
+	if store != PresenceStoreMemory && store != PresenceStorePostgres {
+		return nil, fmt.Errorf("%s must be %s or %s", presenceStore, PresenceStoreMemory, PresenceStorePostgres)
+	}
+
+	ttlMinutes, err := optionalIntEnvValue(env, presenceTtlMinutes, defaultPresenceTtlMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PresenceConfig{
+		Store: store,
+		TTL:   time.Duration(ttlMinutes) * time.Minute,
 	}, nil
 }
 
@@ -141,3 +446,62 @@ func envValue(env map[string]string, envKey string) (string, error) {
 
 	return value, nil
 }
+
+func optionalEnvValue(env map[string]string, envKey string, defaultValue string) string {
+	value, found := env[envKey]
+	if !found || value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// listEnvValue splits a comma separated value, leaving out the blank items.
+func listEnvValue(env map[string]string, envKey string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(env[envKey], ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func optionalIntEnvValue(env map[string]string, envKey string, defaultValue int) (int, error) {
+	value, found := env[envKey]
+	if !found || value == "" {
+		return defaultValue, nil
+	}
+
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", envKey)
+	}
+
+	return intValue, nil
+}
+
+// retiredKeysEnvValue parses a comma separated list of <kid>@<RFC 3339 time> pairs.
+func retiredKeysEnvValue(env map[string]string, envKey string) (map[string]time.Time, error) {
+	retired := make(map[string]time.Time)
+	for _, item := range strings.Split(env[envKey], ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		keyId, retiredAtValue, found := strings.Cut(item, "@")
+		if !found || keyId == "" {
+			return nil, fmt.Errorf("%s must be a list of <kid>@<time>", envKey)
+		}
+		retiredAt, err := time.Parse(time.RFC3339, retiredAtValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s has invalid time of key %s", envKey, keyId)
+		}
+
+		retired[keyId] = retiredAt
+	}
+
+	return retired, nil
+}
@@ -0,0 +1,32 @@
+package dictionary
+
+type AuditAction string
+
+const (
+	AuditActionRoleGrant    AuditAction = "role.grant"
+	AuditActionRoleRevoke   AuditAction = "role.revoke"
+	AuditActionPlayerBan    AuditAction = "player.ban"
+	AuditActionPlayerUnban  AuditAction = "player.unban"
+	AuditActionPointsAdjust AuditAction = "points.adjust"
+	AuditActionGameCancel   AuditAction = "game.cancel"
+	AuditActionGameFinish   AuditAction = "game.finish"
+	AuditActionGamePrizes   AuditAction = "game.prizes"
+	AuditActionChatResolve  AuditAction = "chat.resolve"
+)
+
+type AuditTarget string
+
+const (
+	AuditTargetPlayer      AuditTarget = "player"
+	AuditTargetGame        AuditTarget = "game"
+	AuditTargetChatMessage AuditTarget = "chat_message"
+)
+
+type PointsKind string
+
+const (
+	PointsKindPrize PointsKind = "prize"
+	// PointsKindRefund takes back the prizes of a cancelled game.
+	PointsKindRefund     PointsKind = "refund"
+	PointsKindAdjustment PointsKind = "adjustment"
+)
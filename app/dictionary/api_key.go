@@ -0,0 +1,19 @@
+package dictionary
+
+type ApiKeyScope string
+
+const (
+	ApiKeyScopeGameCreate ApiKeyScope = "game:create"
+	ApiKeyScopeGamePlay   ApiKeyScope = "game:play"
+)
+
+var ApiKeyScopes = []ApiKeyScope{ApiKeyScopeGameCreate, ApiKeyScopeGamePlay}
+
+type RateLimit string
+
+const (
+	RateLimitPlayerRequests RateLimit = "player-requests"
+	RateLimitBotRequests    RateLimit = "bot-requests"
+	RateLimitBotGames       RateLimit = "bot-games"
+	RateLimitChatMessages   RateLimit = "chat-messages"
+)
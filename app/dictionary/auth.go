@@ -0,0 +1,8 @@
+package dictionary
+
+type LoginFailureReason string
+
+const (
+	LoginFailureWrongCredentials LoginFailureReason = "wrong_credentials"
+	LoginFailureWrongCode        LoginFailureReason = "wrong_code"
+)
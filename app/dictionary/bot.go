@@ -0,0 +1,10 @@
+package dictionary
+
+type BotStrategy string
+
+const (
+	BotStrategyRandom    BotStrategy = "random"
+	BotStrategyFrequency BotStrategy = "frequency"
+	BotStrategyMarkov    BotStrategy = "markov"
+	BotStrategyWinStay   BotStrategy = "win-stay-lose-shift"
+)
@@ -8,3 +8,24 @@ const (
 	GameStatusStarted  GameStatus = "started"
 	GameStatusFinished GameStatus = "finished"
 )
+
+type Throw string
+
+const (
+	ThrowRock     Throw = "rock"
+	ThrowScissors Throw = "scissors"
+	ThrowPaper    Throw = "paper"
+)
+
+var Throws = []Throw{ThrowRock, ThrowScissors, ThrowPaper}
+
+// GameRuleSet tells the games apart by what is at stake, the throws beat each other the same way in all of them.
+type GameRuleSet string
+
+const (
+	GameRuleSetClassic  GameRuleSet = "classic"
+	GameRuleSetStaked   GameRuleSet = "staked"
+	GameRuleSetPractice GameRuleSet = "practice"
+)
+
+var GameRuleSets = []GameRuleSet{GameRuleSetClassic, GameRuleSetStaked, GameRuleSetPractice}
@@ -0,0 +1,25 @@
+package dictionary
+
+type LeaderboardBoard string
+
+const (
+	LeaderboardBoardPoints  LeaderboardBoard = "points"
+	LeaderboardBoardWins    LeaderboardBoard = "wins"
+	LeaderboardBoardWinRate LeaderboardBoard = "win_rate"
+)
+
+var LeaderboardBoards = []LeaderboardBoard{LeaderboardBoardPoints, LeaderboardBoardWins, LeaderboardBoardWinRate}
+
+type LeaderboardWindow string
+
+const (
+	LeaderboardWindowAllTime LeaderboardWindow = "all_time"
+	LeaderboardWindowMonthly LeaderboardWindow = "monthly"
+	LeaderboardWindowWeekly  LeaderboardWindow = "weekly"
+)
+
+var LeaderboardWindows = []LeaderboardWindow{
+	LeaderboardWindowAllTime,
+	LeaderboardWindowMonthly,
+	LeaderboardWindowWeekly,
+}
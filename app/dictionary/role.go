@@ -0,0 +1,41 @@
+package dictionary
+
+type Role string
+
+const (
+	RoleAdmin     Role = "admin"
+	RoleModerator Role = "moderator"
+)
+
+var Roles = []Role{RoleAdmin, RoleModerator}
+
+type Permission string
+
+const (
+	PermissionPlayerView   Permission = "player:view"
+	PermissionGameCancel   Permission = "game:cancel"
+	PermissionGameEdit     Permission = "game:edit"
+	PermissionPointsAdjust Permission = "points:adjust"
+	PermissionPlayerBan    Permission = "player:ban"
+	PermissionReportHandle Permission = "report:handle"
+	PermissionRoleManage   Permission = "role:manage"
+	PermissionAuditRead    Permission = "audit:read"
+)
+
+// RolePermissions grants the permissions, a player without roles has none of them.
+var RolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionPlayerView,
+		PermissionGameCancel,
+		PermissionGameEdit,
+		PermissionPointsAdjust,
+		PermissionPlayerBan,
+		PermissionReportHandle,
+		PermissionRoleManage,
+		PermissionAuditRead,
+	},
+	RoleModerator: {
+		PermissionPlayerView,
+		PermissionReportHandle,
+	},
+}
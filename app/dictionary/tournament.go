@@ -0,0 +1,42 @@
+package dictionary
+
+type TournamentType string
+
+const (
+	TournamentTypeSingleElimination TournamentType = "single-elimination"
+	TournamentTypeDoubleElimination TournamentType = "double-elimination"
+	TournamentTypeRoundRobin        TournamentType = "round-robin"
+	TournamentTypeSwiss             TournamentType = "swiss"
+)
+
+type TournamentStatus string
+
+const (
+	TournamentStatusRegistration TournamentStatus = "registration"
+	TournamentStatusRunning      TournamentStatus = "running"
+	TournamentStatusFinished     TournamentStatus = "finished"
+)
+
+type TournamentSeeding string
+
+const (
+	TournamentSeedingRandom TournamentSeeding = "random"
+	TournamentSeedingPoints TournamentSeeding = "points"
+)
+
+type TournamentBracket string
+
+const (
+	TournamentBracketUpper  TournamentBracket = "upper"
+	TournamentBracketLower  TournamentBracket = "lower"
+	TournamentBracketFinal  TournamentBracket = "final"
+	TournamentBracketLeague TournamentBracket = "league"
+)
+
+type TournamentMatchStatus string
+
+const (
+	TournamentMatchStatusPending  TournamentMatchStatus = "pending"
+	TournamentMatchStatusPlaying  TournamentMatchStatus = "playing"
+	TournamentMatchStatusFinished TournamentMatchStatus = "finished"
+)
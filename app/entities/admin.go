@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"time"
+)
+
+// AuditEntry records an action of the staff, the actor is empty for the command line.
+type AuditEntry struct {
+	ID         uuid.UUID              `gorm:"type:uuid;primaryKey"`
+	ActorID    *uuid.UUID             `gorm:"type:uuid;null;index"`
+	Action     dictionary.AuditAction `gorm:"size:32;not null"`
+	TargetType dictionary.AuditTarget `gorm:"size:16;not null"`
+	TargetID   uuid.UUID              `gorm:"type:uuid;not null;index"`
+	Reason     string                 `gorm:"size:512;not null;default:''"`
+	// Details keeps the parameters of the action as a JSON object.
+	Details   string    `gorm:"type:text;not null;default:'{}'"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+func NewAuditEntry(
+	actorId *uuid.UUID,
+	action dictionary.AuditAction,
+	targetType dictionary.AuditTarget,
+	targetId uuid.UUID,
+	reason string,
+	details map[string]interface{},
+) *AuditEntry {
+	encoded, err := json.Marshal(details)
+	if err != nil || details == nil {
+		encoded = []byte("{}")
+	}
+
+	return &AuditEntry{
+		ID:         uuid.New(),
+		ActorID:    actorId,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetId,
+		Reason:     reason,
+		Details:    string(encoded),
+	}
+}
+
+// AddDetails merges the outcome of the action into the details, it is known only once the action is done.
+func (a *AuditEntry) AddDetails(details map[string]interface{}) {
+	merged := make(map[string]interface{}, len(details))
+	_ = json.Unmarshal([]byte(a.Details), &merged)
+	for key, value := range details {
+		merged[key] = value
+	}
+
+	if encoded, err := json.Marshal(merged); err == nil {
+		a.Details = string(encoded)
+	}
+}
+
+type AuditFilter struct {
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+	Before   *time.Time
+	Limit    int
+}
+
+// PointsTransaction is an entry of the points ledger, every change of the points of a player writes one.
+type PointsTransaction struct {
+	ID       uuid.UUID             `gorm:"type:uuid;primaryKey"`
+	PlayerID uuid.UUID             `gorm:"type:uuid;not null;index"`
+	Amount   int                   `gorm:"not null"`
+	Kind     dictionary.PointsKind `gorm:"size:16;not null"`
+	GameID   *uuid.UUID            `gorm:"type:uuid;null;index"`
+	// ActorID is the admin behind an adjustment or a refund.
+	ActorID   *uuid.UUID `gorm:"type:uuid;null"`
+	Reason    string     `gorm:"size:512;not null;default:''"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func NewPointsTransaction(
+	playerId uuid.UUID,
+	amount int,
+	kind dictionary.PointsKind,
+	gameId, actorId *uuid.UUID,
+	reason string,
+) *PointsTransaction {
+	return &PointsTransaction{
+		ID:       uuid.New(),
+		PlayerID: playerId,
+		Amount:   amount,
+		Kind:     kind,
+		GameID:   gameId,
+		ActorID:  actorId,
+		Reason:   reason,
+	}
+}
+
+// Ban keeps a player out, permanently when ExpiresAt is empty.
+type Ban struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	PlayerID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Reason    string     `gorm:"size:512;not null"`
+	IssuedBy  *uuid.UUID `gorm:"type:uuid;null"`
+	ExpiresAt *time.Time `gorm:"type:timestamp;null"`
+	RevokedAt *time.Time `gorm:"type:timestamp;null"`
+	RevokedBy *uuid.UUID `gorm:"type:uuid;null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func NewBan(playerId uuid.UUID, reason string, issuedBy *uuid.UUID, expiresAt *time.Time) *Ban {
+	return &Ban{
+		ID:        uuid.New(),
+		PlayerID:  playerId,
+		Reason:    reason,
+		IssuedBy:  issuedBy,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (b *Ban) IsActive(now time.Time) bool {
+	return b.RevokedAt == nil && (b.ExpiresAt == nil || now.Before(*b.ExpiresAt))
+}
+
+// PlayerPoints is the balance of a player with the latest entries of the ledger.
+type PlayerPoints struct {
+	Balance      uint
+	Transactions []PointsTransaction
+}
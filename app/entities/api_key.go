@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"strings"
+	"time"
+)
+
+const apiKeyScopesSeparator = " "
+
+type ApiKey struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	OwnerID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	BotID      uuid.UUID  `gorm:"type:uuid;not null"`
+	Name       string     `gorm:"size:100;not null"`
+	Prefix     string     `gorm:"size:16;not null"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex"`
+	Scopes     string     `gorm:"size:255;not null"`
+	LastUsedAt *time.Time `gorm:"type:timestamp;null"`
+	RevokedAt  *time.Time `gorm:"type:timestamp;null"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+func NewApiKey(ownerId, botId uuid.UUID, name, prefix, keyHash string, scopes []dictionary.ApiKeyScope) *ApiKey {
+	scopeNames := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scopeNames = append(scopeNames, string(scope))
+	}
+
+	return &ApiKey{
+		ID:      uuid.New(),
+		OwnerID: ownerId,
+		BotID:   botId,
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: keyHash,
+		Scopes:  strings.Join(scopeNames, apiKeyScopesSeparator),
+	}
+}
+
+func (k *ApiKey) ScopeList() []dictionary.ApiKeyScope {
+	scopes := make([]dictionary.ApiKeyScope, 0)
+	for _, scope := range strings.Fields(k.Scopes) {
+		scopes = append(scopes, dictionary.ApiKeyScope(scope))
+	}
+
+	return scopes
+}
+
+func (k *ApiKey) HasScope(scope dictionary.ApiKeyScope) bool {
+	for _, keyScope := range k.ScopeList() {
+		if keyScope == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k *ApiKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ChatMessage is said in the chat of a game, or in the lobby when it has no game.
+type ChatMessage struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	GameID    *uuid.UUID `gorm:"type:uuid;null;index:idx_chat_message_room"`
+	PlayerID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Body      string     `gorm:"size:500;not null"`
+	HiddenAt  *time.Time `gorm:"type:timestamp;null"`
+	HiddenBy  *uuid.UUID `gorm:"type:uuid;null"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null;index:idx_chat_message_room"`
+	Player    Player     `gorm:"foreignKey:PlayerID"`
+}
+
+func NewChatMessage(gameId *uuid.UUID, playerId uuid.UUID, body string) *ChatMessage {
+	return &ChatMessage{
+		ID:        uuid.New(),
+		GameID:    gameId,
+		PlayerID:  playerId,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+}
+
+// ChatReport flags a message for the moderators, a player reports a message once.
+type ChatReport struct {
+	ID         uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	MessageID  uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_chat_report"`
+	ReporterID uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_chat_report"`
+	Reason     string      `gorm:"size:512;not null;default:''"`
+	ResolvedAt *time.Time  `gorm:"type:timestamp;null;index"`
+	ResolvedBy *uuid.UUID  `gorm:"type:uuid;null"`
+	CreatedAt  time.Time   `gorm:"autoCreateTime"`
+	Message    ChatMessage `gorm:"foreignKey:MessageID"`
+}
+
+func NewChatReport(messageId, reporterId uuid.UUID, reason string) *ChatReport {
+	return &ChatReport{
+		ID:         uuid.New(),
+		MessageID:  messageId,
+		ReporterID: reporterId,
+		Reason:     reason,
+	}
+}
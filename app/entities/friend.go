@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"time"
+)
+
+// Friendship is a friend request from the requester, the players are friends once the addressee accepts it.
+// There is at most one row for a pair of players, whichever direction.
+type Friendship struct {
+	RequesterID uuid.UUID                   `gorm:"type:uuid;primaryKey"`
+	AddresseeID uuid.UUID                   `gorm:"type:uuid;primaryKey;index"`
+	Status      dictionary.FriendshipStatus `gorm:"type:VARCHAR(20);check:status IN ('pending', 'accepted')"`
+	AcceptedAt  *time.Time                  `gorm:"type:timestamp;null"`
+	CreatedAt   time.Time                   `gorm:"autoCreateTime"`
+	Requester   Player                      `gorm:"foreignKey:RequesterID"`
+	Addressee   Player                      `gorm:"foreignKey:AddresseeID"`
+}
+
+func NewFriendship(requesterId, addresseeId uuid.UUID) *Friendship {
+	return &Friendship{
+		RequesterID: requesterId,
+		AddresseeID: addresseeId,
+		Status:      dictionary.FriendshipStatusPending,
+	}
+}
+
+// Other returns the player on the other side of the friendship.
+func (f *Friendship) Other(playerId uuid.UUID) *Player {
+	if f.RequesterID == playerId {
+		return &f.Addressee
+	}
+
+	return &f.Requester
+}
+
+// Block keeps the blocked player from sending friend requests to the player and joining their games.
+type Block struct {
+	PlayerID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	BlockedID uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	Blocked   Player    `gorm:"foreignKey:BlockedID"`
+}
+
+// Friend is an accepted friendship with what the player's friend is up to.
+type Friend struct {
+	Player   *Player
+	Since    time.Time
+	Presence PlayerPresence
+}
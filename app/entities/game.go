@@ -11,14 +11,21 @@ type Game struct {
 	StartedAt  time.Time             `gorm:"type:timestamp"`
 	FinishedAt time.Time             `gorm:"type:timestamp"`
 	Status     dictionary.GameStatus `gorm:"type:VARCHAR(20);check:status IN ('planned', 'waiting', 'started', 'finished')"`
-	Players    []Player              `gorm:"many2many:game_players"`
-	Prizes     []GamePrize           `gorm:"foreignKey:GameID"`
-	Result     []GameResult          `gorm:"foreignKey:GameID"`
+	Round      uint                  `gorm:"not null;default:0"`
+	BotOwned   bool                  `gorm:"not null;default:false"`
+	// CancelledAt marks a game voided by an admin, it is finished and its prizes are taken back.
+	CancelledAt  *time.Time   `gorm:"type:timestamp;null"`
+	CancelReason string       `gorm:"size:512;not null;default:''"`
+	Players      []Player     `gorm:"many2many:game_players"`
+	Prizes       []GamePrize  `gorm:"foreignKey:GameID"`
+	Result       []GameResult `gorm:"foreignKey:GameID"`
+	Moves        []GameMove   `gorm:"foreignKey:GameID"`
 }
 
 func NewGame(players []Player) *Game {
 	return &Game{
 		ID:      uuid.New(),
+		Status:  dictionary.GameStatusWaiting,
 		Players: players,
 	}
 }
@@ -26,6 +33,7 @@ func NewGame(players []Player) *Game {
 type GamePlayer struct {
 	PlayerID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_game_player"`
 	GameID   uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_game_player"`
+	Ready    bool      `gorm:"not null;default:false"`
 }
 
 type GamePrize struct {
@@ -39,3 +47,22 @@ type GameResult struct {
 	PlayerID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_game_result"`
 	Place    uint8     `gorm:"type:int;uniqueIndex:idx_game_result"`
 }
+
+type GameMove struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	GameID    uuid.UUID        `gorm:"type:uuid;uniqueIndex:idx_game_move"`
+	PlayerID  uuid.UUID        `gorm:"type:uuid;uniqueIndex:idx_game_move"`
+	Round     uint             `gorm:"not null;uniqueIndex:idx_game_move"`
+	Throw     dictionary.Throw `gorm:"type:VARCHAR(20);check:throw IN ('rock', 'scissors', 'paper')"`
+	CreatedAt time.Time        `gorm:"autoCreateTime"`
+}
+
+func NewGameMove(gameId, playerId uuid.UUID, round uint, throw dictionary.Throw) *GameMove {
+	return &GameMove{
+		ID:       uuid.New(),
+		GameID:   gameId,
+		PlayerID: playerId,
+		Round:    round,
+		Throw:    throw,
+	}
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"time"
+)
+
+// LeaderboardEntry is the standing of a player in a period of a window, the all-time period starts at zero time.
+// The entries of the players of a game are refreshed when it ends, the boards only sort them.
+type LeaderboardEntry struct {
+	Window      dictionary.LeaderboardWindow `gorm:"column:time_window;type:VARCHAR(20);primaryKey"`
+	PeriodStart time.Time                    `gorm:"type:timestamp;primaryKey"`
+	PlayerID    uuid.UUID                    `gorm:"type:uuid;primaryKey;index"`
+	// Points are the balance for all time and the points won or lost in the period otherwise.
+	Points      int       `gorm:"not null;default:0"`
+	GamesPlayed uint      `gorm:"not null;default:0"`
+	GamesWon    uint      `gorm:"not null;default:0"`
+	WinRate     float64   `gorm:"not null;default:0"`
+	UpdatedAt   time.Time `gorm:"type:timestamp"`
+	Player      Player    `gorm:"foreignKey:PlayerID"`
+}
+
+func NewLeaderboardEntry(
+	window dictionary.LeaderboardWindow,
+	periodStart time.Time,
+	playerId uuid.UUID,
+	points int,
+	played, won uint,
+) *LeaderboardEntry {
+	return &LeaderboardEntry{
+		Window:      window,
+		PeriodStart: periodStart,
+		PlayerID:    playerId,
+		Points:      points,
+		GamesPlayed: played,
+		GamesWon:    won,
+		WinRate:     winRate(won, played),
+	}
+}
+
+// RankedEntry shares the rank with the entries of the same score, a zero rank is off the board.
+type RankedEntry struct {
+	Rank  uint
+	Entry LeaderboardEntry
+}
+
+type Leaderboard struct {
+	Board       dictionary.LeaderboardBoard
+	Window      dictionary.LeaderboardWindow
+	PeriodStart time.Time
+	Entries     []RankedEntry
+	// Own is the caller wherever they are, on the board or not.
+	Own RankedEntry
+}
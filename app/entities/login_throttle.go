@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"time"
+)
+
+// LoginThrottle counts the recent failed logins of an account or of an IP address.
+type LoginThrottle struct {
+	Key          string     `gorm:"size:320;primaryKey"`
+	Failures     int        `gorm:"not null;default:0"`
+	LastFailedAt time.Time  `gorm:"type:timestamp;not null"`
+	LockedUntil  *time.Time `gorm:"type:timestamp;null"`
+}
+
+// RetryAfter is how long the key stays locked out.
+func (l *LoginThrottle) RetryAfter(now time.Time) time.Duration {
+	if l.LockedUntil == nil || !now.Before(*l.LockedUntil) {
+		return 0
+	}
+
+	return l.LockedUntil.Sub(now)
+}
+
+// LoginFailure is the audit record of a failed login.
+type LoginFailure struct {
+	ID        uuid.UUID                     `gorm:"type:uuid;primaryKey"`
+	PlayerID  *uuid.UUID                    `gorm:"type:uuid;null;index"`
+	Login     string                        `gorm:"size:255;not null"`
+	IP        string                        `gorm:"size:45;not null;default:''"`
+	UserAgent string                        `gorm:"size:512;not null;default:''"`
+	Reason    dictionary.LoginFailureReason `gorm:"size:32;not null"`
+	CreatedAt time.Time                     `gorm:"autoCreateTime;index"`
+}
+
+func NewLoginFailure(
+	playerId *uuid.UUID,
+	login, ip, userAgent string,
+	reason dictionary.LoginFailureReason,
+) *LoginFailure {
+	return &LoginFailure{
+		ID:        uuid.New(),
+		PlayerID:  playerId,
+		Login:     login,
+		IP:        ip,
+		UserAgent: userAgent,
+		Reason:    reason,
+	}
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PasswordResetToken is mailed to a player who forgot the password, only its hash is stored.
+type PasswordResetToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	PlayerID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp;null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func NewPasswordResetToken(playerId uuid.UUID, tokenHash string, expiresAt time.Time) *PasswordResetToken {
+	return &PasswordResetToken{
+		ID:        uuid.New(),
+		PlayerID:  playerId,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (p *PasswordResetToken) IsUsable(now time.Time) bool {
+	return p.UsedAt == nil && now.Before(p.ExpiresAt)
+}
@@ -1,25 +1,121 @@
 package entities
 
 import (
+	"fmt"
 	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"strings"
 	"time"
 )
 
+const (
+	botEmailDomain     = "bot.knb.local"
+	deletedEmailDomain = "deleted.knb.local"
+	DeletedPlayerName  = "Deleted player"
+
+	DefaultLocale = "en"
+)
+
 type Player struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey'"`
 	Email       string    `gorm:"size:255;unique;not null"`
 	Password    string    `gorm:"size:255;not null"`
 	DisplayName string    `gorm:"size:255;null"`
-	Points      uint      `gorm:"not null;default:0"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	// DisplayNameKey makes the display names of the players unique regardless of the case, bots have none.
+	DisplayNameKey *string `gorm:"size:255;null;uniqueIndex"`
+	AvatarURL      string  `gorm:"size:1024;not null;default:''"`
+	// AvatarFile is the name of the uploaded avatar in the local storage, empty for an external URL.
+	AvatarFile  string                 `gorm:"size:128;not null;default:''"`
+	Locale      string                 `gorm:"size:16;not null;default:'en'"`
+	Points      uint                   `gorm:"not null;default:0"`
+	IsBot       bool                   `gorm:"not null;default:false"`
+	BotStrategy dictionary.BotStrategy `gorm:"type:VARCHAR(30);null"`
+	OwnerID     *uuid.UUID             `gorm:"type:uuid;null;index"`
+	// EmailVerifiedAt is empty until the player follows the link sent on registration.
+	EmailVerifiedAt *time.Time `gorm:"type:timestamp;null"`
+	// DeletionScheduledAt is when the player asked to delete the account and the cooling-off period ends.
+	DeletionScheduledAt *time.Time `gorm:"type:timestamp;null;index"`
+	// AnonymisedAt marks a deleted account, the row stays for the games the player took part in.
+	AnonymisedAt *time.Time `gorm:"type:timestamp;null"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
 }
 
 func NewPlayer(email, password, displayName string) *Player {
+	player := &Player{
+		ID:       uuid.New(),
+		Email:    email,
+		Password: password,
+		Locale:   DefaultLocale,
+	}
+	if displayName == "" {
+		displayName = fmt.Sprintf("Player %s", player.ID.String()[:8])
+	}
+	player.SetDisplayName(displayName)
+
+	return player
+}
+
+func NewBot(strategy dictionary.BotStrategy) *Player {
+	id := uuid.New()
+
 	return &Player{
-		ID:          uuid.New(),
-		Email:       email,
-		Password:    password,
+		ID:          id,
+		Email:       fmt.Sprintf("%s@%s", id, botEmailDomain),
+		DisplayName: fmt.Sprintf("Bot %s (%s)", id.String()[:8], strategy),
+		IsBot:       true,
+		BotStrategy: strategy,
+	}
+}
+
+// NewOwnedBot names the bot of an api key after its owner, the name of the key is free text and stays on the key.
+func NewOwnedBot(owner *Player) *Player {
+	id := uuid.New()
+	displayName := fmt.Sprintf("Bot %s", id.String()[:8])
+	if owner.DisplayName != "" {
+		displayName = fmt.Sprintf("%s of %s", displayName, owner.DisplayName)
+	}
+
+	return &Player{
+		ID:          id,
+		Email:       fmt.Sprintf("%s@%s", id, botEmailDomain),
 		DisplayName: displayName,
+		IsBot:       true,
+		OwnerID:     &owner.ID,
+	}
+}
+
+// SetDisplayName keeps the uniqueness key in sync, the names are compared case-insensitively.
+func (p *Player) SetDisplayName(displayName string) {
+	p.DisplayName = displayName
+	if p.IsBot {
+		p.DisplayNameKey = nil
+		return
 	}
+
+	key := strings.ToLower(displayName)
+	p.DisplayNameKey = &key
+}
+
+func (p *Player) IsBuiltInBot() bool {
+	return p.IsBot && p.BotStrategy != ""
+}
+
+// CanPlayStaked tells whether the player may join the games with prizes, built-in bots have no email to verify.
+// A bot of an api key plays on behalf of its owner, it is the owner who must be verified.
+func (p *Player) CanPlayStaked() bool {
+	return p.IsBuiltInBot() || p.EmailVerifiedAt != nil
+}
+
+// Anonymise scrubs the personal data, the id stays so the games of the other players keep their opponent.
+func (p *Player) Anonymise(now time.Time) {
+	p.Email = fmt.Sprintf("%s@%s", p.ID, deletedEmailDomain)
+	p.Password = ""
+	p.DisplayName = DeletedPlayerName
+	p.DisplayNameKey = nil
+	p.AvatarURL, p.AvatarFile = "", ""
+	p.Locale = DefaultLocale
+	p.EmailVerifiedAt = nil
+	p.DeletionScheduledAt = nil
+	p.AnonymisedAt = &now
 }
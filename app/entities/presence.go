@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Presence is the last sign of life of a player and the game they were playing then.
+type Presence struct {
+	PlayerID   uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	GameID     *uuid.UUID `gorm:"type:uuid;null"`
+	LastSeenAt time.Time  `gorm:"type:timestamp;not null;index"`
+}
+
+// PlayerPresence is what the other players see, the game only while the player is online.
+type PlayerPresence struct {
+	Online     bool
+	LastSeenAt *time.Time
+	GameID     *uuid.UUID
+}
+
+// OnlinePlayer is a player who is around to join a game.
+type OnlinePlayer struct {
+	Player   *Player
+	Presence PlayerPresence
+}
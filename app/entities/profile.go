@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// ProfileUpdate holds the profile fields a player changes, nil ones are left as they are.
+type ProfileUpdate struct {
+	DisplayName *string
+	AvatarURL   *string
+	Locale      *string
+}
+
+// PlayerExport is what the service keeps about a player, handed out to them on request.
+type PlayerExport struct {
+	Player       *Player
+	Roles        Roles
+	Games        []Game
+	Points       []PointsTransaction
+	ChatMessages []ChatMessage
+	ChatReports  []ChatReport
+	ExportedAt   time.Time
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"slices"
+	"time"
+)
+
+// PlayerRole grants a role to a player, a player may have several.
+type PlayerRole struct {
+	PlayerID  uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	Role      dictionary.Role `gorm:"size:32;primaryKey"`
+	GrantedBy *uuid.UUID      `gorm:"type:uuid;null"`
+	CreatedAt time.Time       `gorm:"autoCreateTime"`
+}
+
+type Roles []dictionary.Role
+
+func (r Roles) Can(permission dictionary.Permission) bool {
+	for _, role := range r {
+		if slices.Contains(dictionary.RolePermissions[role], permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Roles) Has(role dictionary.Role) bool {
+	return slices.Contains(r, role)
+}
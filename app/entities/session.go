@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"crypto"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Session is a login of a player and the family of the refresh tokens issued for it.
+type Session struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	PlayerID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UserAgent  string     `gorm:"size:512;not null;default:''"`
+	IP         string     `gorm:"size:45;not null;default:''"`
+	LastUsedAt time.Time  `gorm:"type:timestamp;not null"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp;not null"`
+	RevokedAt  *time.Time `gorm:"type:timestamp;null"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+func NewSession(playerId uuid.UUID, userAgent, ip string, expiresAt time.Time) *Session {
+	return &Session{
+		ID:         uuid.New(),
+		PlayerID:   playerId,
+		UserAgent:  userAgent,
+		IP:         ip,
+		LastUsedAt: time.Now(),
+		ExpiresAt:  expiresAt,
+	}
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken can be exchanged once, a second use means it leaked.
+type RefreshToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	SessionID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp;null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func NewRefreshToken(sessionId uuid.UUID, tokenHash string, expiresAt time.Time) *RefreshToken {
+	return &RefreshToken{
+		ID:        uuid.New(),
+		SessionID: sessionId,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// AuthTokens are issued on login and on every refresh.
+type AuthTokens struct {
+	SessionID        uuid.UUID
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// TokenPublicKey is published in the JWKS so other services can verify player tokens.
+type TokenPublicKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
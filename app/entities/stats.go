@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"time"
+)
+
+// PlayerStats sums up the finished games of a player, every game is added as it ends and a cancelled one is dropped
+// by a rebuild.
+type PlayerStats struct {
+	PlayerID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	GamesPlayed      uint      `gorm:"not null;default:0"`
+	GamesWon         uint      `gorm:"not null;default:0"`
+	CurrentWinStreak uint      `gorm:"not null;default:0"`
+	LongestWinStreak uint      `gorm:"not null;default:0"`
+	// MultiPlayerGames are the games of more than two players, PlaceSum adds up the places taken in them.
+	MultiPlayerGames uint                 `gorm:"not null;default:0"`
+	PlaceSum         uint                 `gorm:"not null;default:0"`
+	UpdatedAt        time.Time            `gorm:"type:timestamp"`
+	RuleSets         []PlayerRuleSetStats `gorm:"foreignKey:PlayerID"`
+	Throws           []PlayerThrowStats   `gorm:"foreignKey:PlayerID"`
+}
+
+func (s *PlayerStats) WinRate() float64 {
+	return winRate(s.GamesWon, s.GamesPlayed)
+}
+
+// AveragePlace is nil until the player has played a game of more than two players.
+func (s *PlayerStats) AveragePlace() *float64 {
+	if s.MultiPlayerGames == 0 {
+		return nil
+	}
+	average := float64(s.PlaceSum) / float64(s.MultiPlayerGames)
+
+	return &average
+}
+
+type PlayerRuleSetStats struct {
+	PlayerID    uuid.UUID              `gorm:"type:uuid;primaryKey"`
+	RuleSet     dictionary.GameRuleSet `gorm:"type:VARCHAR(20);primaryKey"`
+	GamesPlayed uint                   `gorm:"not null;default:0"`
+	GamesWon    uint                   `gorm:"not null;default:0"`
+}
+
+func (s *PlayerRuleSetStats) WinRate() float64 {
+	return winRate(s.GamesWon, s.GamesPlayed)
+}
+
+type PlayerThrowStats struct {
+	PlayerID uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	Throw    dictionary.Throw `gorm:"type:VARCHAR(20);primaryKey"`
+	Count    uint             `gorm:"not null;default:0"`
+}
+
+// PlayerOpponentStats is the record of a player against another one, over the games both of them got a place in.
+type PlayerOpponentStats struct {
+	PlayerID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OpponentID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	GamesPlayed uint      `gorm:"not null;default:0"`
+	Wins        uint      `gorm:"not null;default:0"`
+	Losses      uint      `gorm:"not null;default:0"`
+	Draws       uint      `gorm:"not null;default:0"`
+}
+
+func winRate(won, played uint) float64 {
+	if played == 0 {
+		return 0
+	}
+
+	return float64(won) / float64(played)
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"time"
+)
+
+type Tournament struct {
+	ID            uuid.UUID                    `gorm:"type:uuid;primaryKey"`
+	Name          string                       `gorm:"size:255;not null"`
+	Type          dictionary.TournamentType    `gorm:"type:VARCHAR(30);not null"`
+	Seeding       dictionary.TournamentSeeding `gorm:"type:VARCHAR(20);not null"`
+	Status        dictionary.TournamentStatus  `gorm:"type:VARCHAR(20);not null"`
+	OwnerID       uuid.UUID                    `gorm:"type:uuid;not null"`
+	NoShowMinutes uint                         `gorm:"not null"`
+	Rounds        uint                         `gorm:"not null;default:0"`
+	WinnerID      *uuid.UUID                   `gorm:"type:uuid;null"`
+	CreatedAt     time.Time                    `gorm:"autoCreateTime"`
+	StartedAt     *time.Time                   `gorm:"type:timestamp;null"`
+	FinishedAt    *time.Time                   `gorm:"type:timestamp;null"`
+	Players       []Player                     `gorm:"many2many:tournament_players"`
+	Matches       []TournamentMatch            `gorm:"foreignKey:TournamentID"`
+}
+
+func NewTournament(
+	ownerId uuid.UUID,
+	name string,
+	tournamentType dictionary.TournamentType,
+	seeding dictionary.TournamentSeeding,
+	noShowMinutes uint,
+	rounds uint,
+) *Tournament {
+	return &Tournament{
+		ID:            uuid.New(),
+		Name:          name,
+		Type:          tournamentType,
+		Seeding:       seeding,
+		Status:        dictionary.TournamentStatusRegistration,
+		OwnerID:       ownerId,
+		NoShowMinutes: noShowMinutes,
+		Rounds:        rounds,
+	}
+}
+
+type TournamentPlayer struct {
+	TournamentID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_tournament_player"`
+	PlayerID     uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_tournament_player"`
+	Seed         uint      `gorm:"not null;default:0"`
+}
+
+type TournamentMatch struct {
+	ID               uuid.UUID                        `gorm:"type:uuid;primaryKey"`
+	TournamentID     uuid.UUID                        `gorm:"type:uuid;not null;index"`
+	Bracket          dictionary.TournamentBracket     `gorm:"type:VARCHAR(20);not null"`
+	Round            uint                             `gorm:"not null"`
+	Position         uint                             `gorm:"not null"`
+	PlayerOneID      *uuid.UUID                       `gorm:"type:uuid;null"`
+	PlayerTwoID      *uuid.UUID                       `gorm:"type:uuid;null"`
+	GameID           *uuid.UUID                       `gorm:"type:uuid;null;index"`
+	WinnerID         *uuid.UUID                       `gorm:"type:uuid;null"`
+	LoserID          *uuid.UUID                       `gorm:"type:uuid;null"`
+	Status           dictionary.TournamentMatchStatus `gorm:"type:VARCHAR(20);not null"`
+	NextMatchID      *uuid.UUID                       `gorm:"type:uuid;null"`
+	NextSlot         uint8                            `gorm:"not null;default:0"`
+	LoserNextMatchID *uuid.UUID                       `gorm:"type:uuid;null"`
+	LoserNextSlot    uint8                            `gorm:"not null;default:0"`
+	Deadline         *time.Time                       `gorm:"type:timestamp;null"`
+}
+
+func NewTournamentMatch(
+	tournamentId uuid.UUID,
+	bracket dictionary.TournamentBracket,
+	round, position uint,
+) *TournamentMatch {
+	return &TournamentMatch{
+		ID:           uuid.New(),
+		TournamentID: tournamentId,
+		Bracket:      bracket,
+		Round:        round,
+		Position:     position,
+		Status:       dictionary.TournamentMatchStatusPending,
+	}
+}
+
+func (m *TournamentMatch) Players() []uuid.UUID {
+	players := make([]uuid.UUID, 0, 2)
+	if m.PlayerOneID != nil {
+		players = append(players, *m.PlayerOneID)
+	}
+	if m.PlayerTwoID != nil {
+		players = append(players, *m.PlayerTwoID)
+	}
+
+	return players
+}
+
+func (m *TournamentMatch) SetPlayer(slot uint8, playerId uuid.UUID) {
+	if slot == 1 {
+		m.PlayerOneID = &playerId
+	} else {
+		m.PlayerTwoID = &playerId
+	}
+}
+
+// TournamentStanding is computed from the results of the tournament games and is not stored.
+type TournamentStanding struct {
+	PlayerID   uuid.UUID
+	Seed       uint
+	Played     uint
+	Wins       uint
+	Draws      uint
+	Losses     uint
+	Byes       uint
+	Score      float64
+	Buchholz   float64
+	HeadToHead float64
+	Opponents  []uuid.UUID
+}
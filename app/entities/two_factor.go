@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TwoFactor is the TOTP secret of a player, it protects the logins only after ConfirmedAt is set.
+type TwoFactor struct {
+	PlayerID    uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Secret      string     `gorm:"size:64;not null"`
+	ConfirmedAt *time.Time `gorm:"type:timestamp;null"`
+	// LastUsedStep is the time step of the last accepted code, a code is never accepted twice.
+	LastUsedStep int64     `gorm:"not null;default:0"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func NewTwoFactor(playerId uuid.UUID, secret string) *TwoFactor {
+	return &TwoFactor{
+		PlayerID: playerId,
+		Secret:   secret,
+	}
+}
+
+func (t *TwoFactor) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// RecoveryCode replaces a TOTP code once when the player has lost the authenticator.
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	PlayerID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	CodeHash  string     `gorm:"size:64;not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp;null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func NewRecoveryCode(playerId uuid.UUID, codeHash string) *RecoveryCode {
+	return &RecoveryCode{
+		ID:       uuid.New(),
+		PlayerID: playerId,
+		CodeHash: codeHash,
+	}
+}
+
+// TwoFactorEnrollment is shown to the player once, to be added to an authenticator app.
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// LoginChallenge is issued instead of the tokens when the login needs a second factor.
+type LoginChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,19 @@
+package errors
+
+import "time"
+
+const AccountBannedCode = "account_banned"
+
+// BannedError keeps a banned player out, ExpiresAt is empty for a permanent ban.
+type BannedError struct {
+	message   string
+	ExpiresAt *time.Time
+}
+
+func NewBannedError(message string, expiresAt *time.Time) *BannedError {
+	return &BannedError{message, expiresAt}
+}
+
+func (e *BannedError) Error() string {
+	return e.message
+}
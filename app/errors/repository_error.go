@@ -47,3 +47,15 @@ func NewBadRequestError(message string) *BadRequestError {
 func (e *BadRequestError) Error() string {
 	return e.message
 }
+
+type ForbiddenError struct {
+	message string
+}
+
+func NewForbiddenError(message string) *ForbiddenError {
+	return &ForbiddenError{message}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.message
+}
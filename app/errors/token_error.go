@@ -0,0 +1,72 @@
+package errors
+
+const (
+	TokenExpiredCode          = "token_expired"
+	TokenMalformedCode        = "token_malformed"
+	TokenSignatureInvalidCode = "token_signature_invalid"
+	TokenClaimsInvalidCode    = "token_claims_invalid"
+	SessionRevokedCode        = "session_revoked"
+)
+
+// TokenExpiredError tells the client to refresh the access token.
+type TokenExpiredError struct {
+	message string
+}
+
+func NewTokenExpiredError(message string) *TokenExpiredError {
+	return &TokenExpiredError{message}
+}
+
+func (e *TokenExpiredError) Error() string {
+	return e.message
+}
+
+type TokenMalformedError struct {
+	message string
+}
+
+func NewTokenMalformedError(message string) *TokenMalformedError {
+	return &TokenMalformedError{message}
+}
+
+func (e *TokenMalformedError) Error() string {
+	return e.message
+}
+
+type TokenSignatureError struct {
+	message string
+}
+
+func NewTokenSignatureError(message string) *TokenSignatureError {
+	return &TokenSignatureError{message}
+}
+
+func (e *TokenSignatureError) Error() string {
+	return e.message
+}
+
+// TokenClaimsError is returned for a wrong issuer, audience, a token used before nbf or missing claims.
+type TokenClaimsError struct {
+	message string
+}
+
+func NewTokenClaimsError(message string) *TokenClaimsError {
+	return &TokenClaimsError{message}
+}
+
+func (e *TokenClaimsError) Error() string {
+	return e.message
+}
+
+// SessionRevokedError is returned for a valid token of a session that was logged out or expired.
+type SessionRevokedError struct {
+	message string
+}
+
+func NewSessionRevokedError(message string) *SessionRevokedError {
+	return &SessionRevokedError{message}
+}
+
+func (e *SessionRevokedError) Error() string {
+	return e.message
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	playerExportUrl  = "/player/me/export"
+	playerRestoreUrl = "/player/me/restore"
+)
+
+type accountDeletionTestCase struct {
+	method      string
+	url         string
+	requestBody *requests.PlayerDeleteRequest
+	*expectedError
+	name string
+}
+
+func TestAccountDeletion(t *testing.T) {
+	layers := preparationForTest(t)
+
+	fixture := fixtures.NewFixtures(layers.db, layers.service)
+	if err := fixture.LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+	if err := fixture.LoadGamesFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerId := uuid.MustParse(fixtures.Player1Uuid)
+	client := newTestClient(t, layers, playerId)
+
+	accountDeletionFailedTestCases := []accountDeletionTestCase{
+		{
+			method: http.MethodDelete,
+			url:    playerMeUrl,
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "Request is empty.",
+			},
+			name: "empty request body",
+		},
+		{
+			method:      http.MethodDelete,
+			url:         playerMeUrl,
+			requestBody: &requests.PlayerDeleteRequest{},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "Field validation for 'Password' failed on the 'required' tag",
+			},
+			name: "no password",
+		},
+		{
+			method:      http.MethodDelete,
+			url:         playerMeUrl,
+			requestBody: &requests.PlayerDeleteRequest{Password: "wrong-pass"},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "password is incorrect",
+			},
+			name: "wrong password",
+		},
+		{
+			method: http.MethodPost,
+			url:    playerRestoreUrl,
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "the account deletion is not scheduled",
+			},
+			name: "restore without a scheduled deletion",
+		},
+	}
+
+	for _, tCase := range accountDeletionFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			var requestBody interface{}
+			if tCase.requestBody != nil {
+				requestBody = tCase.requestBody
+			}
+			client.sendFailing(tt, tCase.expectedError, playerId, tCase.method, tCase.url, requestBody)
+		})
+	}
+
+	t.Run("export", func(tt *testing.T) {
+		own, err := layers.service.Chat.Send(playerId, nil, "anyone for a game?")
+		if !assert.NoError(tt, err) {
+			return
+		}
+		other, err := layers.service.Chat.Send(uuid.MustParse(fixtures.Player2Uuid), nil, "not you")
+		if !assert.NoError(tt, err) {
+			return
+		}
+		report, err := layers.service.Chat.Report(playerId, other.ID, "rude")
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		var response responses.PlayerExportResponse
+		resCode := client.send(playerId, http.MethodGet, playerExportUrl, nil, &response)
+		if !assert.Equal(tt, http.StatusOK, resCode) {
+			return
+		}
+		assert.Equal(tt, playerId, response.Profile.ID)
+		assert.Equal(tt, fixtures.Player1Email, response.Profile.Email)
+		assert.Len(tt, response.Games, 4)
+		assert.NotNil(tt, response.Points)
+		if assert.Len(tt, response.ChatMessages, 1) {
+			assert.Equal(tt, own.ID, response.ChatMessages[0].ID)
+		}
+		if assert.Len(tt, response.ChatReports, 1) {
+			assert.Equal(tt, report.ID, response.ChatReports[0].ID)
+			assert.Equal(tt, other.ID, response.ChatReports[0].Message.ID)
+		}
+		assert.False(tt, response.ExportedAt.IsZero())
+	})
+
+	t.Run("schedule and restore", func(tt *testing.T) {
+		var response responses.PlayerMeResponse
+		resCode := client.send(playerId, http.MethodDelete, playerMeUrl, requests.PlayerDeleteRequest{
+			Password: fixtures.Player1Password,
+		}, &response)
+		assert.Equal(tt, http.StatusAccepted, resCode)
+		if assert.NotNil(tt, response.DeletionScheduledAt) {
+			assert.True(tt, response.DeletionScheduledAt.After(time.Now()))
+		}
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "the account deletion is already scheduled",
+		}, playerId, http.MethodDelete, playerMeUrl, requests.PlayerDeleteRequest{Password: fixtures.Player1Password})
+
+		// Nothing happens before the cooling-off period is over.
+		assert.NoError(tt, layers.service.Player.DeleteDue())
+
+		response = responses.PlayerMeResponse{}
+		resCode = client.send(playerId, http.MethodPost, playerRestoreUrl, nil, &response)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Nil(tt, response.DeletionScheduledAt)
+		assert.Equal(tt, fixtures.Player1Email, response.Email)
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "the account deletion is not scheduled",
+		}, playerId, http.MethodPost, playerRestoreUrl, nil)
+	})
+
+	t.Run("anonymise after the cooling-off period", func(tt *testing.T) {
+		otherId := uuid.MustParse(fixtures.Player2Uuid)
+		waitingGame, err := layers.service.Game.NewGameRequest(otherId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Game.JoinGame(playerId, waitingGame.ID)
+		assert.NoError(tt, err)
+		tournament, err := layers.service.Tournament.Create(
+			otherId, "Cup", dictionary.TournamentTypeSingleElimination, "", 0, 0,
+		)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Tournament.Join(playerId, tournament.ID)
+		assert.NoError(tt, err)
+
+		resCode := client.send(playerId, http.MethodDelete, playerMeUrl, requests.PlayerDeleteRequest{
+			Password: fixtures.Player1Password,
+		}, nil)
+		if !assert.Equal(tt, http.StatusAccepted, resCode) {
+			return
+		}
+		assert.NoError(tt, layers.db.
+			Model(&entities.Player{}).
+			Where("id = ?", playerId).
+			Update("deletion_scheduled_at", time.Now().Add(-time.Minute)).
+			Error)
+		assert.NoError(tt, layers.service.Player.DeleteDue())
+
+		resCode = client.send(playerId, http.MethodGet, playerMeUrl, nil, nil)
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+
+		resCode = client.send(uuid.Nil, http.MethodPost, authLoginUrl, requests.AuthLoginRequest{
+			Login:    fixtures.Player1Email,
+			Password: fixtures.Player1Password,
+		}, nil)
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+
+		var profile responses.PlayerProfileResponse
+		resCode = client.send(uuid.Nil, http.MethodGet, playerProfileUrl+"/"+fixtures.Player1Uuid, nil, &profile)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, entities.DeletedPlayerName, profile.DisplayName)
+
+		// The games of the other players keep their participants.
+		var participants int64
+		layers.db.Model(&entities.GamePlayer{}).Where("game_id = ?", fixtures.GameFinishedUuid).Count(&participants)
+		assert.Equal(tt, int64(2), participants)
+
+		// The games and the tournaments that have not started yet go on without them.
+		game, err := layers.service.Game.FindGame(waitingGame.ID)
+		if assert.NoError(tt, err) && assert.Len(tt, game.Players, 1) {
+			assert.Equal(tt, otherId, game.Players[0].ID)
+		}
+		tournament, err = layers.service.Tournament.Find(tournament.ID)
+		if assert.NoError(tt, err) {
+			assert.Empty(tt, tournament.Players)
+		}
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
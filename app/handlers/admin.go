@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (h *Handler) adminPlayerSearch(c *gin.Context) {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	players, err := h.service.Admin.SearchPlayers(c.Query("q"), limit, offset)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.AdminPlayerResponse, 0, len(players))
+	for _, player := range players {
+		response = append(response, responses.AdminPlayerResponse{
+			ID:            player.ID,
+			Email:         player.Email,
+			DisplayName:   player.DisplayName,
+			IsBot:         player.IsBot,
+			Points:        player.Points,
+			EmailVerified: player.EmailVerifiedAt != nil,
+			CreatedAt:     player.CreatedAt,
+		})
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) adminPlayerGames(c *gin.Context) {
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	games, err := h.service.Admin.PlayerGames(playerId, limit)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.AdminPlayerGameResponse, 0, len(games))
+	for _, game := range games {
+		var place uint8
+		for _, result := range game.Result {
+			if result.PlayerID == playerId {
+				place = result.Place
+			}
+		}
+		response = append(response, responses.AdminPlayerGameResponse{
+			ID:          game.ID,
+			Status:      string(game.Status),
+			Round:       game.Round,
+			Players:     len(game.Players),
+			Place:       place,
+			Prizes:      newGamePrizesResponse(game.Prizes),
+			StartedAt:   game.StartedAt,
+			FinishedAt:  game.FinishedAt,
+			CancelledAt: game.CancelledAt,
+		})
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) adminPlayerPoints(c *gin.Context) {
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	points, err := h.service.Admin.PlayerPoints(playerId, limit)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, responses.AdminPlayerPointsResponse{
+		PlayerID:     playerId,
+		Balance:      points.Balance,
+		Transactions: newPointsTransactionsResponse(points.Transactions),
+	})
+}
+
+func (h *Handler) adminPointsAdjust(c *gin.Context) {
+	adminId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AdminPointsAdjustRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transaction, err := h.service.Admin.AdjustPoints(adminId, playerId, request.Amount, request.Reason)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newPointsTransactionResponse(transaction))
+}
+
+func (h *Handler) adminPlayerBan(c *gin.Context) {
+	adminId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AdminBanRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ban, err := h.service.Admin.Ban(
+		adminId,
+		playerId,
+		request.Reason,
+		time.Duration(request.DurationMinutes)*time.Minute,
+	)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusCreated, responses.BanResponse{
+		ID:        ban.ID,
+		PlayerID:  ban.PlayerID,
+		Reason:    ban.Reason,
+		IssuedBy:  ban.IssuedBy,
+		ExpiresAt: ban.ExpiresAt,
+		CreatedAt: ban.CreatedAt,
+	})
+}
+
+// adminPlayerUnban takes an optional reason in the body.
+func (h *Handler) adminPlayerUnban(c *gin.Context) {
+	adminId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	var request requests.AdminReasonRequest
+	if c.Request.Body != http.NoBody {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := h.service.Admin.Unban(adminId, playerId, request.Reason); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) adminGameCancel(c *gin.Context) {
+	adminId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AdminReasonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	game, refunds, err := h.service.Admin.CancelGame(adminId, gameId, request.Reason)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, responses.AdminGameCancelResponse{
+		Game:    newGameStateResponse(game),
+		Refunds: newPointsTransactionsResponse(refunds),
+	})
+}
+
+func (h *Handler) adminGameFinish(c *gin.Context) {
+	adminId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request requests.AdminGameFinishRequest
+	if c.Request.Body != http.NoBody {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	var winnerId *uuid.UUID
+	if request.WinnerId != nil {
+		parsed, err := uuid.Parse(*request.WinnerId)
+		if err != nil {
+			h.response.NewErrorResponse(c, http.StatusBadRequest, "winner id is invalid")
+			return
+		}
+		winnerId = &parsed
+	}
+
+	game, err := h.service.Admin.FinishGame(adminId, gameId, winnerId, request.Reason)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newGameStateResponse(game))
+}
+
+func (h *Handler) adminGamePrizes(c *gin.Context) {
+	adminId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AdminGamePrizesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	prizes := make([]entities.GamePrize, 0, len(request.Prizes))
+	for _, prize := range request.Prizes {
+		prizes = append(prizes, entities.GamePrize{Place: prize.Place, Prize: prize.Prize})
+	}
+
+	game, err := h.service.Admin.UpdateGamePrizes(adminId, gameId, prizes, request.Reason)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newGameStateResponse(game))
+}
+
+// adminAuditLog pages back in time with the created_at of the last entry as "before".
+func (h *Handler) adminAuditLog(c *gin.Context) {
+	var filter entities.AuditFilter
+	var err error
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.ActorID, err = queryUuid(c, "actor_id"); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.TargetID, err = queryUuid(c, "target_id"); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if before := c.Query("before"); before != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, before)
+		if err != nil {
+			h.response.NewErrorResponse(c, http.StatusBadRequest, "before must be an RFC 3339 time")
+			return
+		}
+		filter.Before = &parsed
+	}
+
+	entries, err := h.service.Admin.AuditLog(filter)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.AuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		response = append(response, responses.AuditEntryResponse{
+			ID:         entry.ID,
+			ActorID:    entry.ActorID,
+			Action:     string(entry.Action),
+			TargetType: string(entry.TargetType),
+			TargetID:   entry.TargetID,
+			Reason:     entry.Reason,
+			Details:    json.RawMessage(entry.Details),
+			CreatedAt:  entry.CreatedAt,
+		})
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func newPointsTransactionResponse(transaction *entities.PointsTransaction) responses.PointsTransactionResponse {
+	return responses.PointsTransactionResponse{
+		ID:        transaction.ID,
+		PlayerID:  transaction.PlayerID,
+		Amount:    transaction.Amount,
+		Kind:      string(transaction.Kind),
+		GameID:    transaction.GameID,
+		ActorID:   transaction.ActorID,
+		Reason:    transaction.Reason,
+		CreatedAt: transaction.CreatedAt,
+	}
+}
+
+func newPointsTransactionsResponse(transactions []entities.PointsTransaction) []responses.PointsTransactionResponse {
+	response := make([]responses.PointsTransactionResponse, 0, len(transactions))
+	for index := range transactions {
+		response = append(response, newPointsTransactionResponse(&transactions[index]))
+	}
+
+	return response
+}
+
+// queryInt reads an optional non-negative number from the query string, zero when it is absent.
+func queryInt(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative number", name)
+	}
+
+	return number, nil
+}
+
+func queryUuid(c *gin.Context, name string) (*uuid.UUID, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s is invalid", name)
+	}
+
+	return &id, nil
+}
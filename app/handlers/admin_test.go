@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	adminPlayersUrl       = "/admin/players"
+	adminPlayerUrlPattern = "/admin/players/%s"
+	adminGameUrlPattern   = "/admin/games/%s"
+	adminAuditUrl         = "/admin/audit"
+)
+
+type adminTestCase struct {
+	actorId     uuid.UUID
+	method      string
+	url         string
+	requestBody interface{}
+	*expectedError
+	name string
+}
+
+func TestAdmin(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	adminId := uuid.MustParse(fixtures.Player1Uuid)
+	playerTwoId := uuid.MustParse(fixtures.Player2Uuid)
+	playerThreeId := uuid.MustParse(fixtures.Player3Uuid)
+	if _, err := layers.service.Role.Grant(adminId, dictionary.RoleAdmin, nil); err != nil {
+		t.Fatalf("Failed to grant admin role, %s", err)
+	}
+	client := newTestClient(t, layers, adminId, playerTwoId, playerThreeId)
+	playerUrl := func(playerId uuid.UUID) string {
+		return fmt.Sprintf(adminPlayerUrlPattern, playerId)
+	}
+	pointsUrl := playerUrl(playerTwoId) + "/points"
+
+	adminFailedTestCases := []adminTestCase{
+		{
+			actorId: playerTwoId,
+			method:  http.MethodGet,
+			url:     adminPlayersUrl + "?q=jane",
+			expectedError: &expectedError{
+				code:    http.StatusForbidden,
+				message: "you have no permission for this action",
+			},
+			name: "search without permission",
+		},
+		{
+			actorId: adminId,
+			method:  http.MethodGet,
+			url:     adminPlayersUrl + "?limit=-1",
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "limit must be a non-negative number",
+			},
+			name: "negative limit",
+		},
+		{
+			actorId:     adminId,
+			method:      http.MethodPost,
+			url:         pointsUrl,
+			requestBody: requests.AdminPointsAdjustRequest{Amount: 10},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "Field validation for 'Reason' failed on the 'required' tag",
+			},
+			name: "points without a reason",
+		},
+		{
+			actorId:     adminId,
+			method:      http.MethodPost,
+			url:         pointsUrl,
+			requestBody: requests.AdminPointsAdjustRequest{Amount: -1, Reason: "too much"},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "points can't go below zero",
+			},
+			name: "points below zero",
+		},
+		{
+			actorId:     adminId,
+			method:      http.MethodPost,
+			url:         playerUrl(adminId) + "/ban",
+			requestBody: requests.AdminBanRequest{Reason: "testing"},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "you can't ban yourself",
+			},
+			name: "ban yourself",
+		},
+		{
+			actorId: adminId,
+			method:  http.MethodDelete,
+			url:     playerUrl(playerTwoId) + "/ban",
+			expectedError: &expectedError{
+				code:    http.StatusNotFound,
+				message: "the player is not banned",
+			},
+			name: "unban a player who is not banned",
+		},
+		{
+			actorId: playerTwoId,
+			method:  http.MethodGet,
+			url:     adminAuditUrl,
+			expectedError: &expectedError{
+				code:    http.StatusForbidden,
+				message: "you have no permission for this action",
+			},
+			name: "audit log without permission",
+		},
+	}
+
+	for _, tCase := range adminFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			client.sendFailing(tt, tCase.expectedError, tCase.actorId, tCase.method, tCase.url, tCase.requestBody)
+		})
+	}
+
+	t.Run("search players", func(tt *testing.T) {
+		var players []responses.AdminPlayerResponse
+		resCode := client.send(adminId, http.MethodGet, adminPlayersUrl+"?q=jane", nil, &players)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, players, 1) {
+			assert.Equal(tt, playerTwoId, players[0].ID)
+		}
+	})
+
+	t.Run("adjust points", func(tt *testing.T) {
+		var transaction responses.PointsTransactionResponse
+		resCode := client.send(adminId, http.MethodPost, pointsUrl, requests.AdminPointsAdjustRequest{
+			Amount: 10,
+			Reason: "compensation for an outage",
+		}, &transaction)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, string(dictionary.PointsKindAdjustment), transaction.Kind)
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "points can't go below zero",
+		}, adminId, http.MethodPost, pointsUrl, requests.AdminPointsAdjustRequest{Amount: -11, Reason: "too much"})
+
+		var points responses.AdminPlayerPointsResponse
+		resCode = client.send(adminId, http.MethodGet, pointsUrl, nil, &points)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, uint(10), points.Balance)
+		assert.Len(tt, points.Transactions, 1)
+	})
+
+	t.Run("finish and cancel a game", func(tt *testing.T) {
+		game, err := layers.service.Game.NewGameRequest(playerTwoId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Game.JoinGame(playerThreeId, game.ID)
+		assert.NoError(tt, err)
+		gameUrl := fmt.Sprintf(adminGameUrlPattern, game.ID)
+		prizesRequest := requests.AdminGamePrizesRequest{
+			Prizes: []requests.AdminGamePrize{{Place: 1, Prize: 5}},
+		}
+
+		// The players joined an unstaked game, they must be verified before it gets prizes.
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: fmt.Sprintf("player %s has not verified their email and can't play for prizes", playerTwoId),
+		}, adminId, http.MethodPut, gameUrl+"/prizes", prizesRequest)
+		assert.NoError(tt, layers.db.
+			Model(&entities.Player{}).
+			Where("id IN ?", []uuid.UUID{playerTwoId, playerThreeId}).
+			Update("email_verified_at", time.Now()).
+			Error)
+
+		var state responses.GameStateResponse
+		resCode := client.send(adminId, http.MethodPut, gameUrl+"/prizes", prizesRequest, &state)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, []responses.GamePrizeResponse{{Place: 1, Prize: 5}}, state.Prizes)
+
+		assert.NoError(tt, layers.service.Game.StartGame(playerTwoId, game.ID))
+		assert.NoError(tt, layers.service.Game.StartGame(playerThreeId, game.ID))
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "prizes can be changed only before the game starts",
+		}, adminId, http.MethodPut, gameUrl+"/prizes", requests.AdminGamePrizesRequest{})
+
+		winnerId := playerTwoId.String()
+		resCode = client.send(adminId, http.MethodPost, gameUrl+"/finish", requests.AdminGameFinishRequest{
+			WinnerId: &winnerId,
+		}, &state)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, string(dictionary.GameStatusFinished), state.Status)
+
+		var points responses.AdminPlayerPointsResponse
+		client.send(adminId, http.MethodGet, pointsUrl, nil, &points)
+		assert.Equal(tt, uint(15), points.Balance)
+
+		var cancelled responses.AdminGameCancelResponse
+		resCode = client.send(adminId, http.MethodPost, gameUrl+"/cancel", requests.AdminReasonRequest{
+			Reason: "collusion",
+		}, &cancelled)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.NotNil(tt, cancelled.Game.CancelledAt)
+		if assert.Len(tt, cancelled.Refunds, 1) {
+			assert.Equal(tt, -5, cancelled.Refunds[0].Amount)
+		}
+
+		client.send(adminId, http.MethodGet, pointsUrl, nil, &points)
+		assert.Equal(tt, uint(10), points.Balance)
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "the game is already cancelled",
+		}, adminId, http.MethodPost, gameUrl+"/cancel", requests.AdminReasonRequest{Reason: "collusion"})
+	})
+
+	t.Run("ban and unban", func(tt *testing.T) {
+		waitingGame, err := layers.service.Game.NewGameRequest(playerTwoId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Game.JoinGame(playerThreeId, waitingGame.ID)
+		assert.NoError(tt, err)
+		// The bot of the banned player can't play without its owner either.
+		_, apiKey, err := layers.service.ApiKey.Create(playerThreeId, "bot", []dictionary.ApiKeyScope{
+			dictionary.ApiKeyScopeGamePlay,
+		})
+		if !assert.NoError(tt, err) {
+			return
+		}
+		botGame, err := layers.service.Game.NewGameRequest(playerTwoId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Game.JoinGame(apiKey.BotID, botGame.ID)
+		assert.NoError(tt, err)
+		banUrl := playerUrl(playerThreeId) + "/ban"
+
+		var ban responses.BanResponse
+		resCode := client.send(adminId, http.MethodPost, banUrl, requests.AdminBanRequest{
+			Reason:          "cheating",
+			DurationMinutes: 60,
+		}, &ban)
+		assert.Equal(tt, http.StatusCreated, resCode)
+		assert.NotNil(tt, ban.ExpiresAt)
+
+		var resErr responseError
+		resCode = client.send(playerThreeId, http.MethodGet, playerMeUrl, nil, &resErr)
+		assert.Equal(tt, http.StatusForbidden, resCode)
+		assert.Equal(tt, "account_banned", resErr.Code)
+		if assert.NotNil(tt, resErr.BannedUntil) && assert.NotNil(tt, ban.ExpiresAt) {
+			assert.WithinDuration(tt, *ban.ExpiresAt, *resErr.BannedUntil, time.Second)
+		}
+
+		for _, gameId := range []uuid.UUID{waitingGame.ID, botGame.ID} {
+			game, err := layers.service.Game.FindGame(gameId)
+			if assert.NoError(tt, err) && assert.Len(tt, game.Players, 1) {
+				assert.Equal(tt, playerTwoId, game.Players[0].ID)
+			}
+		}
+
+		resCode = client.send(adminId, http.MethodDelete, banUrl, nil, nil)
+		assert.Equal(tt, http.StatusNoContent, resCode)
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusNotFound,
+			message: "the player is not banned",
+		}, adminId, http.MethodDelete, banUrl, nil)
+
+		// The sessions outlive the ban.
+		resCode = client.send(playerThreeId, http.MethodGet, playerMeUrl, nil, nil)
+		assert.Equal(tt, http.StatusOK, resCode)
+	})
+
+	t.Run("banned player can't log in", func(tt *testing.T) {
+		login, password := "banned@test.com", "strong-pass"
+		playerId, err := layers.service.Auth.Registration(login, password, "")
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Admin.Ban(adminId, playerId, "cheating", 0)
+		assert.NoError(tt, err)
+
+		var resErr responseError
+		resCode := client.send(uuid.Nil, http.MethodPost, authLoginUrl, requests.AuthLoginRequest{
+			Login:    login,
+			Password: password,
+		}, &resErr)
+		assert.Equal(tt, http.StatusForbidden, resCode)
+		assert.Equal(tt, "account_banned", resErr.Code)
+		assert.Nil(tt, resErr.BannedUntil)
+
+		resCode = client.send(uuid.Nil, http.MethodPost, authLoginUrl, requests.AuthLoginRequest{
+			Login:    login,
+			Password: "wrong-pass",
+		}, &resErr)
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+	})
+
+	t.Run("audit log", func(tt *testing.T) {
+		var entries []responses.AuditEntryResponse
+		resCode := client.send(adminId, http.MethodGet, adminAuditUrl+"?target_id="+playerThreeId.String(), nil, &entries)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, entries, 2) {
+			assert.Equal(tt, string(dictionary.AuditActionPlayerUnban), entries[0].Action)
+			assert.Equal(tt, string(dictionary.AuditActionPlayerBan), entries[1].Action)
+			assert.Equal(tt, adminId, *entries[1].ActorID)
+
+			var details map[string]interface{}
+			if assert.NoError(tt, json.Unmarshal(entries[1].Details, &details)) {
+				assert.NotEmpty(tt, details["ban_id"])
+				assert.Equal(tt, 2.0, details["left_games"])
+				assert.Equal(tt, 0.0, details["left_tournaments"])
+			}
+		}
+
+		resCode = client.send(adminId, http.MethodGet, adminAuditUrl+"?actor_id="+adminId.String(), nil, &entries)
+		assert.Equal(tt, http.StatusOK, resCode)
+		// Points adjustment, prizes, finish, cancel, ban, unban and the second ban.
+		assert.Len(tt, entries, 7)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"net/http"
+)
+
+func (h *Handler) apiKeyCreate(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.ApiKeyCreateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scopes := make([]dictionary.ApiKeyScope, 0, len(request.Scopes))
+	for _, scope := range request.Scopes {
+		scopes = append(scopes, dictionary.ApiKeyScope(scope))
+	}
+
+	key, apiKey, err := h.service.ApiKey.Create(playerId, request.Name, scopes)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(
+		c, http.StatusCreated,
+		responses.ApiKeyCreateResponse{
+			Key:            key,
+			ApiKeyResponse: newApiKeyResponse(apiKey),
+		},
+	)
+}
+
+func (h *Handler) apiKeyList(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	apiKeys, err := h.service.ApiKey.List(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.ApiKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		response = append(response, newApiKeyResponse(&apiKey))
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) apiKeyRevoke(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	apiKeyIdParam, err := h.checkGetParam(c, "id")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	apiKeyId, err := uuid.Parse(apiKeyIdParam)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "api key id is invalid")
+		return
+	}
+
+	if err := h.service.ApiKey.Revoke(playerId, apiKeyId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func newApiKeyResponse(apiKey *entities.ApiKey) responses.ApiKeyResponse {
+	scopes := make([]string, 0)
+	for _, scope := range apiKey.ScopeList() {
+		scopes = append(scopes, string(scope))
+	}
+
+	return responses.ApiKeyResponse{
+		ID:         apiKey.ID,
+		BotID:      apiKey.BotID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     scopes,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	apiKeyUrl = "/apikey"
+)
+
+type apiKeyCreateTestCase struct {
+	requestBody *requests.ApiKeyCreateRequest
+	*expectedError
+	name string
+}
+
+type apiKeyAccessTestCase struct {
+	*expectedError
+	headers []*testRequestHeader
+	method  string
+	url     string
+	code    int
+	name    string
+}
+
+func TestApiKey(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player1Uuid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	playerHeaders := []*testRequestHeader{
+		{
+			key:   authorizationToken,
+			value: playerAuthToken,
+		},
+	}
+
+	apiKeyCreateFailedTestCases := []apiKeyCreateTestCase{
+		{
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "Request is empty.",
+			},
+			name: "empty request body",
+		},
+		{
+			requestBody: &requests.ApiKeyCreateRequest{
+				Name:   "tournament bot",
+				Scopes: []string{"admin"},
+			},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "scope admin is invalid",
+			},
+			name: "unknown scope",
+		},
+	}
+
+	for _, tCase := range apiKeyCreateFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			var body []byte
+			if tCase.requestBody != nil {
+				body, _ = json.Marshal(tCase.requestBody)
+			}
+
+			resBody, resCode := sendRequestAndGetResponse(requestData{
+				router:      layers.router,
+				headers:     playerHeaders,
+				requestBody: body,
+				method:      http.MethodPost,
+				url:         apiKeyUrl,
+			})
+
+			var resErr responseError
+			err := json.Unmarshal(resBody, &resErr)
+			if isNotError := assert.NoError(tt, err); !isNotError {
+				return
+			}
+			if isNotError := assert.Equal(tt, tCase.expectedError.code, resCode); !isNotError {
+				return
+			}
+			assert.Equal(tt, tCase.expectedError.message, resErr.Message)
+		})
+	}
+
+	createKey := func(scopes ...dictionary.ApiKeyScope) responses.ApiKeyCreateResponse {
+		scopeNames := make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			scopeNames = append(scopeNames, string(scope))
+		}
+		body, _ := json.Marshal(requests.ApiKeyCreateRequest{Name: "tournament bot", Scopes: scopeNames})
+
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:      layers.router,
+			headers:     playerHeaders,
+			requestBody: body,
+			method:      http.MethodPost,
+			url:         apiKeyUrl,
+		})
+
+		var response responses.ApiKeyCreateResponse
+		if err := json.Unmarshal(resBody, &response); err != nil {
+			t.Fatalf("Failed to create api key, %s", err)
+		}
+		assert.Equal(t, http.StatusCreated, resCode)
+		assert.True(t, strings.HasPrefix(response.Key, response.Prefix))
+
+		return response
+	}
+
+	fullKey := createKey(dictionary.ApiKeyScopeGameCreate, dictionary.ApiKeyScopeGamePlay)
+	playOnlyKey := createKey(dictionary.ApiKeyScopeGamePlay)
+	revokedKey := createKey(dictionary.ApiKeyScopeGamePlay)
+
+	t.Run("the bot is named after its owner", func(tt *testing.T) {
+		bot, err := layers.repository.Player.FindById(fullKey.BotID)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		assert.Equal(tt, "Bot "+fullKey.BotID.String()[:8]+" of "+fixtures.Player1DisplayName, bot.DisplayName)
+		assert.Equal(tt, "tournament bot", fullKey.Name)
+	})
+
+	_, resCode := sendRequestAndGetResponse(requestData{
+		router:  layers.router,
+		headers: playerHeaders,
+		method:  http.MethodDelete,
+		url:     fmt.Sprintf("%s/%s", apiKeyUrl, revokedKey.ID),
+	})
+	assert.Equal(t, http.StatusNoContent, resCode)
+
+	apiKeyHeaders := func(key string) []*testRequestHeader {
+		return []*testRequestHeader{
+			{
+				key:   authorizationApiKey,
+				value: key,
+			},
+		}
+	}
+
+	apiKeyAccessTestCases := []apiKeyAccessTestCase{
+		{
+			expectedError: &expectedError{
+				code:    http.StatusUnauthorized,
+				message: "Unauthorized",
+			},
+			headers: apiKeyHeaders("knb_wrong-key"),
+			method:  http.MethodPost,
+			url:     gameNewGameUrl,
+			name:    "unknown api key",
+		},
+		{
+			expectedError: &expectedError{
+				code:    http.StatusUnauthorized,
+				message: "Unauthorized",
+			},
+			headers: apiKeyHeaders(revokedKey.Key),
+			method:  http.MethodPost,
+			url:     gameNewGameUrl,
+			name:    "revoked api key",
+		},
+		{
+			expectedError: &expectedError{
+				code:    http.StatusForbidden,
+				message: "api key has no scope for this action",
+			},
+			headers: apiKeyHeaders(playOnlyKey.Key),
+			method:  http.MethodPost,
+			url:     gameNewGameUrl,
+			name:    "api key without scope",
+		},
+		{
+			expectedError: &expectedError{
+				code:    http.StatusForbidden,
+				message: "this action isn't available with an api key",
+			},
+			headers: apiKeyHeaders(fullKey.Key),
+			method:  http.MethodGet,
+			url:     apiKeyUrl,
+			name:    "api key manages api keys",
+		},
+		{
+			headers: apiKeyHeaders(fullKey.Key),
+			method:  http.MethodPost,
+			url:     gameNewGameUrl,
+			code:    http.StatusCreated,
+			name:    "bot creates a game",
+		},
+	}
+
+	for _, tCase := range apiKeyAccessTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			resBody, resCode := sendRequestAndGetResponse(requestData{
+				router:  layers.router,
+				headers: tCase.headers,
+				method:  tCase.method,
+				url:     tCase.url,
+			})
+
+			if tCase.expectedError != nil {
+				var resErr responseError
+				err := json.Unmarshal(resBody, &resErr)
+				if isNotError := assert.NoError(tt, err); !isNotError {
+					return
+				}
+				if isNotError := assert.Equal(tt, tCase.expectedError.code, resCode); !isNotError {
+					return
+				}
+				assert.Equal(tt, tCase.expectedError.message, resErr.Message)
+				return
+			}
+
+			var response responses.GameNewGameResponse
+			err := json.Unmarshal(resBody, &response)
+			if !assert.NoError(tt, err) {
+				return
+			}
+			assert.Equal(tt, tCase.code, resCode)
+
+			game, err := layers.service.Game.FindGame(response.ID)
+			if !assert.NoError(tt, err) {
+				return
+			}
+			assert.True(tt, game.BotOwned)
+			assert.Equal(tt, fullKey.BotID, game.Players[0].ID)
+		})
+	}
+
+	t.Run("bots leave no presence", func(tt *testing.T) {
+		presences, err := layers.service.Presence.Find([]uuid.UUID{fullKey.BotID})
+		if assert.NoError(tt, err) {
+			assert.Empty(tt, presences)
+		}
+	})
+
+	t.Run("bot of an unverified owner joins a game with prizes", func(tt *testing.T) {
+		ownerId := uuid.MustParse(fixtures.Player2Uuid)
+		key, _, err := layers.service.ApiKey.Create(ownerId, "staked bot", []dictionary.ApiKeyScope{
+			dictionary.ApiKeyScopeGamePlay,
+		})
+		if !assert.NoError(tt, err) {
+			return
+		}
+		creatorId := uuid.MustParse(fixtures.Player3Uuid)
+		assert.NoError(tt, layers.db.
+			Model(&entities.Player{}).
+			Where("id = ?", creatorId).
+			Update("email_verified_at", time.Now()).
+			Error)
+		game, err := layers.service.Game.NewGameRequest(creatorId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Game.UpdatePrizes(game.ID, []entities.GamePrize{{Place: 1, Prize: 10}}, nil)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: apiKeyHeaders(key),
+			method:  http.MethodPost,
+			url:     gameJoinGameUrl + game.ID.String(),
+		})
+		var resErr responseError
+		_ = json.Unmarshal(resBody, &resErr)
+		assert.Equal(tt, http.StatusForbidden, resCode)
+		assert.Equal(tt, "verify your email to play games with prizes", resErr.Message)
+
+		assert.NoError(tt, layers.db.
+			Model(&entities.Player{}).
+			Where("id = ?", ownerId).
+			Update("email_verified_at", time.Now()).
+			Error)
+		_, resCode = sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: apiKeyHeaders(key),
+			method:  http.MethodPost,
+			url:     gameJoinGameUrl + game.ID.String(),
+		})
+		assert.Equal(tt, http.StatusOK, resCode)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
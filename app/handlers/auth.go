@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
 	"knb/app/handlers/requests"
 	"knb/app/handlers/responses"
 	"net/http"
@@ -19,10 +24,7 @@ func (h *Handler) authRegistration(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.Auth.Registration(
-		request.Login,
-		h.service.Security.GeneratePasswordHash(request.Password),
-	)
+	response, err := h.service.Auth.Registration(request.Login, request.Password, request.DisplayName)
 	if err != nil {
 		h.response.ParseError(c, err)
 		return
@@ -46,24 +48,344 @@ func (h *Handler) authLogin(c *gin.Context) {
 		return
 	}
 
-	player, err := h.service.Auth.Login(
-		request.Login,
-		h.service.Security.GeneratePasswordHash(request.Password),
-	)
+	if !h.checkLoginThrottle(c, request.Login) {
+		return
+	}
+	player, err := h.service.Auth.Login(request.Login, request.Password)
 	if err != nil {
+		h.loginFailed(c, request.Login, entities.NewLoginFailure(
+			nil, request.Login, c.ClientIP(), c.Request.UserAgent(), dictionary.LoginFailureWrongCredentials,
+		), err)
+		return
+	}
+	if err := h.service.LoginThrottle.Succeeded(request.Login); err != nil {
 		h.response.ParseError(c, err)
 		return
 	}
-	token, err := h.service.Security.GenerateAuthToken(player.ID)
+	challenge, err := h.service.TwoFactor.Challenge(player.ID)
 	if err != nil {
 		h.response.ParseError(c, err)
 		return
 	}
+	if challenge != nil {
+		h.response.NewOkResponse(c, http.StatusOK, responses.AuthLoginChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge.Token,
+			ExpiresAt:         challenge.ExpiresAt,
+		})
+		return
+	}
 
-	h.response.NewOkResponse(
-		c, http.StatusOK,
-		responses.AuthLoginResponse{
-			Token: token,
-		},
-	)
+	tokens, err := h.service.Auth.StartSession(player.ID, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.respondWithTokens(c, tokens, request.Cookie)
+}
+
+// authRefresh takes the refresh token from the body or, for browser clients, from its cookie.
+func (h *Handler) authRefresh(c *gin.Context) {
+	var request requests.AuthRefreshRequest
+	if c.Request.Body != http.NoBody {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	fromCookie := false
+	if request.RefreshToken == "" {
+		cookieToken, err := c.Cookie(refreshTokenCookie)
+		if err != nil || cookieToken == "" {
+			h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+			return
+		}
+		if err := checkCsrf(c); err != nil {
+			h.response.NewErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		request.RefreshToken, fromCookie = cookieToken, true
+	}
+
+	tokens, err := h.service.Auth.Refresh(request.RefreshToken)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.respondWithTokens(c, tokens, fromCookie)
+}
+
+func (h *Handler) authLogout(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	sessionId, err := h.getSessionContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.Auth.Logout(playerId, sessionId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.clearAuthCookies(c)
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) authLogoutAll(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.Auth.LogoutAll(playerId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.clearAuthCookies(c)
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) authSessions(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	currentSessionId, err := h.getSessionContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	sessions, err := h.service.Auth.Sessions(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.AuthSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, responses.AuthSessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			Current:    session.ID == currentSessionId,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) authSessionRevoke(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	sessionIdParam, err := h.checkGetParam(c, "id")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	sessionId, err := uuid.Parse(sessionIdParam)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "session id is invalid")
+		return
+	}
+
+	if err := h.service.Auth.RevokeSession(playerId, sessionId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) authVerifyEmail(c *gin.Context) {
+	verificationToken := c.Query("token")
+	if verificationToken == "" {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := h.service.Auth.VerifyEmail(verificationToken); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) authVerifyEmailResend(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.Auth.ResendEmailVerification(playerId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+// authPasswordForgot answers 202 whether the login exists or not.
+func (h *Handler) authPasswordForgot(c *gin.Context) {
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AuthPasswordForgotRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.service.Auth.ForgotPassword(request.Login)
+
+	c.Status(http.StatusAccepted)
+}
+
+// authPasswordResetCheck is where the mailed link lands when no reset page is configured,
+// it answers whether the token is still usable; the new password is posted to the same path.
+func (h *Handler) authPasswordResetCheck(c *gin.Context) {
+	resetToken := c.Query("token")
+	if resetToken == "" {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := h.service.Auth.CheckPasswordReset(resetToken); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) authPasswordReset(c *gin.Context) {
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AuthPasswordResetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Auth.ResetPassword(request.Token, request.Password); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.clearAuthCookies(c)
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) authPasswordChange(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	sessionId, err := h.getSessionContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AuthPasswordChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.service.Auth.ChangePassword(playerId, sessionId, request.CurrentPassword, request.Password)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+// checkLoginThrottle answers 429 with Retry-After while the account or the IP is locked out.
+func (h *Handler) checkLoginThrottle(c *gin.Context, account string) bool {
+	retryAfter, err := h.service.LoginThrottle.Check(account, c.ClientIP())
+	if err != nil {
+		h.response.ParseError(c, err)
+		return false
+	}
+	if retryAfter > 0 {
+		h.tooManyRequests(c, retryAfter.Seconds())
+		return false
+	}
+
+	return true
+}
+
+// loginFailed counts the wrong credentials, a failure that locks the account out is answered with 429 already.
+func (h *Handler) loginFailed(c *gin.Context, account string, failure *entities.LoginFailure, err error) {
+	var wrongLoginError *customErrors.WrongLoginError
+	if !errors.As(err, &wrongLoginError) {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	retryAfter, throttleErr := h.service.LoginThrottle.Failed(account, failure)
+	if throttleErr != nil {
+		h.response.ParseError(c, throttleErr)
+		return
+	}
+	if retryAfter > 0 {
+		h.tooManyRequests(c, retryAfter.Seconds())
+		return
+	}
+
+	h.response.ParseError(c, err)
+}
+
+func (h *Handler) respondWithTokens(c *gin.Context, tokens *entities.AuthTokens, cookie bool) {
+	response := newAuthLoginResponse(tokens)
+	if cookie {
+		if err := h.setAuthCookies(c, tokens); err != nil {
+			h.response.ParseError(c, err)
+			return
+		}
+		response.Token, response.RefreshToken = "", ""
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func newAuthLoginResponse(tokens *entities.AuthTokens) responses.AuthLoginResponse {
+	return responses.AuthLoginResponse{
+		Token:            tokens.AccessToken,
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
 }
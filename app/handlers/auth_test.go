@@ -1,19 +1,37 @@
 package handlers
 
 import (
+	"bytes"
+	"crypto/sha1"
 	"encoding/json"
+	"fmt"
+	"knb/app/dictionary"
+	"knb/app/entities"
 	"knb/app/handlers/requests"
 	"knb/app/handlers/responses"
 	"knb/tests/fixtures"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 const (
 	authRegistrationUrl = "/auth/registration"
 	authLoginUrl        = "/auth/login"
+	authVerifyEmailUrl  = "/auth/verify-email"
+	authRefreshUrl      = "/auth/refresh"
+	authLogoutUrl       = "/auth/logout"
+	authLogoutAllUrl    = "/auth/logout-all"
+	authSessionsUrl     = "/auth/sessions"
+
+	authPasswordForgotUrl = "/auth/password/forgot"
+	authPasswordResetUrl  = "/auth/password/reset"
+	authPasswordChangeUrl = "/auth/password/change"
 )
 
 type registrationTestCase struct {
@@ -74,6 +92,17 @@ func TestAuthRegistration(t *testing.T) {
 			},
 			name: "`login` already exists",
 		},
+		{
+			requestBody: &requests.AuthRegistrationRequest{
+				Login:    "not-an-email",
+				Password: "strong-pass",
+			},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "login must be a valid email",
+			},
+			name: "`login` is not an email",
+		},
 	}
 
 	for _, tCase := range registrationFailedTestCases {
@@ -140,9 +169,45 @@ func TestAuthRegistration(t *testing.T) {
 				t.Errorf("Failed to get created player, %s", err)
 			}
 			assert.Equal(tt, result.Email, tCase.requestBody.Login)
+			assert.Nil(tt, result.EmailVerifiedAt)
 		})
 	}
 
+	t.Run("email verification", func(tt *testing.T) {
+		player, err := layers.repository.Player.FindByLogin("new-user@test.com")
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router: layers.router,
+			method: http.MethodGet,
+			url:    authVerifyEmailUrl + "?token=invalid",
+		})
+		var resErr responseError
+		if assert.NoError(tt, json.Unmarshal(resBody, &resErr)) {
+			assert.Equal(tt, http.StatusBadRequest, resCode)
+			assert.Equal(tt, "verification link is invalid or expired", resErr.Message)
+		}
+
+		verificationToken, err := layers.service.Security.GenerateEmailVerificationToken(player.ID, player.Email)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, resCode = sendRequestAndGetResponse(requestData{
+			router: layers.router,
+			method: http.MethodGet,
+			url:    authVerifyEmailUrl + "?token=" + url.QueryEscape(verificationToken),
+		})
+		assert.Equal(tt, http.StatusNoContent, resCode)
+
+		result, err := layers.repository.Player.FindById(player.ID)
+		if assert.NoError(tt, err) {
+			assert.NotNil(tt, result.EmailVerifiedAt)
+			assert.True(tt, result.CanPlayStaked())
+		}
+	})
+
 	if err := layers.bootstrap.TeardownTestDB(); err != nil {
 		t.Errorf("Failed to teardown test DB, %s", err)
 	}
@@ -266,14 +331,375 @@ func TestAuthLogin(t *testing.T) {
 			}
 			assert.Equal(tt, http.StatusOK, resCode)
 
-			playerId, err := layers.service.Security.ParseAuthToken(response.Token)
+			playerId, _, _, err := layers.service.Security.ParseAuthToken(response.Token)
 			if err != nil {
 				t.Errorf("Failed to get created player, %s", err)
 			}
-			assert.Equal(tt, playerId, fixtures.Player1Uuid)
+			assert.Equal(tt, fixtures.Player1Uuid, playerId.String())
 		})
 	}
 
+	t.Run("legacy password hash is upgraded", func(tt *testing.T) {
+		legacyHash := sha1.New()
+		legacyHash.Write([]byte(fixtures.Player1Password))
+		legacySalt := "8yU9nA`tx$=k-XPB/4@(ctf[n$Me;#Mhk^T]jBZC/K"
+		player := entities.NewPlayer("legacy@test.com", fmt.Sprintf("%x", legacyHash.Sum([]byte(legacySalt))), "")
+		if err := layers.db.Create(player).Error; err != nil {
+			tt.Fatalf("Failed to create legacy player, %s", err)
+		}
+
+		body, _ := json.Marshal(requests.AuthLoginRequest{
+			Login:    player.Email,
+			Password: fixtures.Player1Password,
+		})
+		_, resCode := sendRequestAndGetResponse(requestData{
+			router:      layers.router,
+			requestBody: body,
+			method:      http.MethodPost,
+			url:         authLoginUrl,
+		})
+		assert.Equal(tt, http.StatusOK, resCode)
+
+		result, err := layers.repository.Player.FindById(player.ID)
+		if isNotError := assert.NoError(tt, err); !isNotError {
+			return
+		}
+		valid, needsRehash := layers.service.Security.VerifyPassword(fixtures.Player1Password, result.Password)
+		assert.True(tt, valid)
+		assert.False(tt, needsRehash)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
+
+func TestAuthSession(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	login := func() responses.AuthLoginResponse {
+		body, _ := json.Marshal(requests.AuthLoginRequest{
+			Login:    fixtures.Player1Email,
+			Password: fixtures.Player1Password,
+		})
+		resBody, _ := sendRequestAndGetResponse(requestData{
+			router:      layers.router,
+			requestBody: body,
+			method:      http.MethodPost,
+			url:         authLoginUrl,
+		})
+
+		var response responses.AuthLoginResponse
+		if err := json.Unmarshal(resBody, &response); err != nil {
+			t.Fatalf("Failed to login player, %s", err)
+		}
+
+		return response
+	}
+	refresh := func(refreshToken string) (responses.AuthLoginResponse, int) {
+		body, _ := json.Marshal(requests.AuthRefreshRequest{RefreshToken: refreshToken})
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:      layers.router,
+			requestBody: body,
+			method:      http.MethodPost,
+			url:         authRefreshUrl,
+		})
+
+		var response responses.AuthLoginResponse
+		_ = json.Unmarshal(resBody, &response)
+
+		return response, resCode
+	}
+	post := func(url, accessToken string) int {
+		_, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: []*testRequestHeader{{key: authorizationToken, value: accessToken}},
+			method:  http.MethodPost,
+			url:     url,
+		})
+
+		return resCode
+	}
+	list := func(accessToken string) int {
+		_, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: []*testRequestHeader{{key: authorizationToken, value: accessToken}},
+			method:  http.MethodGet,
+			url:     apiKeyUrl,
+		})
+
+		return resCode
+	}
+
+	t.Run("refresh token rotation and reuse detection", func(tt *testing.T) {
+		tokens := login()
+
+		rotated, resCode := refresh(tokens.RefreshToken)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.NotEqual(tt, tokens.RefreshToken, rotated.RefreshToken)
+		assert.Equal(tt, http.StatusOK, list(rotated.Token))
+
+		_, resCode = refresh(tokens.RefreshToken)
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+
+		_, resCode = refresh(rotated.RefreshToken)
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+		assert.Equal(tt, http.StatusUnauthorized, list(rotated.Token))
+	})
+
+	t.Run("logout revokes the current session only", func(tt *testing.T) {
+		first, second := login(), login()
+
+		assert.Equal(tt, http.StatusNoContent, post(authLogoutUrl, first.Token))
+		assert.Equal(tt, http.StatusUnauthorized, list(first.Token))
+		_, resCode := refresh(first.RefreshToken)
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+		assert.Equal(tt, http.StatusOK, list(second.Token))
+	})
+
+	t.Run("list and revoke sessions", func(tt *testing.T) {
+		assert.Equal(tt, http.StatusNoContent, post(authLogoutAllUrl, login().Token))
+		phone, laptop := login(), login()
+
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: []*testRequestHeader{{key: authorizationToken, value: laptop.Token}},
+			method:  http.MethodGet,
+			url:     authSessionsUrl,
+		})
+		var sessions []responses.AuthSessionResponse
+		if isNotError := assert.NoError(tt, json.Unmarshal(resBody, &sessions)); !isNotError {
+			return
+		}
+		assert.Equal(tt, http.StatusOK, resCode)
+
+		if isNotError := assert.Equal(tt, 2, len(sessions)); !isNotError {
+			return
+		}
+
+		var phoneSessionId string
+		for _, session := range sessions {
+			if !session.Current {
+				phoneSessionId = session.ID.String()
+			}
+		}
+		assert.NotEmpty(tt, phoneSessionId)
+
+		_, resCode = sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: []*testRequestHeader{{key: authorizationToken, value: laptop.Token}},
+			method:  http.MethodDelete,
+			url:     fmt.Sprintf("%s/%s", authSessionsUrl, phoneSessionId),
+		})
+		assert.Equal(tt, http.StatusNoContent, resCode)
+		assert.Equal(tt, http.StatusUnauthorized, list(phone.Token))
+		assert.Equal(tt, http.StatusOK, list(laptop.Token))
+	})
+
+	t.Run("logout from every session", func(tt *testing.T) {
+		first, second := login(), login()
+
+		assert.Equal(tt, http.StatusNoContent, post(authLogoutAllUrl, first.Token))
+		assert.Equal(tt, http.StatusUnauthorized, list(first.Token))
+		assert.Equal(tt, http.StatusUnauthorized, list(second.Token))
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
+
+func TestAuthPassword(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerId := uuid.MustParse(fixtures.Player1Uuid)
+	send := func(url string, request interface{}, headers ...*testRequestHeader) (responseError, int) {
+		body, _ := json.Marshal(request)
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:      layers.router,
+			requestBody: body,
+			headers:     headers,
+			method:      http.MethodPost,
+			url:         url,
+		})
+
+		var resErr responseError
+		_ = json.Unmarshal(resBody, &resErr)
+
+		return resErr, resCode
+	}
+	canLogin := func(password string) bool {
+		_, err := layers.service.Auth.Login(fixtures.Player1Email, password)
+
+		return err == nil
+	}
+	// openLink follows the mailed reset link.
+	openLink := func(resetToken string) int {
+		_, resCode := sendRequestAndGetResponse(requestData{
+			router: layers.router,
+			method: http.MethodGet,
+			url:    authPasswordResetUrl + "?token=" + url.QueryEscape(resetToken),
+		})
+
+		return resCode
+	}
+
+	t.Run("forgot password doesn't disclose the login", func(tt *testing.T) {
+		_, resCode := send(authPasswordForgotUrl, requests.AuthPasswordForgotRequest{Login: "nobody@test.com"})
+		assert.Equal(tt, http.StatusAccepted, resCode)
+
+		_, resCode = send(authPasswordForgotUrl, requests.AuthPasswordForgotRequest{Login: fixtures.Player1Email})
+		assert.Equal(tt, http.StatusAccepted, resCode)
+	})
+
+	t.Run("reset password", func(tt *testing.T) {
+		accessToken, err := newAuthToken(layers, playerId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		resetToken, resetTokenHash, err := layers.service.Security.GeneratePasswordResetToken()
+		if !assert.NoError(tt, err) {
+			return
+		}
+		err = layers.repository.PasswordReset.Create(
+			entities.NewPasswordResetToken(playerId, resetTokenHash, time.Now().Add(time.Hour)),
+		)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		assert.Equal(tt, http.StatusNoContent, openLink(resetToken))
+		assert.Equal(tt, http.StatusBadRequest, openLink("knbp_wrong"))
+
+		resErr, resCode := send(authPasswordResetUrl, requests.AuthPasswordResetRequest{
+			Token:    "knbp_wrong",
+			Password: "new-pass",
+		})
+		assert.Equal(tt, http.StatusBadRequest, resCode)
+		assert.Equal(tt, "reset link is invalid or expired", resErr.Message)
+
+		_, resCode = send(authPasswordResetUrl, requests.AuthPasswordResetRequest{
+			Token:    resetToken,
+			Password: "new-pass",
+		})
+		assert.Equal(tt, http.StatusNoContent, resCode)
+		assert.True(tt, canLogin("new-pass"))
+		assert.False(tt, canLogin(fixtures.Player1Password))
+
+		_, resCode = sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: []*testRequestHeader{{key: authorizationToken, value: accessToken}},
+			method:  http.MethodGet,
+			url:     authSessionsUrl,
+		})
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+
+		_, resCode = send(authPasswordResetUrl, requests.AuthPasswordResetRequest{
+			Token:    resetToken,
+			Password: "another-pass",
+		})
+		assert.Equal(tt, http.StatusBadRequest, resCode)
+		assert.True(tt, canLogin("new-pass"))
+		assert.Equal(tt, http.StatusBadRequest, openLink(resetToken))
+	})
+
+	t.Run("change password", func(tt *testing.T) {
+		accessToken, err := newAuthToken(layers, playerId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		otherAccessToken, err := newAuthToken(layers, playerId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		header := &testRequestHeader{key: authorizationToken, value: accessToken}
+
+		resErr, resCode := send(authPasswordChangeUrl, requests.AuthPasswordChangeRequest{
+			CurrentPassword: "wrong-pass",
+			Password:        "changed-pass",
+		}, header)
+		assert.Equal(tt, http.StatusBadRequest, resCode)
+		assert.Equal(tt, "current password is incorrect", resErr.Message)
+
+		_, resCode = send(authPasswordChangeUrl, requests.AuthPasswordChangeRequest{
+			CurrentPassword: "new-pass",
+			Password:        "changed-pass",
+		}, header)
+		assert.Equal(tt, http.StatusNoContent, resCode)
+		assert.True(tt, canLogin("changed-pass"))
+
+		for token, expectedCode := range map[string]int{
+			accessToken:      http.StatusOK,
+			otherAccessToken: http.StatusUnauthorized,
+		} {
+			_, resCode = sendRequestAndGetResponse(requestData{
+				router:  layers.router,
+				headers: []*testRequestHeader{{key: authorizationToken, value: token}},
+				method:  http.MethodGet,
+				url:     authSessionsUrl,
+			})
+			assert.Equal(tt, expectedCode, resCode)
+		}
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
+
+func TestAuthLoginThrottle(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	login := func(password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(requests.AuthLoginRequest{
+			Login:    fixtures.Player1Email,
+			Password: password,
+		})
+		req, _ := http.NewRequest(http.MethodPost, authLoginUrl, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		layers.router.ServeHTTP(w, req)
+
+		return w
+	}
+
+	t.Run("successful login resets the failures", func(tt *testing.T) {
+		assert.Equal(tt, http.StatusUnauthorized, login("wrong-pass").Code)
+		assert.Equal(tt, http.StatusOK, login(fixtures.Player1Password).Code)
+	})
+
+	t.Run("account is locked out", func(tt *testing.T) {
+		freeAttempts := layers.bootstrap.Config().LoginThrottleConfig.AccountFreeAttempts
+		for i := 0; i < freeAttempts; i++ {
+			assert.Equal(tt, http.StatusUnauthorized, login("wrong-pass").Code)
+		}
+
+		locked := login("wrong-pass")
+		assert.Equal(tt, http.StatusTooManyRequests, locked.Code)
+		assert.NotEmpty(tt, locked.Header().Get("Retry-After"))
+
+		locked = login(fixtures.Player1Password)
+		assert.Equal(tt, http.StatusTooManyRequests, locked.Code)
+		assert.NotEmpty(tt, locked.Header().Get("Retry-After"))
+
+		var failures int64
+		layers.db.Model(&entities.LoginFailure{}).
+			Where("login = ? AND reason = ?", fixtures.Player1Email, dictionary.LoginFailureWrongCredentials).
+			Count(&failures)
+		assert.Equal(tt, int64(freeAttempts+2), failures)
+	})
+
 	if err := layers.bootstrap.TeardownTestDB(); err != nil {
 		t.Errorf("Failed to teardown test DB, %s", err)
 	}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"net/http"
+)
+
+func (h *Handler) chatGameMessages(c *gin.Context) {
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.chatMessages(c, &gameId)
+}
+
+func (h *Handler) chatLobbyMessages(c *gin.Context) {
+	h.chatMessages(c, nil)
+}
+
+func (h *Handler) chatGameSend(c *gin.Context) {
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.chatSend(c, &gameId)
+}
+
+func (h *Handler) chatLobbySend(c *gin.Context) {
+	h.chatSend(c, nil)
+}
+
+// chatMessages polls the chat, the after query is the id of the last message the client has.
+func (h *Handler) chatMessages(c *gin.Context, gameId *uuid.UUID) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	after, err := queryUuid(c, "after")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	messages, err := h.service.Chat.Messages(playerId, gameId, after, limit)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.ChatMessageResponse, 0, len(messages))
+	for index := range messages {
+		response = append(response, newChatMessageResponse(&messages[index], false))
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) chatSend(c *gin.Context, gameId *uuid.UUID) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.ChatMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	message, err := h.service.Chat.Send(playerId, gameId, request.Body)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusCreated, newChatMessageResponse(message, false))
+}
+
+// chatReport takes an optional reason.
+func (h *Handler) chatReport(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	messageId, ok := h.chatMessageIdParam(c)
+	if !ok {
+		return
+	}
+
+	var request requests.ChatReportRequest
+	if c.Request.Body != http.NoBody {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	report, err := h.service.Chat.Report(playerId, messageId, request.Reason)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusCreated, newChatReportResponse(report))
+}
+
+func (h *Handler) adminChatReports(c *gin.Context) {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reports, err := h.service.Chat.Reports(limit)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.ChatReportResponse, 0, len(reports))
+	for index := range reports {
+		response = append(response, newChatReportResponse(&reports[index]))
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) adminChatReportResolve(c *gin.Context) {
+	moderatorId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	reportId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "report id is invalid")
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AdminChatResolveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.service.Chat.Resolve(moderatorId, reportId, request.Hide, request.Reason)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newChatReportResponse(report))
+}
+
+func (h *Handler) chatMessageIdParam(c *gin.Context) (uuid.UUID, bool) {
+	messageId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "message id is invalid")
+		return uuid.Nil, false
+	}
+
+	return messageId, true
+}
+
+// newChatMessageResponse leaves out what a hidden message said, unless it is revealed to the moderators.
+func newChatMessageResponse(message *entities.ChatMessage, reveal bool) responses.ChatMessageResponse {
+	response := responses.ChatMessageResponse{
+		ID:     message.ID,
+		GameID: message.GameID,
+		Player: responses.GamePlayerResponse{
+			ID:   message.PlayerID,
+			Name: message.Player.DisplayName,
+		},
+		Body:      message.Body,
+		Hidden:    message.HiddenAt != nil,
+		CreatedAt: message.CreatedAt,
+	}
+	if response.Hidden && !reveal {
+		response.Body = ""
+	}
+
+	return response
+}
+
+func newChatReportResponse(report *entities.ChatReport) responses.ChatReportResponse {
+	return responses.ChatReportResponse{
+		ID:         report.ID,
+		ReporterID: report.ReporterID,
+		Reason:     report.Reason,
+		Message:    newChatMessageResponse(&report.Message, true),
+		ResolvedAt: report.ResolvedAt,
+		CreatedAt:  report.CreatedAt,
+	}
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"knb/app/services"
+	"knb/tests/fixtures"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const (
+	gameChatUrlPattern       = "/game/%s/chat"
+	lobbyChatUrl             = "/lobby/chat"
+	chatReportUrlPattern     = "/chat/messages/%s/report"
+	adminChatReportsUrl      = "/admin/chat/reports"
+	adminChatResolvePattern  = "/admin/chat/reports/%s/resolve"
+	chatTestProfanity        = "darn"
+	chatTestProfanityMasked  = "****"
+	chatTestMessagesInGame   = 4
+	chatTestMessagesPageSize = 2
+)
+
+type chatTestCase struct {
+	playerId    uuid.UUID
+	method      string
+	requestBody *requests.ChatMessageRequest
+	*expectedError
+	name string
+}
+
+func TestChat(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerOneId := uuid.MustParse(fixtures.Player1Uuid)
+	playerTwoId := uuid.MustParse(fixtures.Player2Uuid)
+	moderatorId := uuid.MustParse(fixtures.Player3Uuid)
+	if _, err := layers.service.Role.Grant(moderatorId, dictionary.RoleModerator, nil); err != nil {
+		t.Fatalf("Failed to grant moderator role, %s", err)
+	}
+	client := newTestClient(t, layers, playerOneId, playerTwoId, moderatorId)
+
+	config := *layers.bootstrap.Config()
+	config.ChatProfanityWords = []string{chatTestProfanity}
+	filteredClient := client.withRouter(
+		NewHandler(services.NewService(layers.repository, &config)).InitRoutes(testRoutesMode),
+	)
+
+	game, err := layers.service.Game.NewGameRequest(playerOneId)
+	if err != nil {
+		t.Fatalf("Failed to create game, %s", err)
+	}
+	if _, err := layers.service.Game.JoinGame(playerTwoId, game.ID); err != nil {
+		t.Fatalf("Failed to join game, %s", err)
+	}
+	gameChatUrl := fmt.Sprintf(gameChatUrlPattern, game.ID)
+
+	chatFailedTestCases := []chatTestCase{
+		{
+			playerId:    playerOneId,
+			method:      http.MethodPost,
+			requestBody: &requests.ChatMessageRequest{Body: "  "},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "message is empty",
+			},
+			name: "empty message",
+		},
+		{
+			playerId:    playerOneId,
+			method:      http.MethodPost,
+			requestBody: &requests.ChatMessageRequest{Body: strings.Repeat("a", 501)},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "message must not exceed 500 characters",
+			},
+			name: "too long message",
+		},
+		{
+			playerId:    moderatorId,
+			method:      http.MethodPost,
+			requestBody: &requests.ChatMessageRequest{Body: "hi"},
+			expectedError: &expectedError{
+				code:    http.StatusForbidden,
+				message: "you can't participate in this game",
+			},
+			name: "write to the chat of another game",
+		},
+		{
+			playerId: moderatorId,
+			method:   http.MethodGet,
+			expectedError: &expectedError{
+				code:    http.StatusForbidden,
+				message: "you can't participate in this game",
+			},
+			name: "read the chat of another game",
+		},
+	}
+
+	for _, tCase := range chatFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			var requestBody interface{}
+			if tCase.requestBody != nil {
+				requestBody = tCase.requestBody
+			}
+			client.sendFailing(tt, tCase.expectedError, tCase.playerId, tCase.method, gameChatUrl, requestBody)
+		})
+	}
+
+	var messages []responses.ChatMessageResponse
+
+	t.Run("game chat", func(tt *testing.T) {
+		for i := 0; i < chatTestMessagesInGame; i++ {
+			playerId := []uuid.UUID{playerOneId, playerTwoId}[i%2]
+			var message responses.ChatMessageResponse
+			resCode := client.send(playerId, http.MethodPost, gameChatUrl, requests.ChatMessageRequest{
+				Body: fmt.Sprintf("message %d", i),
+			}, &message)
+			assert.Equal(tt, http.StatusCreated, resCode)
+			assert.Equal(tt, playerId, message.Player.ID)
+			assert.Equal(tt, &game.ID, message.GameID)
+		}
+	})
+
+	t.Run("pages", func(tt *testing.T) {
+		resCode := client.send(playerTwoId, http.MethodGet, gameChatUrl, nil, &messages)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if !assert.Len(tt, messages, chatTestMessagesInGame) {
+			return
+		}
+		assert.Equal(tt, "message 0", messages[0].Body)
+
+		var page []responses.ChatMessageResponse
+		pageUrl := fmt.Sprintf("%s?limit=%d", gameChatUrl, chatTestMessagesPageSize)
+		client.send(playerTwoId, http.MethodGet, pageUrl, nil, &page)
+		if assert.Len(tt, page, chatTestMessagesPageSize) {
+			assert.Equal(tt, messages[2].ID, page[0].ID)
+		}
+
+		client.send(playerTwoId, http.MethodGet, fmt.Sprintf("%s?after=%s", gameChatUrl, messages[0].ID), nil, &page)
+		if assert.Len(tt, page, chatTestMessagesInGame-1) {
+			assert.Equal(tt, messages[1].ID, page[0].ID)
+		}
+
+		client.send(playerTwoId, http.MethodGet, fmt.Sprintf("%s?after=%s", gameChatUrl, messages[3].ID), nil, &page)
+		assert.Empty(tt, page)
+	})
+
+	t.Run("lobby chat", func(tt *testing.T) {
+		var message responses.ChatMessageResponse
+		resCode := filteredClient.send(moderatorId, http.MethodPost, lobbyChatUrl, requests.ChatMessageRequest{
+			Body: "any " + strings.ToUpper(chatTestProfanity) + " game?",
+		}, &message)
+		assert.Equal(tt, http.StatusCreated, resCode)
+		assert.Equal(tt, "any "+chatTestProfanityMasked+" game?", message.Body)
+		assert.Nil(tt, message.GameID)
+
+		var lobby []responses.ChatMessageResponse
+		resCode = client.send(playerOneId, http.MethodGet, lobbyChatUrl, nil, &lobby)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, lobby, 1) {
+			assert.Equal(tt, message.ID, lobby[0].ID)
+		}
+
+		if len(messages) > 0 {
+			client.sendFailing(tt, &expectedError{
+				code:    http.StatusBadRequest,
+				message: "the cursor is a message of another chat",
+			}, playerOneId, http.MethodGet, fmt.Sprintf("%s?after=%s", lobbyChatUrl, messages[0].ID), nil)
+		}
+	})
+
+	t.Run("report and moderate", func(tt *testing.T) {
+		if len(messages) < chatTestMessagesInGame {
+			tt.Skip("the messages were not sent")
+		}
+		reportUrl := fmt.Sprintf(chatReportUrlPattern, messages[0].ID)
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "you can't report your own message",
+		}, playerOneId, http.MethodPost, reportUrl, nil)
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusForbidden,
+			message: "you can't participate in this game",
+		}, moderatorId, http.MethodPost, reportUrl, nil)
+
+		var report responses.ChatReportResponse
+		resCode := client.send(playerTwoId, http.MethodPost, reportUrl, requests.ChatReportRequest{Reason: "rude"}, &report)
+		assert.Equal(tt, http.StatusCreated, resCode)
+		assert.Equal(tt, "rude", report.Reason)
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusConflict,
+			message: "you have already reported this message",
+		}, playerTwoId, http.MethodPost, reportUrl, nil)
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusForbidden,
+			message: "you have no permission for this action",
+		}, playerTwoId, http.MethodGet, adminChatReportsUrl, nil)
+
+		var reports []responses.ChatReportResponse
+		resCode = client.send(moderatorId, http.MethodGet, adminChatReportsUrl, nil, &reports)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, reports, 1) {
+			assert.Equal(tt, messages[0].ID, reports[0].Message.ID)
+			assert.Equal(tt, "message 0", reports[0].Message.Body)
+		}
+
+		resolveUrl := fmt.Sprintf(adminChatResolvePattern, report.ID)
+		resCode = client.send(moderatorId, http.MethodPost, resolveUrl, requests.AdminChatResolveRequest{
+			Hide:   true,
+			Reason: "insult",
+		}, &report)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.NotNil(tt, report.ResolvedAt)
+		assert.True(tt, report.Message.Hidden)
+		assert.Equal(tt, "message 0", report.Message.Body)
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "the report is already resolved",
+		}, moderatorId, http.MethodPost, resolveUrl, requests.AdminChatResolveRequest{})
+		var resolutions int64
+		layers.db.
+			Model(&entities.AuditEntry{}).
+			Where("target_id = ? AND action = ?", messages[0].ID, dictionary.AuditActionChatResolve).
+			Count(&resolutions)
+		assert.Equal(tt, int64(1), resolutions)
+
+		client.send(moderatorId, http.MethodGet, adminChatReportsUrl, nil, &reports)
+		assert.Empty(tt, reports)
+
+		var chat []responses.ChatMessageResponse
+		client.send(playerOneId, http.MethodGet, gameChatUrl, nil, &chat)
+		if assert.Len(tt, chat, chatTestMessagesInGame) {
+			assert.True(tt, chat[0].Hidden)
+			assert.Empty(tt, chat[0].Body)
+		}
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
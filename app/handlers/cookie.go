@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"knb/app/entities"
+	"net/http"
+	"time"
+)
+
+// Browser clients may keep the tokens in HttpOnly cookies instead of the storage reachable from scripts.
+// Cookie authenticated requests that change state must repeat the csrf cookie in the X-CSRF-Token header.
+const (
+	accessTokenCookie  = "knb_access_token"
+	refreshTokenCookie = "knb_refresh_token"
+	csrfTokenCookie    = "knb_csrf_token"
+	csrfTokenHeader    = "X-CSRF-Token"
+	refreshCookiePath  = "/auth"
+)
+
+func (h *Handler) setAuthCookies(c *gin.Context, tokens *entities.AuthTokens) error {
+	csrfToken, err := h.service.Security.GenerateCsrfToken()
+	if err != nil {
+		return err
+	}
+
+	secure := isSecureRequest(c)
+	setCookie(c, accessTokenCookie, tokens.AccessToken, "/", tokens.RefreshExpiresAt, secure, true)
+	setCookie(c, refreshTokenCookie, tokens.RefreshToken, refreshCookiePath, tokens.RefreshExpiresAt, secure, true)
+	setCookie(c, csrfTokenCookie, csrfToken, "/", tokens.RefreshExpiresAt, secure, false)
+
+	return nil
+}
+
+func (h *Handler) clearAuthCookies(c *gin.Context) {
+	if _, err := c.Cookie(accessTokenCookie); err != nil {
+		return
+	}
+
+	secure := isSecureRequest(c)
+	expired := time.Unix(0, 0)
+	setCookie(c, accessTokenCookie, "", "/", expired, secure, true)
+	setCookie(c, refreshTokenCookie, "", refreshCookiePath, expired, secure, true)
+	setCookie(c, csrfTokenCookie, "", "/", expired, secure, false)
+}
+
+// checkCsrf applies the double submit check to the requests that aren't safe methods.
+func checkCsrf(c *gin.Context) error {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	cookieToken, err := c.Cookie(csrfTokenCookie)
+	if err != nil || cookieToken == "" {
+		return errors.New("csrf token is missing")
+	}
+	headerToken := c.GetHeader(csrfTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(cookieToken), []byte(headerToken)) != 1 {
+		return errors.New("csrf token is invalid")
+	}
+
+	return nil
+}
+
+func setCookie(c *gin.Context, name, value, path string, expiresAt time.Time, secure, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Expires:  expiresAt,
+		Secure:   secure,
+		HttpOnly: httpOnly,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func isSecureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
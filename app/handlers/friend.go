@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/responses"
+	"net/http"
+)
+
+func (h *Handler) friendList(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	friends, err := h.service.Friend.Friends(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.FriendResponse, 0, len(friends))
+	for _, friend := range friends {
+		response = append(response, responses.FriendResponse{
+			PlayerProfileResponse:  newPlayerProfileResponse(friend.Player),
+			PlayerPresenceResponse: newPlayerPresenceResponse(friend.Presence),
+			Since:                  friend.Since,
+		})
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) friendRemove(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	friendId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Friend.Remove(playerId, friendId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) friendRequests(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	friendships, err := h.service.Friend.Requests(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := responses.FriendRequestsResponse{
+		Incoming: make([]responses.FriendRequestResponse, 0),
+		Outgoing: make([]responses.FriendRequestResponse, 0),
+	}
+	for index := range friendships {
+		friendship := &friendships[index]
+		request := responses.FriendRequestResponse{
+			Player:    newPlayerProfileResponse(friendship.Other(playerId)),
+			CreatedAt: friendship.CreatedAt,
+		}
+		if friendship.AddresseeID == playerId {
+			response.Incoming = append(response.Incoming, request)
+		} else {
+			response.Outgoing = append(response.Outgoing, request)
+		}
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+// friendRequestSend answers 200 instead of 201 when the other player had already asked and they are friends now.
+func (h *Handler) friendRequestSend(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	otherId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	friendship, err := h.service.Friend.SendRequest(playerId, otherId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if friendship.Status == dictionary.FriendshipStatusAccepted {
+		status = http.StatusOK
+	}
+	h.response.NewOkResponse(c, status, newFriendshipResponse(playerId, friendship))
+}
+
+func (h *Handler) friendRequestAccept(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	requesterId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	friendship, err := h.service.Friend.AcceptRequest(playerId, requesterId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newFriendshipResponse(playerId, friendship))
+}
+
+func (h *Handler) friendRequestDelete(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	otherId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Friend.DeleteRequest(playerId, otherId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) blockList(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	blocks, err := h.service.Friend.Blocks(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.BlockResponse, 0, len(blocks))
+	for index := range blocks {
+		response = append(response, responses.BlockResponse{
+			Player:    newPlayerProfileResponse(&blocks[index].Blocked),
+			CreatedAt: blocks[index].CreatedAt,
+		})
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) blockAdd(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	blockedId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Friend.Block(playerId, blockedId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) blockRemove(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	blockedId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Friend.Unblock(playerId, blockedId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func newFriendshipResponse(playerId uuid.UUID, friendship *entities.Friendship) responses.FriendshipResponse {
+	otherId := friendship.AddresseeID
+	if otherId == playerId {
+		otherId = friendship.RequesterID
+	}
+
+	return responses.FriendshipResponse{
+		PlayerID:   otherId,
+		Status:     string(friendship.Status),
+		CreatedAt:  friendship.CreatedAt,
+		AcceptedAt: friendship.AcceptedAt,
+	}
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"testing"
+)
+
+const (
+	friendsUrl              = "/player/me/friends"
+	friendRequestsUrl       = "/player/me/friends/requests"
+	friendRequestUrlPattern = "/player/me/friends/requests/%s"
+	blocksUrl               = "/player/me/blocks"
+)
+
+type friendTestCase struct {
+	method string
+	url    string
+	*expectedError
+	name string
+}
+
+func TestFriends(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerOneId := uuid.MustParse(fixtures.Player1Uuid)
+	playerTwoId := uuid.MustParse(fixtures.Player2Uuid)
+	playerThreeId := uuid.MustParse(fixtures.Player3Uuid)
+	client := newTestClient(t, layers, playerOneId, playerTwoId, playerThreeId)
+	requestUrl := func(playerId uuid.UUID) string {
+		return fmt.Sprintf(friendRequestUrlPattern, playerId)
+	}
+
+	friendFailedTestCases := []friendTestCase{
+		{
+			method: http.MethodPost,
+			url:    requestUrl(playerOneId),
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "you can't be friends with yourself",
+			},
+			name: "request to yourself",
+		},
+		{
+			method: http.MethodPost,
+			url:    requestUrl(playerTwoId) + "/accept",
+			expectedError: &expectedError{
+				code:    http.StatusNotFound,
+				message: "the friend request was not found",
+			},
+			name: "accept a request never sent",
+		},
+		{
+			method: http.MethodDelete,
+			url:    requestUrl(playerTwoId),
+			expectedError: &expectedError{
+				code:    http.StatusNotFound,
+				message: "the friend request was not found",
+			},
+			name: "decline a request never sent",
+		},
+		{
+			method: http.MethodDelete,
+			url:    friendsUrl + "/" + playerThreeId.String(),
+			expectedError: &expectedError{
+				code:    http.StatusNotFound,
+				message: "the player is not your friend",
+			},
+			name: "remove a player who is not a friend",
+		},
+		{
+			method: http.MethodPut,
+			url:    blocksUrl + "/" + playerOneId.String(),
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "you can't block yourself",
+			},
+			name: "block yourself",
+		},
+		{
+			method: http.MethodDelete,
+			url:    blocksUrl + "/" + playerThreeId.String(),
+			expectedError: &expectedError{
+				code:    http.StatusNotFound,
+				message: "the player is not blocked",
+			},
+			name: "unblock a player who is not blocked",
+		},
+	}
+
+	for _, tCase := range friendFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			client.sendFailing(tt, tCase.expectedError, playerOneId, tCase.method, tCase.url, nil)
+		})
+	}
+
+	t.Run("send and accept a request", func(tt *testing.T) {
+		var friendship responses.FriendshipResponse
+		resCode := client.send(playerOneId, http.MethodPost, requestUrl(playerTwoId), nil, &friendship)
+		assert.Equal(tt, http.StatusCreated, resCode)
+		assert.Equal(tt, playerTwoId, friendship.PlayerID)
+		assert.Equal(tt, string(dictionary.FriendshipStatusPending), friendship.Status)
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "the friend request is already sent",
+		}, playerOneId, http.MethodPost, requestUrl(playerTwoId), nil)
+
+		var requests responses.FriendRequestsResponse
+		resCode = client.send(playerTwoId, http.MethodGet, friendRequestsUrl, nil, &requests)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, requests.Incoming, 1) {
+			assert.Equal(tt, playerOneId, requests.Incoming[0].Player.ID)
+		}
+		assert.Empty(tt, requests.Outgoing)
+
+		// Only the addressee accepts.
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusNotFound,
+			message: "the friend request was not found",
+		}, playerOneId, http.MethodPost, requestUrl(playerTwoId)+"/accept", nil)
+
+		resCode = client.send(playerTwoId, http.MethodPost, requestUrl(playerOneId)+"/accept", nil, &friendship)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, string(dictionary.FriendshipStatusAccepted), friendship.Status)
+		assert.NotNil(tt, friendship.AcceptedAt)
+	})
+
+	t.Run("friends list", func(tt *testing.T) {
+		game, err := layers.service.Game.NewGameRequest(playerTwoId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Game.JoinGame(playerThreeId, game.ID)
+		assert.NoError(tt, err)
+		assert.NoError(tt, layers.service.Game.StartGame(playerTwoId, game.ID))
+		assert.NoError(tt, layers.service.Game.StartGame(playerThreeId, game.ID))
+
+		var friends []responses.FriendResponse
+		resCode := client.send(playerOneId, http.MethodGet, friendsUrl, nil, &friends)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, friends, 1) {
+			assert.Equal(tt, playerTwoId, friends[0].ID)
+			assert.True(tt, friends[0].Online)
+			assert.Equal(tt, &game.ID, friends[0].GameID)
+		}
+
+		resCode = client.send(playerTwoId, http.MethodDelete, friendsUrl+"/"+playerOneId.String(), nil, nil)
+		assert.Equal(tt, http.StatusNoContent, resCode)
+
+		resCode = client.send(playerOneId, http.MethodGet, friendsUrl, nil, &friends)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Empty(tt, friends)
+	})
+
+	t.Run("a request sent both ways makes friends", func(tt *testing.T) {
+		resCode := client.send(playerThreeId, http.MethodPost, requestUrl(playerOneId), nil, nil)
+		assert.Equal(tt, http.StatusCreated, resCode)
+
+		var friendship responses.FriendshipResponse
+		resCode = client.send(playerOneId, http.MethodPost, requestUrl(playerThreeId), nil, &friendship)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, string(dictionary.FriendshipStatusAccepted), friendship.Status)
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusNotFound,
+			message: "the friend request was not found",
+		}, playerOneId, http.MethodDelete, requestUrl(playerThreeId), nil)
+	})
+
+	t.Run("decline a request", func(tt *testing.T) {
+		resCode := client.send(playerTwoId, http.MethodPost, requestUrl(playerOneId), nil, nil)
+		assert.Equal(tt, http.StatusCreated, resCode)
+
+		resCode = client.send(playerOneId, http.MethodDelete, requestUrl(playerTwoId), nil, nil)
+		assert.Equal(tt, http.StatusNoContent, resCode)
+
+		var requests responses.FriendRequestsResponse
+		client.send(playerTwoId, http.MethodGet, friendRequestsUrl, nil, &requests)
+		assert.Empty(tt, requests.Outgoing)
+	})
+
+	t.Run("block", func(tt *testing.T) {
+		sharedGame, err := layers.service.Game.NewGameRequest(playerOneId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Game.JoinGame(playerThreeId, sharedGame.ID)
+		assert.NoError(tt, err)
+		key, _, err := layers.service.ApiKey.Create(playerThreeId, "bot", []dictionary.ApiKeyScope{
+			dictionary.ApiKeyScopeGamePlay,
+		})
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		resCode := client.send(playerOneId, http.MethodPut, blocksUrl+"/"+playerThreeId.String(), nil, nil)
+		assert.Equal(tt, http.StatusNoContent, resCode)
+
+		// The game both were waiting in goes on without the blocked player.
+		sharedGame, err = layers.service.Game.FindGame(sharedGame.ID)
+		if assert.NoError(tt, err) && assert.Len(tt, sharedGame.Players, 1) {
+			assert.Equal(tt, playerOneId, sharedGame.Players[0].ID)
+		}
+
+		var friends []responses.FriendResponse
+		client.send(playerOneId, http.MethodGet, friendsUrl, nil, &friends)
+		assert.Empty(tt, friends)
+
+		var blocks []responses.BlockResponse
+		resCode = client.send(playerOneId, http.MethodGet, blocksUrl, nil, &blocks)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, blocks, 1) {
+			assert.Equal(tt, playerThreeId, blocks[0].Player.ID)
+		}
+
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusForbidden,
+			message: "the player doesn't accept your friend requests",
+		}, playerThreeId, http.MethodPost, requestUrl(playerOneId), nil)
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusBadRequest,
+			message: "unblock the player first",
+		}, playerOneId, http.MethodPost, requestUrl(playerThreeId), nil)
+
+		game, err := layers.service.Game.NewGameRequest(playerOneId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusForbidden,
+			message: "you can't join this game",
+		}, playerThreeId, http.MethodPost, gameJoinGameUrl+game.ID.String(), nil)
+		// Nor can their bot join for them.
+		_, resCode = sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: []*testRequestHeader{{key: authorizationApiKey, value: key}},
+			method:  http.MethodPost,
+			url:     gameJoinGameUrl + game.ID.String(),
+		})
+		assert.Equal(tt, http.StatusForbidden, resCode)
+
+		resCode = client.send(playerOneId, http.MethodDelete, blocksUrl+"/"+playerThreeId.String(), nil, nil)
+		assert.Equal(tt, http.StatusNoContent, resCode)
+		client.sendFailing(tt, &expectedError{
+			code:    http.StatusNotFound,
+			message: "the player is not blocked",
+		}, playerOneId, http.MethodDelete, blocksUrl+"/"+playerThreeId.String(), nil)
+
+		resCode = client.send(playerThreeId, http.MethodPost, gameJoinGameUrl+game.ID.String(), nil, nil)
+		assert.Equal(tt, http.StatusOK, resCode)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
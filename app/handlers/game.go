@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
 	"knb/app/handlers/responses"
 	"net/http"
+	"slices"
 )
 
 func (h *Handler) gameNewGame(c *gin.Context) {
@@ -28,20 +33,41 @@ func (h *Handler) gameNewGame(c *gin.Context) {
 	)
 }
 
-func (h *Handler) gameJoinGame(c *gin.Context) {
+func (h *Handler) gamePractice(c *gin.Context) {
 	playerId, err := h.getAccessContext(c)
 	if err != nil {
 		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
-	gameIdParam, err := h.checkGetParam(c, "id")
-	if err != nil {
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.GamePracticeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
 		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	gameId, err := uuid.Parse(gameIdParam)
+
+	game, err := h.service.Game.NewPracticeGame(playerId, dictionary.BotStrategy(request.Strategy))
 	if err != nil {
-		h.response.NewErrorResponse(c, http.StatusBadRequest, "game id is invalid")
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusCreated, newGameStateResponse(game))
+}
+
+func (h *Handler) gameJoinGame(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -69,6 +95,156 @@ func (h *Handler) gameJoinGame(c *gin.Context) {
 	)
 }
 
+func (h *Handler) gameAddBots(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.GameAddBotsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	game, err := h.service.Game.AddBots(playerId, gameId, request.Count, dictionary.BotStrategy(request.Strategy))
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newGameStateResponse(game))
+}
+
 func (h *Handler) gameStart(c *gin.Context) {
-	h.response.NewErrorResponse(c, http.StatusMethodNotAllowed, "/game/start")
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Game.StartGame(playerId, gameId); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) gameMove(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	gameId, err := h.getGameIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.GameMoveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	game, err := h.service.Game.Move(playerId, gameId, dictionary.Throw(request.Throw))
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newGameStateResponse(game))
+}
+
+func (h *Handler) getGameIdParam(c *gin.Context) (uuid.UUID, error) {
+	gameIdParam, err := h.checkGetParam(c, "id")
+	if err != nil {
+		return uuid.Nil, err
+	}
+	gameId, err := uuid.Parse(gameIdParam)
+	if err != nil {
+		return uuid.Nil, errors.New("game id is invalid")
+	}
+
+	return gameId, nil
+}
+
+func newGameStateResponse(game *entities.Game) responses.GameStateResponse {
+	places := make(map[uuid.UUID]uint8, len(game.Result))
+	for _, result := range game.Result {
+		places[result.PlayerID] = result.Place
+	}
+
+	moved := make(map[uuid.UUID]bool, len(game.Players))
+	moves := make([]responses.GameMoveResponse, 0, len(game.Moves))
+	for _, move := range game.Moves {
+		// throws of the running round stay hidden until everybody has moved
+		if move.Round == game.Round && game.Status != dictionary.GameStatusFinished {
+			moved[move.PlayerID] = true
+			continue
+		}
+		moves = append(moves, responses.GameMoveResponse{
+			PlayerID: move.PlayerID,
+			Round:    move.Round,
+			Throw:    string(move.Throw),
+		})
+	}
+
+	players := make([]responses.GameStatePlayerResponse, 0, len(game.Players))
+	for _, player := range game.Players {
+		place, finished := places[player.ID]
+		players = append(players, responses.GameStatePlayerResponse{
+			ID:       player.ID,
+			Name:     player.DisplayName,
+			IsBot:    player.IsBot,
+			Moved:    moved[player.ID],
+			Place:    place,
+			Finished: finished,
+		})
+	}
+
+	return responses.GameStateResponse{
+		ID:          game.ID,
+		Status:      string(game.Status),
+		Round:       game.Round,
+		StartedAt:   game.StartedAt,
+		FinishedAt:  game.FinishedAt,
+		CancelledAt: game.CancelledAt,
+		Prizes:      newGamePrizesResponse(game.Prizes),
+		Players:     players,
+		Moves:       moves,
+	}
+}
+
+func newGamePrizesResponse(prizes []entities.GamePrize) []responses.GamePrizeResponse {
+	response := make([]responses.GamePrizeResponse, 0, len(prizes))
+	for _, prize := range prizes {
+		response = append(response, responses.GamePrizeResponse{Place: prize.Place, Prize: prize.Prize})
+	}
+	slices.SortFunc(response, func(a, b responses.GamePrizeResponse) int {
+		return int(a.Place) - int(b.Place)
+	})
+
+	return response
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/handlers/requests"
 	"knb/app/handlers/responses"
 	"knb/tests/fixtures"
 	"net/http"
@@ -15,6 +17,8 @@ const (
 	gameNewGameUrl   = "/game/new"
 	gameJoinGameUrl  = "/game/join/"
 	gameStartGameUrl = "/game/start/"
+	gamePracticeUrl  = "/game/practice"
+	gameMoveUrl      = "/game/move/"
 
 	nonExistingGameId = "2485e769-aee9-486a-bc66-4ca964d7e617"
 )
@@ -36,7 +40,7 @@ func TestGameNewGame(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	nonExistingAuthToken, err := layers.service.Security.GenerateAuthToken(playerUuid)
+	nonExistingAuthToken, err := newAuthToken(layers, playerUuid)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -84,7 +88,7 @@ func TestGameNewGame(t *testing.T) {
 		})
 	}
 
-	existingAuthToken, err := layers.service.Security.GenerateAuthToken(uuid.MustParse(fixtures.Player1Uuid))
+	existingAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player1Uuid))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -155,11 +159,11 @@ func TestGameJoinGame(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	nonExistingAuthToken, err := layers.service.Security.GenerateAuthToken(playerUuid)
+	nonExistingAuthToken, err := newAuthToken(layers, playerUuid)
 	if err != nil {
 		t.Fatal(err)
 	}
-	existingAuthToken, err := layers.service.Security.GenerateAuthToken(uuid.MustParse(fixtures.Player1Uuid))
+	existingAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player1Uuid))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -250,7 +254,7 @@ func TestGameJoinGame(t *testing.T) {
 		})
 	}
 
-	playerAuthToken, err := layers.service.Security.GenerateAuthToken(uuid.MustParse(fixtures.Player2Uuid))
+	playerAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player2Uuid))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -335,19 +339,19 @@ func TestGameStartGame(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	nonExistingAuthToken, err := layers.service.Security.GenerateAuthToken(playerUuid)
+	nonExistingAuthToken, err := newAuthToken(layers, playerUuid)
 	if err != nil {
 		t.Fatal(err)
 	}
-	playerOneAuthToken, err := layers.service.Security.GenerateAuthToken(uuid.MustParse(fixtures.Player1Uuid))
+	playerOneAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player1Uuid))
 	if err != nil {
 		t.Fatal(err)
 	}
-	playerTwoAuthToken, err := layers.service.Security.GenerateAuthToken(uuid.MustParse(fixtures.Player2Uuid))
+	playerTwoAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player2Uuid))
 	if err != nil {
 		t.Fatal(err)
 	}
-	playerThreeAuthToken, err := layers.service.Security.GenerateAuthToken(uuid.MustParse(fixtures.Player3Uuid))
+	playerThreeAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player3Uuid))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -538,3 +542,254 @@ func TestGameStartGame(t *testing.T) {
 		})
 	}
 }
+
+type gamePracticeTestCase struct {
+	requestBody *requests.GamePracticeRequest
+	*expectedError
+	headers []*testRequestHeader
+	name    string
+}
+
+func TestGamePractice(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player1Uuid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := []*testRequestHeader{
+		{
+			key:   authorizationToken,
+			value: playerAuthToken,
+		},
+	}
+
+	gamePracticeFailedTestCases := []gamePracticeTestCase{
+		{
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "Request is empty.",
+			},
+			headers: headers,
+			name:    "empty request body",
+		},
+		{
+			requestBody: &requests.GamePracticeRequest{
+				Strategy: "cheater",
+			},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "bot strategy is invalid",
+			},
+			headers: headers,
+			name:    "unknown strategy",
+		},
+	}
+
+	for _, tCase := range gamePracticeFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			var body []byte
+			if tCase.requestBody != nil {
+				body, _ = json.Marshal(tCase.requestBody)
+			}
+
+			resBody, resCode := sendRequestAndGetResponse(requestData{
+				router:      layers.router,
+				headers:     tCase.headers,
+				requestBody: body,
+				method:      http.MethodPost,
+				url:         gamePracticeUrl,
+			})
+
+			var resErr responseError
+			err := json.Unmarshal(resBody, &resErr)
+			if isNotError := assert.NoError(tt, err); !isNotError {
+				return
+			}
+			if isNotError := assert.Equal(tt, tCase.expectedError.code, resCode); !isNotError {
+				return
+			}
+			assert.Equal(tt, tCase.expectedError.message, resErr.Message)
+		})
+	}
+
+	gamePracticeSuccessTestCases := []gamePracticeTestCase{
+		{
+			requestBody: &requests.GamePracticeRequest{
+				Strategy: string(dictionary.BotStrategyMarkov),
+			},
+			headers: headers,
+			name:    "success practice game",
+		},
+	}
+
+	for _, tCase := range gamePracticeSuccessTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			body, _ := json.Marshal(tCase.requestBody)
+			resBody, resCode := sendRequestAndGetResponse(requestData{
+				router:      layers.router,
+				headers:     tCase.headers,
+				requestBody: body,
+				method:      http.MethodPost,
+				url:         gamePracticeUrl,
+			})
+
+			var response responses.GameStateResponse
+			err := json.Unmarshal(resBody, &response)
+			if !assert.NoError(tt, err) {
+				t.Errorf("Failed to create practice game, %s", err)
+			}
+			assert.Equal(tt, http.StatusCreated, resCode)
+			assert.Equal(tt, string(dictionary.GameStatusStarted), response.Status)
+			assert.Equal(tt, 2, len(response.Players))
+			assert.False(tt, response.Players[0].IsBot)
+			assert.False(tt, response.Players[0].Moved)
+			assert.True(tt, response.Players[1].IsBot)
+			assert.True(tt, response.Players[1].Moved)
+		})
+	}
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
+
+type gameMoveTestCase struct {
+	requestBody *requests.GameMoveRequest
+	*expectedError
+	headers []*testRequestHeader
+	gameId  string
+	name    string
+}
+
+func TestGameMove(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	game, err := layers.service.Game.NewPracticeGame(
+		uuid.MustParse(fixtures.Player1Uuid),
+		dictionary.BotStrategyRandom,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	playerOneAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player1Uuid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	playerTwoAuthToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player2Uuid))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gameMoveFailedTestCases := []gameMoveTestCase{
+		{
+			requestBody: &requests.GameMoveRequest{
+				Throw: "lizard",
+			},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "throw is invalid",
+			},
+			headers: []*testRequestHeader{
+				{
+					key:   authorizationToken,
+					value: playerOneAuthToken,
+				},
+			},
+			gameId: game.ID.String(),
+			name:   "invalid throw",
+		},
+		{
+			requestBody: &requests.GameMoveRequest{
+				Throw: string(dictionary.ThrowRock),
+			},
+			expectedError: &expectedError{
+				code:    http.StatusForbidden,
+				message: "you can't participate in this game",
+			},
+			headers: []*testRequestHeader{
+				{
+					key:   authorizationToken,
+					value: playerTwoAuthToken,
+				},
+			},
+			gameId: game.ID.String(),
+			name:   "move by non-participant",
+		},
+	}
+
+	for _, tCase := range gameMoveFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			body, _ := json.Marshal(tCase.requestBody)
+			resBody, resCode := sendRequestAndGetResponse(requestData{
+				router:      layers.router,
+				headers:     tCase.headers,
+				requestBody: body,
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s%s", gameMoveUrl, tCase.gameId),
+			})
+
+			var resErr responseError
+			err := json.Unmarshal(resBody, &resErr)
+			if isNotError := assert.NoError(tt, err); !isNotError {
+				return
+			}
+			if isNotError := assert.Equal(tt, tCase.expectedError.code, resCode); !isNotError {
+				return
+			}
+			assert.Equal(tt, tCase.expectedError.message, resErr.Message)
+		})
+	}
+
+	gameMoveSuccessTestCases := []gameMoveTestCase{
+		{
+			requestBody: &requests.GameMoveRequest{
+				Throw: string(dictionary.ThrowRock),
+			},
+			headers: []*testRequestHeader{
+				{
+					key:   authorizationToken,
+					value: playerOneAuthToken,
+				},
+			},
+			gameId: game.ID.String(),
+			name:   "success move",
+		},
+	}
+
+	for _, tCase := range gameMoveSuccessTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			body, _ := json.Marshal(tCase.requestBody)
+			resBody, resCode := sendRequestAndGetResponse(requestData{
+				router:      layers.router,
+				headers:     tCase.headers,
+				requestBody: body,
+				method:      http.MethodPost,
+				url:         fmt.Sprintf("%s%s", gameMoveUrl, tCase.gameId),
+			})
+
+			var response responses.GameStateResponse
+			err := json.Unmarshal(resBody, &response)
+			if !assert.NoError(tt, err) {
+				t.Errorf("Failed to make a move, %s", err)
+			}
+			assert.Equal(tt, http.StatusOK, resCode)
+			assert.Equal(tt, uint(2), response.Round)
+			assert.Equal(tt, 2, len(response.Moves))
+			assert.Equal(tt, fixtures.Player1Uuid, response.Moves[1].PlayerID.String())
+		})
+	}
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
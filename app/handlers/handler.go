@@ -3,13 +3,22 @@ package handlers
 import (
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"knb/app/dictionary"
 	"knb/app/handlers/responses"
 	"knb/app/services"
 )
 
 const (
-	authorizationToken   = "Access-Token"
-	authorizationContext = "authorizationCtx"
+	authorizationHeader         = "Authorization"
+	authenticateHeader          = "WWW-Authenticate"
+	authenticateRealm           = "knb"
+	bearerScheme                = "Bearer"
+	authorizationToken          = "Access-Token"
+	authorizationApiKey         = "Api-Key"
+	authorizationContext        = "authorizationCtx"
+	authorizationApiKeyContext  = "authorizationApiKeyCtx"
+	authorizationSessionContext = "authorizationSessionCtx"
+	authorizationRolesContext   = "authorizationRolesCtx"
 )
 
 type Handler struct {
@@ -28,17 +37,130 @@ func (h *Handler) InitRoutes(mode string) *gin.Engine {
 	gin.SetMode(mode)
 	router := gin.New()
 
+	router.GET("/.well-known/jwks.json", h.jwks)
+	router.GET("/avatars/:name", h.playerAvatar)
+
 	auth := router.Group("/auth")
 	{
 		auth.POST("/registration", h.authRegistration)
 		auth.POST("/login", h.authLogin)
+		auth.POST("/login/2fa", h.authLoginTwoFactor)
+		auth.POST("/refresh", h.authRefresh)
+		auth.GET("/verify-email", h.authVerifyEmail)
+		auth.POST("/verify-email/resend", h.userAccessIdentity, h.humanAccessOnly, h.authVerifyEmailResend)
+		auth.POST("/password/forgot", h.authPasswordForgot)
+		auth.GET("/password/reset", h.authPasswordResetCheck)
+		auth.POST("/password/reset", h.authPasswordReset)
+		auth.POST("/password/change", h.userAccessIdentity, h.humanAccessOnly, h.authPasswordChange)
+		auth.POST("/logout", h.userAccessIdentity, h.humanAccessOnly, h.authLogout)
+		auth.POST("/logout-all", h.userAccessIdentity, h.humanAccessOnly, h.authLogoutAll)
+		auth.GET("/sessions", h.userAccessIdentity, h.humanAccessOnly, h.authSessions)
+		auth.DELETE("/sessions/:id", h.userAccessIdentity, h.humanAccessOnly, h.authSessionRevoke)
+	}
+
+	twoFactor := router.Group("/auth/2fa", h.userAccessIdentity, h.humanAccessOnly)
+	{
+		twoFactor.GET("", h.twoFactorStatus)
+		twoFactor.POST("/enroll", h.twoFactorEnroll)
+		twoFactor.POST("/confirm", h.twoFactorConfirm)
+		twoFactor.POST("/disable", h.twoFactorDisable)
+		twoFactor.POST("/recovery-codes", h.twoFactorRecoveryCodes)
+	}
+
+	player := router.Group("/player")
+	{
+		player.GET("/me", h.userAccessIdentity, h.rateLimit, h.playerMe)
+		player.PATCH("/me", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly, h.playerUpdate)
+		player.PUT("/me/avatar", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly, h.playerAvatarUpload)
+		player.GET("/me/export", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly, h.playerExport)
+		player.DELETE("/me", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly, h.playerDelete)
+		player.POST("/me/restore", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly, h.playerRestore)
+		player.POST("/me/heartbeat", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly, h.playerHeartbeat)
+		player.GET("/:id", h.optionalAccessIdentity, h.playerProfile)
+		player.GET("/:id/stats", h.playerStats)
+	}
+
+	router.GET("/leaderboard", h.userAccessIdentity, h.rateLimit, h.leaderboard)
+
+	lobby := router.Group("/lobby", h.userAccessIdentity, h.rateLimit)
+	{
+		lobby.GET("/players", h.lobbyPlayers)
+		lobby.GET("/chat", h.humanAccessOnly, h.chatLobbyMessages)
+		lobby.POST("/chat", h.humanAccessOnly, h.chatRateLimit, h.chatLobbySend)
+	}
+
+	router.POST("/chat/messages/:id/report", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly, h.chatReport)
+
+	social := router.Group("/player/me", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly)
+	{
+		social.GET("/friends", h.friendList)
+		social.DELETE("/friends/:id", h.friendRemove)
+		social.GET("/friends/requests", h.friendRequests)
+		social.POST("/friends/requests/:id", h.friendRequestSend)
+		social.POST("/friends/requests/:id/accept", h.friendRequestAccept)
+		social.DELETE("/friends/requests/:id", h.friendRequestDelete)
+		social.GET("/blocks", h.blockList)
+		social.PUT("/blocks/:id", h.blockAdd)
+		social.DELETE("/blocks/:id", h.blockRemove)
 	}
 
-	game := router.Group("/game", h.userAccessIdentity)
+	apiKey := router.Group("/apikey", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly)
+	{
+		apiKey.POST("", h.apiKeyCreate)
+		apiKey.GET("", h.apiKeyList)
+		apiKey.DELETE("/:id", h.apiKeyRevoke)
+	}
+
+	canCreate := h.requireScope(dictionary.ApiKeyScopeGameCreate)
+	canPlay := h.requireScope(dictionary.ApiKeyScopeGamePlay)
+
+	game := router.Group("/game", h.userAccessIdentity, h.rateLimit)
+	{
+		game.POST("/new", canCreate, h.botGamesRateLimit, h.gameNewGame)
+		game.POST("/practice", canCreate, h.botGamesRateLimit, h.gamePractice)
+		game.POST("/join/:id", canPlay, h.gameJoinGame)
+		game.POST("/bots/:id", canPlay, h.gameAddBots)
+		game.POST("/start/:id", canPlay, h.gameStart)
+		game.POST("/move/:id", canPlay, h.gameMove)
+		game.GET("/:id/chat", h.humanAccessOnly, h.chatGameMessages)
+		game.POST("/:id/chat", h.humanAccessOnly, h.chatRateLimit, h.chatGameSend)
+	}
+
+	tournament := router.Group("/tournament", h.userAccessIdentity, h.rateLimit)
+	{
+		tournament.POST("/new", canCreate, h.tournamentNew)
+		tournament.POST("/join/:id", canPlay, h.tournamentJoin)
+		tournament.POST("/start/:id", canCreate, h.tournamentStart)
+		tournament.GET("/:id/bracket", h.tournamentBracket)
+		tournament.GET("/:id/standings", h.tournamentStandings)
+	}
+
+	canViewPlayers := h.requirePermission(dictionary.PermissionPlayerView)
+	canBanPlayers := h.requirePermission(dictionary.PermissionPlayerBan)
+	canAdjustPoints := h.requirePermission(dictionary.PermissionPointsAdjust)
+	canCancelGames := h.requirePermission(dictionary.PermissionGameCancel)
+	canEditGames := h.requirePermission(dictionary.PermissionGameEdit)
+	canManageRoles := h.requirePermission(dictionary.PermissionRoleManage)
+	canReadAudit := h.requirePermission(dictionary.PermissionAuditRead)
+	canHandleReports := h.requirePermission(dictionary.PermissionReportHandle)
+
+	admin := router.Group("/admin", h.userAccessIdentity, h.rateLimit, h.humanAccessOnly)
 	{
-		game.POST("/new", h.gameNewGame)
-		game.POST("/join/:id", h.gameJoinGame)
-		game.POST("/start/:id", h.gameStart)
+		admin.GET("/players", canViewPlayers, h.adminPlayerSearch)
+		admin.GET("/players/:id/games", canViewPlayers, h.adminPlayerGames)
+		admin.GET("/players/:id/points", canViewPlayers, h.adminPlayerPoints)
+		admin.POST("/players/:id/points", canAdjustPoints, h.adminPointsAdjust)
+		admin.POST("/players/:id/ban", canBanPlayers, h.adminPlayerBan)
+		admin.DELETE("/players/:id/ban", canBanPlayers, h.adminPlayerUnban)
+		admin.GET("/players/:id/roles", canManageRoles, h.adminPlayerRoles)
+		admin.PUT("/players/:id/roles/:role", canManageRoles, h.adminRoleGrant)
+		admin.DELETE("/players/:id/roles/:role", canManageRoles, h.adminRoleRevoke)
+		admin.POST("/games/:id/cancel", canCancelGames, h.adminGameCancel)
+		admin.POST("/games/:id/finish", canCancelGames, h.adminGameFinish)
+		admin.PUT("/games/:id/prizes", canEditGames, h.adminGamePrizes)
+		admin.GET("/audit", canReadAudit, h.adminAuditLog)
+		admin.GET("/chat/reports", canHandleReports, h.adminChatReports)
+		admin.POST("/chat/reports/:id/resolve", canHandleReports, h.adminChatReportResolve)
 	}
 
 	return router
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 	"knb/app/repositories"
 	"knb/app/services"
@@ -10,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 const (
@@ -18,7 +22,9 @@ const (
 )
 
 type responseError struct {
-	Message string `json:"message"`
+	Code        string     `json:"code"`
+	Message     string     `json:"message"`
+	BannedUntil *time.Time `json:"banned_until"`
 }
 
 type expectedError struct {
@@ -72,6 +78,84 @@ func preparationForTest(t *testing.T) testAppLayers {
 	}
 }
 
+func newAuthToken(layers testAppLayers, playerId uuid.UUID) (string, error) {
+	tokens, err := layers.service.Auth.StartSession(playerId, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	return tokens.AccessToken, nil
+}
+
+// testClient sends the requests of a test on behalf of the players it started a session for.
+type testClient struct {
+	router *gin.Engine
+	tokens map[uuid.UUID]string
+}
+
+func newTestClient(t *testing.T, layers testAppLayers, playerIds ...uuid.UUID) *testClient {
+	client := &testClient{
+		router: layers.router,
+		tokens: make(map[uuid.UUID]string, len(playerIds)),
+	}
+	for _, playerId := range playerIds {
+		token, err := newAuthToken(layers, playerId)
+		if err != nil {
+			t.Fatalf("Failed to create auth token, %s", err)
+		}
+		client.tokens[playerId] = token
+	}
+
+	return client
+}
+
+// withRouter sends the requests of the same players to another router.
+func (c *testClient) withRouter(router *gin.Engine) *testClient {
+	return &testClient{router: router, tokens: c.tokens}
+}
+
+// send posts the request as JSON and decodes the response into the given one, a player without a session
+// (uuid.Nil for instance) sends it unauthorized.
+func (c *testClient) send(playerId uuid.UUID, method, url string, request interface{}, response interface{}) int {
+	var body []byte
+	if request != nil {
+		body, _ = json.Marshal(request)
+	}
+	var headers []*testRequestHeader
+	if token, ok := c.tokens[playerId]; ok {
+		headers = append(headers, &testRequestHeader{key: authorizationToken, value: token})
+	}
+
+	resBody, resCode := sendRequestAndGetResponse(requestData{
+		router:      c.router,
+		headers:     headers,
+		requestBody: body,
+		method:      method,
+		url:         url,
+	})
+	if response != nil {
+		_ = json.Unmarshal(resBody, response)
+	}
+
+	return resCode
+}
+
+// sendFailing sends a request that must fail with the expected error.
+func (c *testClient) sendFailing(
+	tt *testing.T,
+	expected *expectedError,
+	playerId uuid.UUID,
+	method, url string,
+	request interface{},
+) {
+	var resErr responseError
+	resCode := c.send(playerId, method, url, request, &resErr)
+	if isNotError := assert.Equal(tt, expected.code, resCode); !isNotError {
+		return
+	}
+	assert.Equal(tt, expected.message, resErr.Message)
+}
+
 func sendRequestAndGetResponse(data requestData) ([]byte, int) {
 	req, _ := http.NewRequest(data.method, data.url, getRequestBody(data.requestBody))
 	for _, header := range data.headers {
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"knb/app/entities"
+	"knb/app/handlers/responses"
+	"math/big"
+	"net/http"
+)
+
+const jwksMaxAge = 300
+
+// jwks publishes the public token keys (RFC 7517) so other services can verify player tokens.
+func (h *Handler) jwks(c *gin.Context) {
+	publicKeys := h.service.Security.PublicTokenKeys()
+
+	keys := make([]responses.JwkResponse, 0, len(publicKeys))
+	for _, publicKey := range publicKeys {
+		if key, ok := newJwkResponse(publicKey); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", jwksMaxAge))
+	h.response.NewOkResponse(c, http.StatusOK, responses.JwksResponse{Keys: keys})
+}
+
+func newJwkResponse(publicKey entities.TokenPublicKey) (responses.JwkResponse, bool) {
+	key := responses.JwkResponse{
+		Use:       "sig",
+		KeyId:     publicKey.ID,
+		Algorithm: publicKey.Algorithm,
+	}
+
+	switch value := publicKey.Key.(type) {
+	case ed25519.PublicKey:
+		key.KeyType = "OKP"
+		key.Curve = "Ed25519"
+		key.X = base64.RawURLEncoding.EncodeToString(value)
+	case *rsa.PublicKey:
+		key.KeyType = "RSA"
+		key.N = base64.RawURLEncoding.EncodeToString(value.N.Bytes())
+		key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(value.E)).Bytes())
+	default:
+		return key, false
+	}
+
+	return key, true
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"knb/app/handlers/responses"
+	"net/http"
+	"testing"
+)
+
+const jwksUrl = "/.well-known/jwks.json"
+
+func TestJwks(t *testing.T) {
+	layers := preparationForTest(t)
+
+	resBody, resCode := sendRequestAndGetResponse(requestData{
+		router: layers.router,
+		method: http.MethodGet,
+		url:    jwksUrl,
+	})
+
+	var response responses.JwksResponse
+	if isNotError := assert.NoError(t, json.Unmarshal(resBody, &response)); isNotError {
+		assert.Equal(t, http.StatusOK, resCode)
+		assert.Equal(t, len(layers.service.Security.PublicTokenKeys()), len(response.Keys))
+		for _, key := range response.Keys {
+			assert.NotEmpty(t, key.KeyId)
+			assert.NotEqual(t, "HS256", key.Algorithm)
+		}
+	}
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
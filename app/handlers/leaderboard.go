@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/responses"
+	"net/http"
+)
+
+func (h *Handler) leaderboard(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	leaderboard, err := h.service.Leaderboard.Board(
+		playerId,
+		dictionary.LeaderboardBoard(c.Query("board")),
+		dictionary.LeaderboardWindow(c.Query("window")),
+		limit,
+	)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := responses.LeaderboardResponse{
+		Board:   string(leaderboard.Board),
+		Window:  string(leaderboard.Window),
+		Entries: make([]responses.LeaderboardEntryResponse, 0, len(leaderboard.Entries)),
+		Own:     newLeaderboardEntryResponse(&leaderboard.Own),
+	}
+	if !leaderboard.PeriodStart.IsZero() {
+		response.PeriodStart = &leaderboard.PeriodStart
+	}
+	for index := range leaderboard.Entries {
+		response.Entries = append(response.Entries, newLeaderboardEntryResponse(&leaderboard.Entries[index]))
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func newLeaderboardEntryResponse(ranked *entities.RankedEntry) responses.LeaderboardEntryResponse {
+	response := responses.LeaderboardEntryResponse{
+		Player:      newPlayerProfileResponse(&ranked.Entry.Player),
+		Points:      ranked.Entry.Points,
+		GamesPlayed: ranked.Entry.GamesPlayed,
+		GamesWon:    ranked.Entry.GamesWon,
+		WinRate:     ranked.Entry.WinRate,
+	}
+	if ranked.Rank > 0 {
+		rank := ranked.Rank
+		response.Rank = &rank
+	}
+
+	return response
+}
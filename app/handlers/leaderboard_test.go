@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"testing"
+)
+
+const leaderboardUrl = "/leaderboard"
+
+type leaderboardTestCase struct {
+	playerId uuid.UUID
+	query    string
+	*expectedError
+	name string
+}
+
+func TestLeaderboard(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerOneId := uuid.MustParse(fixtures.Player1Uuid)
+	playerTwoId := uuid.MustParse(fixtures.Player2Uuid)
+	playerThreeId := uuid.MustParse(fixtures.Player3Uuid)
+	client := newTestClient(t, layers, playerOneId, playerTwoId, playerThreeId)
+
+	// play runs a game of two players settled in a single round.
+	play := func(winnerId, loserId uuid.UUID) uuid.UUID {
+		game, err := layers.service.Game.NewGameRequest(winnerId)
+		if err != nil {
+			t.Fatalf("Failed to create game, %s", err)
+		}
+		if _, err := layers.service.Game.JoinGame(loserId, game.ID); err != nil {
+			t.Fatalf("Failed to join game, %s", err)
+		}
+		for _, playerId := range []uuid.UUID{winnerId, loserId} {
+			if err := layers.service.Game.StartGame(playerId, game.ID); err != nil {
+				t.Fatalf("Failed to start game, %s", err)
+			}
+		}
+		if _, err := layers.service.Game.Move(winnerId, game.ID, dictionary.ThrowRock); err != nil {
+			t.Fatalf("Failed to move, %s", err)
+		}
+		if _, err := layers.service.Game.Move(loserId, game.ID, dictionary.ThrowScissors); err != nil {
+			t.Fatalf("Failed to move, %s", err)
+		}
+
+		return game.ID
+	}
+	ranks := func(leaderboard responses.LeaderboardResponse) map[uuid.UUID]uint {
+		found := make(map[uuid.UUID]uint, len(leaderboard.Entries))
+		for _, entry := range leaderboard.Entries {
+			if entry.Rank != nil {
+				found[entry.Player.ID] = *entry.Rank
+			}
+		}
+
+		return found
+	}
+
+	play(playerOneId, playerTwoId)
+	secondWinId := play(playerOneId, playerTwoId)
+	play(playerTwoId, playerThreeId)
+	if _, err := layers.service.Admin.AdjustPoints(playerOneId, playerThreeId, 100, "bonus"); err != nil {
+		t.Fatalf("Failed to adjust points, %s", err)
+	}
+
+	leaderboardFailedTestCases := []leaderboardTestCase{
+		{
+			playerId: playerOneId,
+			query:    "?board=losses",
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "board is invalid",
+			},
+			name: "unknown board",
+		},
+		{
+			playerId: playerOneId,
+			query:    "?window=daily",
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "window is invalid",
+			},
+			name: "unknown window",
+		},
+		{
+			expectedError: &expectedError{
+				code:    http.StatusUnauthorized,
+				message: "empty 'Access-Token' header",
+			},
+			name: "unauthenticated",
+		},
+	}
+
+	for _, tCase := range leaderboardFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			client.sendFailing(tt, tCase.expectedError, tCase.playerId, http.MethodGet, leaderboardUrl+tCase.query, nil)
+		})
+	}
+
+	t.Run("wins", func(tt *testing.T) {
+		for _, window := range dictionary.LeaderboardWindows {
+			var leaderboard responses.LeaderboardResponse
+			url := leaderboardUrl + "?board=wins&window=" + string(window)
+			resCode := client.send(playerThreeId, http.MethodGet, url, nil, &leaderboard)
+			if !assert.Equal(tt, http.StatusOK, resCode) {
+				continue
+			}
+			assert.Equal(tt, string(window), leaderboard.Window)
+			assert.Equal(tt, window == dictionary.LeaderboardWindowAllTime, leaderboard.PeriodStart == nil)
+			assert.Equal(tt, map[uuid.UUID]uint{playerOneId: 1, playerTwoId: 2, playerThreeId: 3}, ranks(leaderboard))
+			if assert.NotNil(tt, leaderboard.Own.Rank) {
+				assert.Equal(tt, uint(3), *leaderboard.Own.Rank)
+			}
+		}
+	})
+
+	t.Run("own rank outside the top", func(tt *testing.T) {
+		var leaderboard responses.LeaderboardResponse
+		url := leaderboardUrl + "?board=wins&window=weekly&limit=1"
+		resCode := client.send(playerTwoId, http.MethodGet, url, nil, &leaderboard)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, leaderboard.Entries, 1) {
+			assert.Equal(tt, playerOneId, leaderboard.Entries[0].Player.ID)
+		}
+		assert.Equal(tt, playerTwoId, leaderboard.Own.Player.ID)
+		if assert.NotNil(tt, leaderboard.Own.Rank) {
+			assert.Equal(tt, uint(2), *leaderboard.Own.Rank)
+		}
+		assert.Equal(tt, uint(2), leaderboard.Own.GamesPlayed)
+	})
+
+	t.Run("points", func(tt *testing.T) {
+		var leaderboard responses.LeaderboardResponse
+		resCode := client.send(playerOneId, http.MethodGet, leaderboardUrl, nil, &leaderboard)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, string(dictionary.LeaderboardBoardPoints), leaderboard.Board)
+		assert.Equal(tt, string(dictionary.LeaderboardWindowAllTime), leaderboard.Window)
+		assert.Equal(tt, map[uuid.UUID]uint{playerThreeId: 1, playerOneId: 2, playerTwoId: 2}, ranks(leaderboard))
+		if assert.NotEmpty(tt, leaderboard.Entries) {
+			assert.Equal(tt, 100, leaderboard.Entries[0].Points)
+		}
+	})
+
+	t.Run("win rate needs enough games", func(tt *testing.T) {
+		var leaderboard responses.LeaderboardResponse
+		resCode := client.send(playerOneId, http.MethodGet, leaderboardUrl+"?board=win_rate", nil, &leaderboard)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Empty(tt, leaderboard.Entries)
+		assert.Nil(tt, leaderboard.Own.Rank)
+		assert.Equal(tt, 1.0, leaderboard.Own.WinRate)
+	})
+
+	t.Run("a cancelled game drops out", func(tt *testing.T) {
+		_, _, err := layers.service.Game.Cancel(secondWinId, nil, "testing", nil)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		var leaderboard responses.LeaderboardResponse
+		client.send(playerOneId, http.MethodGet, leaderboardUrl+"?board=wins&window=monthly", nil, &leaderboard)
+		assert.Equal(tt, map[uuid.UUID]uint{playerOneId: 1, playerTwoId: 1, playerThreeId: 3}, ranks(leaderboard))
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
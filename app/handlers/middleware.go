@@ -5,29 +5,222 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/handlers/responses"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
+// userAccessIdentity accepts, in this order, an RFC 6750 bearer token (an access token or an api key),
+// the legacy Access-Token header, the access token cookie of browser clients and the Api-Key header.
 func (h *Handler) userAccessIdentity(c *gin.Context) {
+	if c.GetHeader(authorizationHeader) != "" {
+		token, err := bearerToken(c.GetHeader(authorizationHeader))
+		if err != nil {
+			h.unauthorized(c, err.Error())
+			return
+		}
+		if h.service.Security.IsApiKey(token) {
+			h.apiKeyIdentity(c, token)
+			return
+		}
+
+		h.accessTokenIdentity(c, token)
+		return
+	}
+
+	if c.GetHeader(authorizationToken) == "" {
+		if cookieToken, err := c.Cookie(accessTokenCookie); err == nil && cookieToken != "" {
+			if err := checkCsrf(c); err != nil {
+				h.response.NewErrorResponse(c, http.StatusForbidden, err.Error())
+				return
+			}
+
+			h.accessTokenIdentity(c, cookieToken)
+			return
+		}
+		if c.GetHeader(authorizationApiKey) != "" {
+			h.apiKeyAccessIdentity(c)
+			return
+		}
+	}
+
 	headerToken, err := h.checkHeader(c, authorizationToken)
+	if err != nil {
+		h.unauthorized(c, err.Error())
+		return
+	}
+
+	h.accessTokenIdentity(c, headerToken)
+}
+
+// optionalAccessIdentity identifies the caller when the request carries credentials, anonymous requests go on.
+func (h *Handler) optionalAccessIdentity(c *gin.Context) {
+	cookieToken, _ := c.Cookie(accessTokenCookie)
+	if c.GetHeader(authorizationHeader) == "" &&
+		c.GetHeader(authorizationToken) == "" &&
+		c.GetHeader(authorizationApiKey) == "" &&
+		cookieToken == "" {
+		return
+	}
+
+	h.userAccessIdentity(c)
+}
+
+func (h *Handler) accessTokenIdentity(c *gin.Context, token string) {
+	playerId, sessionId, roles, err := h.service.Security.ParseAuthToken(token)
+	if err != nil {
+		h.invalidToken(c, err)
+		return
+	}
+	if err := h.service.Auth.CheckSession(playerId, sessionId, c.ClientIP()); err != nil {
+		h.invalidToken(c, err)
+		return
+	}
+
+	c.Set(authorizationContext, playerId.String())
+	c.Set(authorizationSessionContext, sessionId)
+	c.Set(authorizationRolesContext, roles)
+	// Only the sessions of people count, bots are not around to be invited.
+	h.service.Presence.Touch(playerId)
+}
+
+func (h *Handler) apiKeyAccessIdentity(c *gin.Context) {
+	headerKey, err := h.checkHeader(c, authorizationApiKey)
+	if err != nil {
+		h.unauthorized(c, err.Error())
+		return
+	}
+
+	h.apiKeyIdentity(c, headerKey)
+}
+
+func (h *Handler) apiKeyIdentity(c *gin.Context, key string) {
+	apiKey, err := h.service.ApiKey.Authenticate(key)
+	if err != nil {
+		h.invalidToken(c, err)
+		return
+	}
+
+	c.Set(authorizationContext, apiKey.BotID.String())
+	c.Set(authorizationApiKeyContext, apiKey)
+}
+
+func (h *Handler) rateLimit(c *gin.Context) {
+	kind := dictionary.RateLimitPlayerRequests
+	if _, ok := h.getApiKeyContext(c); ok {
+		kind = dictionary.RateLimitBotRequests
+	}
+
+	playerId, err := h.getAccessContext(c)
 	if err != nil {
 		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	playerId, err := h.service.Security.ParseAuthToken(headerToken)
+	if allowed, retryAfter := h.service.RateLimit.Allow(kind, playerId.String()); !allowed {
+		h.tooManyRequests(c, retryAfter.Seconds())
+	}
+}
+
+func (h *Handler) botGamesRateLimit(c *gin.Context) {
+	apiKey, ok := h.getApiKeyContext(c)
+	if !ok {
+		return
+	}
+
+	if allowed, retryAfter := h.service.RateLimit.Allow(
+		dictionary.RateLimitBotGames,
+		apiKey.OwnerID.String(),
+	); !allowed {
+		h.tooManyRequests(c, retryAfter.Seconds())
+	}
+}
+
+func (h *Handler) chatRateLimit(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
 	if err != nil {
-		h.response.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	if playerId == "" {
-		h.response.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+	if allowed, retryAfter := h.service.RateLimit.Allow(
+		dictionary.RateLimitChatMessages,
+		playerId.String(),
+	); !allowed {
+		h.tooManyRequests(c, retryAfter.Seconds())
+	}
+}
+
+func (h *Handler) requireScope(scope dictionary.ApiKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey, ok := h.getApiKeyContext(c)
+		if !ok {
+			return
+		}
+
+		if !apiKey.HasScope(scope) {
+			// The scope names hold a colon, the error messages must not.
+			h.response.NewErrorResponse(c, http.StatusForbidden, "api key has no scope for this action")
+		}
+	}
+}
+
+// requirePermission guards a route by the roles of the access token, an api key never has a permission.
+func (h *Handler) requirePermission(permission dictionary.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.getRolesContext(c).Can(permission) {
+			// The permission names hold a colon, the error messages must not.
+			h.response.NewErrorResponse(c, http.StatusForbidden, "you have no permission for this action")
+		}
+	}
+}
+
+func (h *Handler) humanAccessOnly(c *gin.Context) {
+	if _, ok := h.getApiKeyContext(c); ok {
+		h.response.NewErrorResponse(c, http.StatusForbidden, "this action isn't available with an api key")
+	}
+}
+
+// unauthorized answers a request without credentials with the bearer challenge of RFC 6750.
+func (h *Handler) unauthorized(c *gin.Context, message string) {
+	c.Header(authenticateHeader, fmt.Sprintf(`Bearer realm="%s"`, authenticateRealm))
+	h.response.NewErrorResponse(c, http.StatusUnauthorized, message)
+}
+
+func (h *Handler) invalidToken(c *gin.Context, err error) {
+	// The credentials of a banned player are valid, there is nothing to challenge.
+	var bannedErr *customErrors.BannedError
+	if errors.As(err, &bannedErr) {
+		h.response.ParseError(c, err)
 		return
 	}
 
-	c.Set(authorizationContext, playerId)
+	challenge := fmt.Sprintf(`Bearer realm="%s", error="invalid_token"`, authenticateRealm)
+	if code, ok := responses.AuthErrorCode(err); ok {
+		challenge += fmt.Sprintf(`, error_description="%s"`, code)
+	}
+	c.Header(authenticateHeader, challenge)
+	h.response.ParseError(c, err)
+}
+
+func bearerToken(header string) (string, error) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	token = strings.TrimSpace(token)
+	if !found || !strings.EqualFold(scheme, bearerScheme) || token == "" {
+		return "", errors.New(fmt.Sprintf("invalid '%s' header", authorizationHeader))
+	}
+
+	return token, nil
+}
+
+func (h *Handler) tooManyRequests(c *gin.Context, retryAfterSeconds float64) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfterSeconds))))
+	h.response.NewErrorResponse(c, http.StatusTooManyRequests, "Too many requests")
 }
 
 func (h *Handler) checkHeader(c *gin.Context, headerName string) (string, error) {
@@ -67,3 +260,39 @@ func (h *Handler) getAccessContext(c *gin.Context) (uuid.UUID, error) {
 
 	return playerId, nil
 }
+
+func (h *Handler) getSessionContext(c *gin.Context) (uuid.UUID, error) {
+	value, ok := c.Get(authorizationSessionContext)
+	if !ok {
+		return uuid.Nil, errors.New("request is invalid")
+	}
+
+	sessionId, ok := value.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, errors.New("session is invalid")
+	}
+
+	return sessionId, nil
+}
+
+func (h *Handler) getRolesContext(c *gin.Context) entities.Roles {
+	value, ok := c.Get(authorizationRolesContext)
+	if !ok {
+		return nil
+	}
+
+	roles, _ := value.(entities.Roles)
+
+	return roles
+}
+
+func (h *Handler) getApiKeyContext(c *gin.Context) (*entities.ApiKey, bool) {
+	value, ok := c.Get(authorizationApiKeyContext)
+	if !ok {
+		return nil, false
+	}
+
+	apiKey, ok := value.(*entities.ApiKey)
+
+	return apiKey, ok
+}
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	customErrors "knb/app/errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -94,11 +97,81 @@ func TestMiddleware(t *testing.T) {
 		})
 	}
 
+	t.Run("error codes of rejected tokens", func(tt *testing.T) {
+		playerId, err := uuid.NewUUID()
+		if err != nil {
+			tt.Fatal(err)
+		}
+		revokedToken, err := newAuthToken(layers, playerId)
+		if err != nil {
+			tt.Fatal(err)
+		}
+		if err := layers.service.Auth.LogoutAll(playerId); err != nil {
+			tt.Fatal(err)
+		}
+
+		for token, code := range map[string]string{
+			"wrong-access-token": customErrors.TokenMalformedCode,
+			revokedToken:         customErrors.SessionRevokedCode,
+		} {
+			resBody, resCode := sendRequestAndGetResponse(requestData{
+				router:  layers.router,
+				headers: []*testRequestHeader{{key: authorizationToken, value: token}},
+				method:  http.MethodPost,
+				url:     gameNewGameUrl,
+			})
+
+			var resErr responseError
+			if isNotError := assert.NoError(tt, json.Unmarshal(resBody, &resErr)); !isNotError {
+				return
+			}
+			assert.Equal(tt, http.StatusUnauthorized, resCode)
+			assert.Equal(tt, code, resErr.Code)
+		}
+	})
+
+	t.Run("bearer token and cookie credentials", func(tt *testing.T) {
+		playerId, err := uuid.NewUUID()
+		if err != nil {
+			tt.Fatal(err)
+		}
+		accessToken, err := newAuthToken(layers, playerId)
+		if err != nil {
+			tt.Fatal(err)
+		}
+
+		request := func(headers map[string]string) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest(http.MethodGet, apiKeyUrl, nil)
+			for key, value := range headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			layers.router.ServeHTTP(w, req)
+
+			return w
+		}
+
+		assert.Equal(tt, http.StatusOK, request(map[string]string{
+			authorizationHeader: "Bearer " + accessToken,
+		}).Code)
+
+		rejected := request(map[string]string{authorizationHeader: "Basic " + accessToken})
+		assert.Equal(tt, http.StatusUnauthorized, rejected.Code)
+		assert.Equal(tt, `Bearer realm="knb"`, rejected.Header().Get(authenticateHeader))
+
+		bogus := request(map[string]string{authorizationHeader: "Bearer wrong-access-token"})
+		assert.Equal(tt, http.StatusUnauthorized, bogus.Code)
+		assert.Contains(tt, bogus.Header().Get(authenticateHeader), `error="invalid_token"`)
+
+		cookies := fmt.Sprintf("%s=%s; %s=csrf", accessTokenCookie, accessToken, csrfTokenCookie)
+		assert.Equal(tt, http.StatusOK, request(map[string]string{"Cookie": cookies}).Code)
+	})
+
 	playerUuid, err := uuid.NewUUID()
 	if err != nil {
 		t.Fatal(err)
 	}
-	successAuthToken, err := layers.service.Security.GenerateAuthToken(playerUuid)
+	successAuthToken, err := newAuthToken(layers, playerUuid)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -124,11 +197,11 @@ func TestMiddleware(t *testing.T) {
 				url:     authLoginUrl,
 			})
 
-			playerId, err := layers.service.Security.ParseAuthToken(successAuthToken)
+			playerId, _, _, err := layers.service.Security.ParseAuthToken(successAuthToken)
 			if err != nil {
 				t.Errorf("Failed to parse player token, %s", err)
 			}
-			assert.Equal(tt, playerUuid.String(), playerId)
+			assert.Equal(tt, playerUuid, playerId)
 		})
 	}
 }
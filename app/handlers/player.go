@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"knb/app/services"
+	"net/http"
+)
+
+const (
+	avatarFormField = "avatar"
+	// avatarUploadOverhead leaves room for the multipart boundaries and headers around the image.
+	avatarUploadOverhead = 64 << 10
+)
+
+func (h *Handler) playerMe(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	player, err := h.service.Player.Profile(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.playerMeResponse(c, player)
+}
+
+func (h *Handler) playerUpdate(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.PlayerUpdateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	player, err := h.service.Player.UpdateProfile(playerId, &entities.ProfileUpdate{
+		DisplayName: request.DisplayName,
+		AvatarURL:   request.AvatarUrl,
+		Locale:      request.Locale,
+	})
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.playerMeResponse(c, player)
+}
+
+// playerAvatarUpload takes the image from the "avatar" field of a multipart form.
+func (h *Handler) playerAvatarUpload(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxAvatarBytes+avatarUploadOverhead)
+	fileHeader, err := c.FormFile(avatarFormField)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "avatar file is required and must not exceed 2 MB")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxAvatarBytes+1))
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	player, err := h.service.Player.UploadAvatar(playerId, data)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.playerMeResponse(c, player)
+}
+
+func (h *Handler) playerProfile(c *gin.Context) {
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	player, err := h.service.Player.Profile(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+	// The presence is shown to the other players only, the anonymous callers see the profile alone.
+	var presence entities.PlayerPresence
+	if viewerId, err := h.getAccessContext(c); err == nil {
+		presence, err = h.service.Presence.Visible(viewerId, playerId)
+		if err != nil {
+			h.response.ParseError(c, err)
+			return
+		}
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, responses.PlayerPublicProfileResponse{
+		PlayerProfileResponse:  newPlayerProfileResponse(player),
+		PlayerPresenceResponse: newPlayerPresenceResponse(presence),
+	})
+}
+
+// playerHeartbeat keeps an idle client online, any other request does it as well.
+func (h *Handler) playerHeartbeat(c *gin.Context) {
+	h.response.NewNoContentResponse(c)
+}
+
+// lobbyPlayers lists the people around to fill the waiting games.
+func (h *Handler) lobbyPlayers(c *gin.Context) {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	players, err := h.service.Presence.Online(limit)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	response := make([]responses.PlayerPublicProfileResponse, 0, len(players))
+	for _, player := range players {
+		response = append(response, responses.PlayerPublicProfileResponse{
+			PlayerProfileResponse:  newPlayerProfileResponse(player.Player),
+			PlayerPresenceResponse: newPlayerPresenceResponse(player.Presence),
+		})
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func (h *Handler) playerAvatar(c *gin.Context) {
+	avatar, modifiedAt, err := h.service.Player.Avatar(c.Param("name"))
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+	defer func() {
+		_ = avatar.Close()
+	}()
+
+	// Every upload gets a new name, so the file behind a name never changes.
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(c.Writer, c.Request, c.Param("name"), modifiedAt, avatar)
+}
+
+// playerExport hands the data out as a file, the moves of the games are only the player's own.
+func (h *Handler) playerExport(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	export, err := h.service.Player.Export(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	games := make([]responses.GameStateResponse, 0, len(export.Games))
+	for index := range export.Games {
+		games = append(games, newGameStateResponse(&export.Games[index]))
+	}
+	// The own messages are exported whole, even the hidden ones; a reported one as the player saw it.
+	messages := make([]responses.ChatMessageResponse, 0, len(export.ChatMessages))
+	for index := range export.ChatMessages {
+		messages = append(messages, newChatMessageResponse(&export.ChatMessages[index], true))
+	}
+	reports := make([]responses.ChatReportResponse, 0, len(export.ChatReports))
+	for index := range export.ChatReports {
+		report := newChatReportResponse(&export.ChatReports[index])
+		report.Message = newChatMessageResponse(&export.ChatReports[index].Message, false)
+		reports = append(reports, report)
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="knb-export-%s.json"`, playerId))
+	h.response.NewOkResponse(c, http.StatusOK, responses.PlayerExportResponse{
+		Profile:      newPlayerMeResponse(export.Player, export.Roles),
+		Games:        games,
+		Points:       newPointsTransactionsResponse(export.Points),
+		ChatMessages: messages,
+		ChatReports:  reports,
+		ExportedAt:   export.ExportedAt,
+	})
+}
+
+func (h *Handler) playerDelete(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.PlayerDeleteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	player, err := h.service.Player.ScheduleDeletion(playerId, request.Password)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.playerMeResponseWithStatus(c, player, http.StatusAccepted)
+}
+
+func (h *Handler) playerRestore(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	player, err := h.service.Player.CancelDeletion(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.playerMeResponse(c, player)
+}
+
+func newPlayerProfileResponse(player *entities.Player) responses.PlayerProfileResponse {
+	return responses.PlayerProfileResponse{
+		ID:          player.ID,
+		DisplayName: player.DisplayName,
+		AvatarUrl:   player.AvatarURL,
+		IsBot:       player.IsBot,
+		Points:      player.Points,
+		CreatedAt:   player.CreatedAt,
+	}
+}
+
+func newPlayerPresenceResponse(presence entities.PlayerPresence) responses.PlayerPresenceResponse {
+	return responses.PlayerPresenceResponse{
+		Online:     presence.Online,
+		LastSeenAt: presence.LastSeenAt,
+		GameID:     presence.GameID,
+	}
+}
+
+// playerMeResponse reads the roles from the database, the ones of the access token may be stale.
+func (h *Handler) playerMeResponse(c *gin.Context, player *entities.Player) {
+	h.playerMeResponseWithStatus(c, player, http.StatusOK)
+}
+
+func (h *Handler) playerMeResponseWithStatus(c *gin.Context, player *entities.Player, status int) {
+	roles, err := h.service.Role.Roles(player.ID)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, status, newPlayerMeResponse(player, roles))
+}
+
+func newPlayerMeResponse(player *entities.Player, roles entities.Roles) responses.PlayerMeResponse {
+	return responses.PlayerMeResponse{
+		PlayerProfileResponse: newPlayerProfileResponse(player),
+		Email:                 player.Email,
+		EmailVerified:         player.EmailVerifiedAt != nil,
+		Locale:                player.Locale,
+		Roles:                 roles,
+		DeletionScheduledAt:   player.DeletionScheduledAt,
+	}
+}
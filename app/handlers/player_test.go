@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/png"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	playerMeUrl       = "/player/me"
+	playerAvatarUrl   = "/player/me/avatar"
+	playerProfileUrl  = "/player"
+	avatarsUrlPattern = "/avatars/"
+)
+
+type playerUpdateTestCase struct {
+	requestBody *requests.PlayerUpdateRequest
+	*expectedError
+	name string
+}
+
+func TestPlayerProfile(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerId := uuid.MustParse(fixtures.Player1Uuid)
+	client := newTestClient(t, layers, playerId)
+	stringPtr := func(value string) *string {
+		return &value
+	}
+
+	t.Run("registration names the player", func(tt *testing.T) {
+		for login, displayName := range map[string]string{
+			"named@test.com":   "Taken Name",
+			"unnamed@test.com": "",
+		} {
+			var response responses.AuthRegistrationResponse
+			resCode := client.send(uuid.Nil, http.MethodPost, authRegistrationUrl, requests.AuthRegistrationRequest{
+				Login:       login,
+				Password:    fixtures.Player1Password,
+				DisplayName: displayName,
+			}, &response)
+			if !assert.Equal(tt, http.StatusCreated, resCode) {
+				continue
+			}
+
+			player, err := layers.repository.Player.FindById(response.ID)
+			if !assert.NoError(tt, err) {
+				continue
+			}
+			if displayName == "" {
+				assert.Equal(tt, "Player "+response.ID.String()[:8], player.DisplayName)
+			} else {
+				assert.Equal(tt, displayName, player.DisplayName)
+			}
+		}
+	})
+
+	t.Run("view own profile", func(tt *testing.T) {
+		var response responses.PlayerMeResponse
+		resCode := client.send(playerId, http.MethodGet, playerMeUrl, nil, &response)
+		if assert.Equal(tt, http.StatusOK, resCode) {
+			assert.Equal(tt, fixtures.Player1DisplayName, response.DisplayName)
+			assert.Equal(tt, fixtures.Player1Email, response.Email)
+			assert.Equal(tt, "en", response.Locale)
+		}
+	})
+
+	playerUpdateFailedTestCases := []playerUpdateTestCase{
+		{
+			requestBody: &requests.PlayerUpdateRequest{DisplayName: stringPtr(" a ")},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "display name must be from 3 to 32 characters long",
+			},
+			name: "too short display name",
+		},
+		{
+			requestBody: &requests.PlayerUpdateRequest{DisplayName: stringPtr("Bot 1234")},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "this display name is reserved",
+			},
+			name: "reserved display name",
+		},
+		{
+			requestBody: &requests.PlayerUpdateRequest{DisplayName: stringPtr("taken name")},
+			expectedError: &expectedError{
+				code:    http.StatusConflict,
+				message: "This display name is already taken",
+			},
+			name: "taken display name",
+		},
+		{
+			requestBody: &requests.PlayerUpdateRequest{AvatarUrl: stringPtr("javascript:alert(1)")},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "avatar_url must be an http or https URL",
+			},
+			name: "avatar is not a URL",
+		},
+		{
+			requestBody: &requests.PlayerUpdateRequest{Locale: stringPtr("english")},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "locale must be a language tag like en or pt-BR",
+			},
+			name: "invalid locale",
+		},
+	}
+
+	for _, tCase := range playerUpdateFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			client.sendFailing(tt, tCase.expectedError, playerId, http.MethodPatch, playerMeUrl, tCase.requestBody)
+		})
+	}
+
+	t.Run("edit profile", func(tt *testing.T) {
+		var response responses.PlayerMeResponse
+		resCode := client.send(playerId, http.MethodPatch, playerMeUrl, requests.PlayerUpdateRequest{
+			DisplayName: stringPtr("  Johnny   Doe "),
+			AvatarUrl:   stringPtr("https://example.com/johnny.png"),
+			Locale:      stringPtr("pt-BR"),
+		}, &response)
+		if assert.Equal(tt, http.StatusOK, resCode) {
+			assert.Equal(tt, "Johnny Doe", response.DisplayName)
+			assert.Equal(tt, "https://example.com/johnny.png", response.AvatarUrl)
+			assert.Equal(tt, "pt-BR", response.Locale)
+		}
+	})
+
+	t.Run("upload avatar", func(tt *testing.T) {
+		var encoded bytes.Buffer
+		if err := png.Encode(&encoded, newTestImage()); err != nil {
+			tt.Fatal(err)
+		}
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile(avatarFormField, "avatar.png")
+		_, _ = part.Write(encoded.Bytes())
+		_ = writer.Close()
+
+		req, _ := http.NewRequest(http.MethodPut, playerAvatarUrl, &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set(authorizationToken, client.tokens[playerId])
+		w := httptest.NewRecorder()
+		layers.router.ServeHTTP(w, req)
+		assert.Equal(tt, http.StatusOK, w.Code)
+
+		var response responses.PlayerMeResponse
+		if !assert.NoError(tt, json.Unmarshal(w.Body.Bytes(), &response)) {
+			return
+		}
+		_, name, found := strings.Cut(response.AvatarUrl, avatarsUrlPattern)
+		if !assert.True(tt, found) {
+			return
+		}
+		defer func() {
+			_ = os.Remove(filepath.Join(layers.bootstrap.Config().AvatarDir, name))
+		}()
+
+		req, _ = http.NewRequest(http.MethodGet, avatarsUrlPattern+name, nil)
+		w = httptest.NewRecorder()
+		layers.router.ServeHTTP(w, req)
+		assert.Equal(tt, http.StatusOK, w.Code)
+		assert.Equal(tt, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(tt, encoded.Bytes(), w.Body.Bytes())
+
+		resCode := client.send(uuid.Nil, http.MethodGet, avatarsUrlPattern+"missing.png", nil, nil)
+		assert.Equal(tt, http.StatusNotFound, resCode)
+	})
+
+	t.Run("public profile", func(tt *testing.T) {
+		var response map[string]interface{}
+		resCode := client.send(uuid.Nil, http.MethodGet, fmt.Sprintf("%s/%s", playerProfileUrl, playerId), nil, &response)
+		if assert.Equal(tt, http.StatusOK, resCode) {
+			assert.Equal(tt, "Johnny Doe", response["display_name"])
+			assert.NotContains(tt, response, "email")
+			assert.NotContains(tt, response, "locale")
+		}
+
+		resCode = client.send(uuid.Nil, http.MethodGet, fmt.Sprintf("%s/%s", playerProfileUrl, uuid.New()), nil, nil)
+		assert.Equal(tt, http.StatusNotFound, resCode)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
+
+func newTestImage() image.Image {
+	return image.NewRGBA(image.Rect(0, 0, 4, 4))
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"testing"
+)
+
+const (
+	playerHeartbeatUrl = "/player/me/heartbeat"
+	lobbyPlayersUrl    = "/lobby/players"
+)
+
+type presenceTestCase struct {
+	playerId uuid.UUID
+	method   string
+	url      string
+	*expectedError
+	name string
+}
+
+func TestPresence(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerOneId := uuid.MustParse(fixtures.Player1Uuid)
+	playerTwoId := uuid.MustParse(fixtures.Player2Uuid)
+	client := newTestClient(t, layers, playerOneId, playerTwoId)
+
+	presenceFailedTestCases := []presenceTestCase{
+		{
+			playerId: uuid.Nil,
+			method:   http.MethodPost,
+			url:      playerHeartbeatUrl,
+			expectedError: &expectedError{
+				code:    http.StatusUnauthorized,
+				message: "empty 'Access-Token' header",
+			},
+			name: "heartbeat without a session",
+		},
+		{
+			playerId: uuid.Nil,
+			method:   http.MethodGet,
+			url:      lobbyPlayersUrl,
+			expectedError: &expectedError{
+				code:    http.StatusUnauthorized,
+				message: "empty 'Access-Token' header",
+			},
+			name: "lobby without a session",
+		},
+		{
+			playerId: playerOneId,
+			method:   http.MethodGet,
+			url:      lobbyPlayersUrl + "?limit=-1",
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "limit must be a non-negative number",
+			},
+			name: "negative limit",
+		},
+	}
+
+	for _, tCase := range presenceFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			client.sendFailing(tt, tCase.expectedError, tCase.playerId, tCase.method, tCase.url, nil)
+		})
+	}
+
+	profile := func(tt *testing.T) responses.PlayerPublicProfileResponse {
+		var response responses.PlayerPublicProfileResponse
+		resCode := client.send(playerOneId, http.MethodGet, playerProfileUrl+"/"+fixtures.Player2Uuid, nil, &response)
+		assert.Equal(tt, http.StatusOK, resCode)
+
+		return response
+	}
+
+	var gameId uuid.UUID
+
+	t.Run("online after a heartbeat", func(tt *testing.T) {
+		response := profile(tt)
+		assert.False(tt, response.Online)
+		assert.Nil(tt, response.LastSeenAt)
+
+		resCode := client.send(playerTwoId, http.MethodPost, playerHeartbeatUrl, nil, nil)
+		assert.Equal(tt, http.StatusNoContent, resCode)
+
+		response = profile(tt)
+		assert.True(tt, response.Online)
+		assert.NotNil(tt, response.LastSeenAt)
+		assert.Nil(tt, response.GameID)
+	})
+
+	t.Run("the game a player is in", func(tt *testing.T) {
+		var game responses.GameNewGameResponse
+		resCode := client.send(playerTwoId, http.MethodPost, gameNewGameUrl, nil, &game)
+		if !assert.Equal(tt, http.StatusCreated, resCode) {
+			return
+		}
+		gameId = game.ID
+
+		response := profile(tt)
+		assert.True(tt, response.Online)
+		assert.Equal(tt, &gameId, response.GameID)
+	})
+
+	t.Run("lobby", func(tt *testing.T) {
+		var players []responses.PlayerPublicProfileResponse
+		resCode := client.send(playerOneId, http.MethodGet, lobbyPlayersUrl, nil, &players)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.Len(tt, players, 2) {
+			for _, player := range players {
+				assert.True(tt, player.Online)
+				assert.False(tt, player.IsBot)
+			}
+		}
+
+		resCode = client.send(playerOneId, http.MethodGet, lobbyPlayersUrl+"?limit=1", nil, &players)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Len(tt, players, 1)
+	})
+
+	t.Run("back in the lobby when the game is over", func(tt *testing.T) {
+		if gameId == uuid.Nil {
+			tt.Skip("the game was not created")
+		}
+		_, err := layers.service.Game.ForceFinish(gameId, nil, nil)
+		assert.NoError(tt, err)
+
+		response := profile(tt)
+		assert.True(tt, response.Online)
+		assert.Nil(tt, response.GameID)
+	})
+
+	t.Run("back in the lobby when taken out of a game by a block", func(tt *testing.T) {
+		blockerId := uuid.MustParse(fixtures.Player3Uuid)
+		game, err := layers.service.Game.NewGameRequest(blockerId)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		_, err = layers.service.Game.JoinGame(playerTwoId, game.ID)
+		if !assert.NoError(tt, err) {
+			return
+		}
+		assert.Equal(tt, &game.ID, profile(tt).GameID)
+
+		assert.NoError(tt, layers.service.Friend.Block(blockerId, playerTwoId))
+
+		response := profile(tt)
+		assert.True(tt, response.Online)
+		assert.Nil(tt, response.GameID)
+	})
+
+	t.Run("hidden from anonymous callers and the blocked players", func(tt *testing.T) {
+		var response responses.PlayerPublicProfileResponse
+		resCode := client.send(uuid.Nil, http.MethodGet, playerProfileUrl+"/"+fixtures.Player2Uuid, nil, &response)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, playerTwoId, response.ID)
+		assert.False(tt, response.Online)
+		assert.Nil(tt, response.LastSeenAt)
+
+		assert.NoError(tt, layers.service.Friend.Block(playerTwoId, playerOneId))
+
+		response = profile(tt)
+		assert.False(tt, response.Online)
+		assert.Nil(tt, response.LastSeenAt)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
@@ -0,0 +1,31 @@
+package requests
+
+type AdminReasonRequest struct {
+	Reason string `json:"reason"`
+}
+
+type AdminBanRequest struct {
+	Reason string `json:"reason" binding:"required"`
+	// DurationMinutes bans for good when it is zero.
+	DurationMinutes uint `json:"duration_minutes"`
+}
+
+type AdminPointsAdjustRequest struct {
+	Amount int    `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AdminGameFinishRequest struct {
+	WinnerId *string `json:"winner_id"`
+	Reason   string  `json:"reason"`
+}
+
+type AdminGamePrize struct {
+	Place uint8 `json:"place"`
+	Prize uint  `json:"prize"`
+}
+
+type AdminGamePrizesRequest struct {
+	Prizes []AdminGamePrize `json:"prizes"`
+	Reason string           `json:"reason"`
+}
@@ -0,0 +1,6 @@
+package requests
+
+type ApiKeyCreateRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required"`
+}
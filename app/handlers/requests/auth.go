@@ -1,11 +1,32 @@
 package requests
 
 type AuthRegistrationRequest struct {
-	Login    string `json:"login" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Login       string `json:"login" binding:"required"`
+	Password    string `json:"password" binding:"required"`
+	DisplayName string `json:"display_name"`
 }
 
 type AuthLoginRequest struct {
 	Login    string `json:"login" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// Cookie asks to keep the tokens in HttpOnly cookies instead of returning them.
+	Cookie bool `json:"cookie"`
+}
+
+type AuthRefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type AuthPasswordForgotRequest struct {
+	Login string `json:"login" binding:"required"`
+}
+
+type AuthPasswordResetRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type AuthPasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Password        string `json:"password" binding:"required"`
 }
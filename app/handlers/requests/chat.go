@@ -0,0 +1,15 @@
+package requests
+
+type ChatMessageRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type ChatReportRequest struct {
+	Reason string `json:"reason"`
+}
+
+// AdminChatResolveRequest hides the reported message when the moderator finds it abusive.
+type AdminChatResolveRequest struct {
+	Hide   bool   `json:"hide"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,14 @@
+package requests
+
+type GamePracticeRequest struct {
+	Strategy string `json:"strategy" binding:"required"`
+}
+
+type GameAddBotsRequest struct {
+	Count    int    `json:"count" binding:"required"`
+	Strategy string `json:"strategy" binding:"required"`
+}
+
+type GameMoveRequest struct {
+	Throw string `json:"throw" binding:"required"`
+}
@@ -0,0 +1,13 @@
+package requests
+
+// PlayerUpdateRequest changes only the fields that are present, an empty avatar_url removes the avatar.
+type PlayerUpdateRequest struct {
+	DisplayName *string `json:"display_name"`
+	AvatarUrl   *string `json:"avatar_url"`
+	Locale      *string `json:"locale"`
+}
+
+// PlayerDeleteRequest asks for the password again, a stolen access token must not be enough.
+type PlayerDeleteRequest struct {
+	Password string `json:"password" binding:"required"`
+}
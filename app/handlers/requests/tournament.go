@@ -0,0 +1,9 @@
+package requests
+
+type TournamentNewRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Type          string `json:"type" binding:"required"`
+	Seeding       string `json:"seeding"`
+	NoShowMinutes uint   `json:"no_show_minutes"`
+	Rounds        uint   `json:"rounds"`
+}
@@ -0,0 +1,12 @@
+package requests
+
+type TwoFactorCodeRequest struct {
+	// Code is a TOTP code or, except for the confirmation, a recovery code.
+	Code string `json:"code" binding:"required"`
+}
+
+type AuthLoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+	Cookie         bool   `json:"cookie"`
+}
@@ -0,0 +1,71 @@
+package responses
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type AdminPlayerResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email"`
+	DisplayName   string    `json:"display_name"`
+	IsBot         bool      `json:"is_bot"`
+	Points        uint      `json:"points"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type AdminPlayerGameResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	Status      string              `json:"status"`
+	Round       uint                `json:"round"`
+	Players     int                 `json:"players"`
+	Place       uint8               `json:"place,omitempty"`
+	Prizes      []GamePrizeResponse `json:"prizes"`
+	StartedAt   time.Time           `json:"started_at"`
+	FinishedAt  time.Time           `json:"finished_at"`
+	CancelledAt *time.Time          `json:"cancelled_at,omitempty"`
+}
+
+type PointsTransactionResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	PlayerID  uuid.UUID  `json:"player_id"`
+	Amount    int        `json:"amount"`
+	Kind      string     `json:"kind"`
+	GameID    *uuid.UUID `json:"game_id,omitempty"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+	Reason    string     `json:"reason"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type AdminPlayerPointsResponse struct {
+	PlayerID     uuid.UUID                   `json:"player_id"`
+	Balance      uint                        `json:"balance"`
+	Transactions []PointsTransactionResponse `json:"transactions"`
+}
+
+type BanResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	PlayerID  uuid.UUID  `json:"player_id"`
+	Reason    string     `json:"reason"`
+	IssuedBy  *uuid.UUID `json:"issued_by,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type AdminGameCancelResponse struct {
+	Game    GameStateResponse           `json:"game"`
+	Refunds []PointsTransactionResponse `json:"refunds"`
+}
+
+type AuditEntryResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	ActorID    *uuid.UUID      `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   uuid.UUID       `json:"target_id"`
+	Reason     string          `json:"reason"`
+	Details    json.RawMessage `json:"details"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
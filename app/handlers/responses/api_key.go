@@ -0,0 +1,22 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ApiKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	BotID      uuid.UUID  `json:"bot_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ApiKeyCreateResponse struct {
+	Key string `json:"key"`
+	ApiKeyResponse
+}
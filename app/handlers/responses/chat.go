@@ -0,0 +1,24 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ChatMessageResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	GameID    *uuid.UUID         `json:"game_id,omitempty"`
+	Player    GamePlayerResponse `json:"player"`
+	Body      string             `json:"body"`
+	Hidden    bool               `json:"hidden"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type ChatReportResponse struct {
+	ID         uuid.UUID           `json:"id"`
+	ReporterID uuid.UUID           `json:"reporter_id"`
+	Reason     string              `json:"reason"`
+	Message    ChatMessageResponse `json:"message"`
+	ResolvedAt *time.Time          `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type FriendResponse struct {
+	PlayerProfileResponse
+	PlayerPresenceResponse
+	Since time.Time `json:"since"`
+}
+
+type FriendshipResponse struct {
+	PlayerID   uuid.UUID  `json:"player_id"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+type FriendRequestResponse struct {
+	Player    PlayerProfileResponse `json:"player"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+type FriendRequestsResponse struct {
+	Incoming []FriendRequestResponse `json:"incoming"`
+	Outgoing []FriendRequestResponse `json:"outgoing"`
+}
+
+type BlockResponse struct {
+	Player    PlayerProfileResponse `json:"player"`
+	CreatedAt time.Time             `json:"created_at"`
+}
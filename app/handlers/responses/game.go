@@ -19,3 +19,35 @@ type GameJoinGameResponse struct {
 	StartedAt time.Time            `json:"started_at"`
 	Players   []GamePlayerResponse `json:"players"`
 }
+
+type GameStatePlayerResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	IsBot    bool      `json:"is_bot"`
+	Moved    bool      `json:"moved"`
+	Place    uint8     `json:"place,omitempty"`
+	Finished bool      `json:"finished"`
+}
+
+type GameMoveResponse struct {
+	PlayerID uuid.UUID `json:"player_id"`
+	Round    uint      `json:"round"`
+	Throw    string    `json:"throw"`
+}
+
+type GamePrizeResponse struct {
+	Place uint8 `json:"place"`
+	Prize uint  `json:"prize"`
+}
+
+type GameStateResponse struct {
+	ID          uuid.UUID                 `json:"id"`
+	Status      string                    `json:"status"`
+	Round       uint                      `json:"round"`
+	StartedAt   time.Time                 `json:"started_at"`
+	FinishedAt  time.Time                 `json:"finished_at"`
+	CancelledAt *time.Time                `json:"cancelled_at,omitempty"`
+	Prizes      []GamePrizeResponse       `json:"prizes"`
+	Players     []GameStatePlayerResponse `json:"players"`
+	Moves       []GameMoveResponse        `json:"moves"`
+}
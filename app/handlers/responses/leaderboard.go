@@ -0,0 +1,24 @@
+package responses
+
+import (
+	"time"
+)
+
+type LeaderboardResponse struct {
+	Board  string `json:"board"`
+	Window string `json:"window"`
+	// PeriodStart is empty for the all-time window.
+	PeriodStart *time.Time                 `json:"period_start,omitempty"`
+	Entries     []LeaderboardEntryResponse `json:"entries"`
+	Own         LeaderboardEntryResponse   `json:"own"`
+}
+
+type LeaderboardEntryResponse struct {
+	// Rank is empty for a player who is not on the board.
+	Rank        *uint                 `json:"rank"`
+	Player      PlayerProfileResponse `json:"player"`
+	Points      int                   `json:"points"`
+	GamesPlayed uint                  `json:"games_played"`
+	GamesWon    uint                  `json:"games_won"`
+	WinRate     float64               `json:"win_rate"`
+}
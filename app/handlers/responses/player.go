@@ -0,0 +1,46 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"time"
+)
+
+type PlayerProfileResponse struct {
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"display_name"`
+	AvatarUrl   string    `json:"avatar_url"`
+	IsBot       bool      `json:"is_bot"`
+	Points      uint      `json:"points"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type PlayerPresenceResponse struct {
+	Online     bool       `json:"online"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	GameID     *uuid.UUID `json:"game_id,omitempty"`
+}
+
+type PlayerPublicProfileResponse struct {
+	PlayerProfileResponse
+	PlayerPresenceResponse
+}
+
+type PlayerMeResponse struct {
+	PlayerProfileResponse
+	Email         string            `json:"email"`
+	EmailVerified bool              `json:"email_verified"`
+	Locale        string            `json:"locale"`
+	Roles         []dictionary.Role `json:"roles"`
+	// DeletionScheduledAt is when the account gets anonymised, unless the player restores it before.
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+}
+
+type PlayerExportResponse struct {
+	Profile      PlayerMeResponse            `json:"profile"`
+	Games        []GameStateResponse         `json:"games"`
+	Points       []PointsTransactionResponse `json:"points"`
+	ChatMessages []ChatMessageResponse       `json:"chat_messages"`
+	ChatReports  []ChatReportResponse        `json:"chat_reports"`
+	ExportedAt   time.Time                   `json:"exported_at"`
+}
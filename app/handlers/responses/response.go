@@ -6,6 +6,7 @@ import (
 	customErrors "knb/app/errors"
 	"net/http"
 	"strings"
+	"time"
 )
 
 type Response struct {
@@ -16,16 +17,39 @@ func NewResponse() *Response {
 }
 
 type errorResponse struct {
+	Code    string `json:"code,omitempty"`
 	Message string `json:"message"`
+	// BannedUntil comes with the account_banned code of a timed ban.
+	BannedUntil *time.Time `json:"banned_until,omitempty"`
 }
 
 func (r *Response) NewErrorResponse(c *gin.Context, statusCode int, message string) {
+	r.NewCodedErrorResponse(c, statusCode, "", message)
+}
+
+// NewCodedErrorResponse adds a machine readable code for the errors clients have to react on.
+func (r *Response) NewCodedErrorResponse(c *gin.Context, statusCode int, code, message string) {
 	c.AbortWithStatusJSON(statusCode, errorResponse{
+		Code:    code,
 		Message: prepareErrorMessage(message),
 	})
 }
 
 func (r *Response) ParseError(c *gin.Context, err error) {
+	if code, ok := AuthErrorCode(err); ok {
+		r.NewCodedErrorResponse(c, http.StatusUnauthorized, code, err.Error())
+		return
+	}
+	var bannedError *customErrors.BannedError
+	if errors.As(err, &bannedError) {
+		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{
+			Code:        customErrors.AccountBannedCode,
+			Message:     prepareErrorMessage(bannedError.Error()),
+			BannedUntil: bannedError.ExpiresAt,
+		})
+		return
+	}
+
 	var statusCode int
 	var message string
 
@@ -33,6 +57,7 @@ func (r *Response) ParseError(c *gin.Context, err error) {
 	var wrongLoginError *customErrors.WrongLoginError
 	var notFoundError *customErrors.NotFoundError
 	var badRequestError *customErrors.BadRequestError
+	var forbiddenError *customErrors.ForbiddenError
 
 	if errors.As(err, &repositoryUniqueViolationError) {
 		statusCode = http.StatusConflict
@@ -46,6 +71,9 @@ func (r *Response) ParseError(c *gin.Context, err error) {
 	} else if errors.As(err, &badRequestError) {
 		statusCode = http.StatusBadRequest
 		message = err.Error()
+	} else if errors.As(err, &forbiddenError) {
+		statusCode = http.StatusForbidden
+		message = err.Error()
 	} else {
 		statusCode = http.StatusInternalServerError
 		message = err.Error()
@@ -58,6 +86,34 @@ func (r *Response) NewOkResponse(c *gin.Context, statusCode int, data interface{
 	c.JSON(statusCode, data)
 }
 
+func (r *Response) NewNoContentResponse(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
+// AuthErrorCode tells an expired access token, which has to be refreshed, from one that is bogus or revoked.
+func AuthErrorCode(err error) (string, bool) {
+	var tokenExpiredError *customErrors.TokenExpiredError
+	var tokenMalformedError *customErrors.TokenMalformedError
+	var tokenSignatureError *customErrors.TokenSignatureError
+	var tokenClaimsError *customErrors.TokenClaimsError
+	var sessionRevokedError *customErrors.SessionRevokedError
+
+	switch {
+	case errors.As(err, &tokenExpiredError):
+		return customErrors.TokenExpiredCode, true
+	case errors.As(err, &tokenMalformedError):
+		return customErrors.TokenMalformedCode, true
+	case errors.As(err, &tokenSignatureError):
+		return customErrors.TokenSignatureInvalidCode, true
+	case errors.As(err, &tokenClaimsError):
+		return customErrors.TokenClaimsInvalidCode, true
+	case errors.As(err, &sessionRevokedError):
+		return customErrors.SessionRevokedCode, true
+	}
+
+	return "", false
+}
+
 func prepareErrorMessage(message string) string {
 	messageParts := strings.Split(message, ":")
 
@@ -0,0 +1,11 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+)
+
+type PlayerRolesResponse struct {
+	PlayerID uuid.UUID         `json:"player_id"`
+	Roles    []dictionary.Role `json:"roles"`
+}
@@ -0,0 +1,43 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type PlayerStatsResponse struct {
+	PlayerID         uuid.UUID                    `json:"player_id"`
+	GamesPlayed      uint                         `json:"games_played"`
+	GamesWon         uint                         `json:"games_won"`
+	WinRate          float64                      `json:"win_rate"`
+	CurrentWinStreak uint                         `json:"current_win_streak"`
+	LongestWinStreak uint                         `json:"longest_win_streak"`
+	MultiPlayerGames uint                         `json:"multi_player_games"`
+	AveragePlace     *float64                     `json:"average_place"`
+	RuleSets         []PlayerRuleSetStatsResponse `json:"rule_sets"`
+	Throws           []PlayerThrowStatsResponse   `json:"throws"`
+	FavouriteThrow   *string                      `json:"favourite_throw"`
+	HeadToHead       *HeadToHeadResponse          `json:"head_to_head,omitempty"`
+	UpdatedAt        time.Time                    `json:"updated_at"`
+}
+
+type PlayerRuleSetStatsResponse struct {
+	RuleSet     string  `json:"rule_set"`
+	GamesPlayed uint    `json:"games_played"`
+	GamesWon    uint    `json:"games_won"`
+	WinRate     float64 `json:"win_rate"`
+}
+
+type PlayerThrowStatsResponse struct {
+	Throw string  `json:"throw"`
+	Count uint    `json:"count"`
+	Share float64 `json:"share"`
+}
+
+type HeadToHeadResponse struct {
+	OpponentID  uuid.UUID `json:"opponent_id"`
+	GamesPlayed uint      `json:"games_played"`
+	Wins        uint      `json:"wins"`
+	Losses      uint      `json:"losses"`
+	Draws       uint      `json:"draws"`
+}
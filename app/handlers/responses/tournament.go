@@ -0,0 +1,56 @@
+package responses
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TournamentResponse struct {
+	ID        uuid.UUID            `json:"id"`
+	Name      string               `json:"name"`
+	Type      string               `json:"type"`
+	Seeding   string               `json:"seeding"`
+	Status    string               `json:"status"`
+	OwnerID   uuid.UUID            `json:"owner_id"`
+	WinnerID  *uuid.UUID           `json:"winner_id"`
+	StartedAt *time.Time           `json:"started_at"`
+	Players   []GamePlayerResponse `json:"players"`
+}
+
+type TournamentMatchResponse struct {
+	ID        uuid.UUID            `json:"id"`
+	Bracket   string               `json:"bracket"`
+	Round     uint                 `json:"round"`
+	Position  uint                 `json:"position"`
+	Status    string               `json:"status"`
+	Players   []GamePlayerResponse `json:"players"`
+	GameID    *uuid.UUID           `json:"game_id"`
+	WinnerID  *uuid.UUID           `json:"winner_id"`
+	Deadline  *time.Time           `json:"deadline"`
+	NextMatch *uuid.UUID           `json:"next_match_id"`
+}
+
+type TournamentBracketResponse struct {
+	TournamentResponse
+	Matches []TournamentMatchResponse `json:"matches"`
+}
+
+type TournamentStandingResponse struct {
+	Rank       uint               `json:"rank"`
+	Player     GamePlayerResponse `json:"player"`
+	Seed       uint               `json:"seed"`
+	Played     uint               `json:"played"`
+	Wins       uint               `json:"wins"`
+	Draws      uint               `json:"draws"`
+	Losses     uint               `json:"losses"`
+	Byes       uint               `json:"byes"`
+	Score      float64            `json:"score"`
+	Buchholz   float64            `json:"buchholz"`
+	HeadToHead float64            `json:"head_to_head"`
+}
+
+type TournamentStandingsResponse struct {
+	TournamentResponse
+	Rounds    uint                         `json:"rounds"`
+	Standings []TournamentStandingResponse `json:"standings"`
+}
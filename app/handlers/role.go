@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/responses"
+	"net/http"
+)
+
+func (h *Handler) adminPlayerRoles(c *gin.Context) {
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.service.Role.Roles(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newPlayerRolesResponse(playerId, roles))
+}
+
+func (h *Handler) adminRoleGrant(c *gin.Context) {
+	adminId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.service.Role.Grant(playerId, dictionary.Role(c.Param("role")), &adminId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newPlayerRolesResponse(playerId, roles))
+}
+
+func (h *Handler) adminRoleRevoke(c *gin.Context) {
+	adminId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.service.Role.Revoke(playerId, dictionary.Role(c.Param("role")), &adminId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newPlayerRolesResponse(playerId, roles))
+}
+
+// playerIdParam answers 400 itself when the id of the path is not a uuid.
+func (h *Handler) playerIdParam(c *gin.Context) (uuid.UUID, bool) {
+	playerIdParam, err := h.checkGetParam(c, "id")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return uuid.Nil, false
+	}
+	playerId, err := uuid.Parse(playerIdParam)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "player id is invalid")
+		return uuid.Nil, false
+	}
+
+	return playerId, true
+}
+
+func newPlayerRolesResponse(playerId uuid.UUID, roles entities.Roles) responses.PlayerRolesResponse {
+	return responses.PlayerRolesResponse{PlayerID: playerId, Roles: roles}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"testing"
+)
+
+const adminPlayerRolesUrlPattern = "/admin/players/%s/roles"
+
+type roleTestCase struct {
+	actorId uuid.UUID
+	method  string
+	url     string
+	*expectedError
+	name string
+}
+
+func TestRoles(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	adminId := uuid.MustParse(fixtures.Player1Uuid)
+	if _, err := layers.service.Role.Grant(adminId, dictionary.RoleAdmin, nil); err != nil {
+		t.Fatalf("Failed to grant admin role, %s", err)
+	}
+	playerId := uuid.MustParse(fixtures.Player2Uuid)
+	client := newTestClient(t, layers, adminId, playerId)
+	playerRolesUrl := fmt.Sprintf(adminPlayerRolesUrlPattern, playerId)
+
+	roleFailedTestCases := []roleTestCase{
+		{
+			actorId: playerId,
+			method:  http.MethodPut,
+			url:     playerRolesUrl + "/moderator",
+			expectedError: &expectedError{
+				code:    http.StatusForbidden,
+				message: "you have no permission for this action",
+			},
+			name: "permission is required",
+		},
+		{
+			actorId: adminId,
+			method:  http.MethodPut,
+			url:     playerRolesUrl + "/owner",
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "role 'owner' doesn't exist",
+			},
+			name: "unknown role",
+		},
+		{
+			actorId: adminId,
+			method:  http.MethodDelete,
+			url:     fmt.Sprintf(adminPlayerRolesUrlPattern, adminId) + "/admin",
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "you can't revoke your own admin role",
+			},
+			name: "own admin role",
+		},
+	}
+
+	for _, tCase := range roleFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			client.sendFailing(tt, tCase.expectedError, tCase.actorId, tCase.method, tCase.url, nil)
+		})
+	}
+
+	t.Run("grant and revoke", func(tt *testing.T) {
+		var me responses.PlayerMeResponse
+		client.send(adminId, http.MethodGet, playerMeUrl, nil, &me)
+		assert.Equal(tt, []dictionary.Role{dictionary.RoleAdmin}, me.Roles)
+
+		var rolesResponse responses.PlayerRolesResponse
+		resCode := client.send(adminId, http.MethodPut, playerRolesUrl+"/moderator", nil, &rolesResponse)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, []dictionary.Role{dictionary.RoleModerator}, rolesResponse.Roles)
+
+		// Granting the role again changes nothing and leaves no entry in the audit log.
+		resCode = client.send(adminId, http.MethodPut, playerRolesUrl+"/moderator", nil, &rolesResponse)
+		assert.Equal(tt, http.StatusOK, resCode)
+		var grants int64
+		layers.db.
+			Model(&entities.AuditEntry{}).
+			Where("target_id = ? AND action = ?", playerId, dictionary.AuditActionRoleGrant).
+			Count(&grants)
+		assert.Equal(tt, int64(1), grants)
+
+		resCode = client.send(adminId, http.MethodGet, playerRolesUrl, nil, &rolesResponse)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, []dictionary.Role{dictionary.RoleModerator}, rolesResponse.Roles)
+
+		resCode = client.send(adminId, http.MethodDelete, playerRolesUrl+"/moderator", nil, &rolesResponse)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Empty(tt, rolesResponse.Roles)
+
+		// The revoked roles must not live on in the issued access tokens.
+		resCode = client.send(playerId, http.MethodGet, playerMeUrl, nil, nil)
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"knb/app/entities"
+	"knb/app/handlers/responses"
+	"net/http"
+)
+
+// playerStats is public like the profile, the opponent query adds the record of the player against them.
+func (h *Handler) playerStats(c *gin.Context) {
+	playerId, ok := h.playerIdParam(c)
+	if !ok {
+		return
+	}
+	opponentId, err := queryUuid(c, "opponent")
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.service.Stats.Find(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+	response := newPlayerStatsResponse(stats)
+
+	if opponentId != nil {
+		headToHead, err := h.service.Stats.HeadToHead(playerId, *opponentId)
+		if err != nil {
+			h.response.ParseError(c, err)
+			return
+		}
+		response.HeadToHead = &responses.HeadToHeadResponse{
+			OpponentID:  headToHead.OpponentID,
+			GamesPlayed: headToHead.GamesPlayed,
+			Wins:        headToHead.Wins,
+			Losses:      headToHead.Losses,
+			Draws:       headToHead.Draws,
+		}
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, response)
+}
+
+func newPlayerStatsResponse(stats *entities.PlayerStats) responses.PlayerStatsResponse {
+	ruleSets := make([]responses.PlayerRuleSetStatsResponse, 0, len(stats.RuleSets))
+	for index := range stats.RuleSets {
+		ruleSet := &stats.RuleSets[index]
+		ruleSets = append(ruleSets, responses.PlayerRuleSetStatsResponse{
+			RuleSet:     string(ruleSet.RuleSet),
+			GamesPlayed: ruleSet.GamesPlayed,
+			GamesWon:    ruleSet.GamesWon,
+			WinRate:     ruleSet.WinRate(),
+		})
+	}
+
+	var total uint
+	for _, throw := range stats.Throws {
+		total += throw.Count
+	}
+	throws := make([]responses.PlayerThrowStatsResponse, 0, len(stats.Throws))
+	var favourite *string
+	var favouriteCount uint
+	for _, throw := range stats.Throws {
+		response := responses.PlayerThrowStatsResponse{Throw: string(throw.Throw), Count: throw.Count}
+		if total > 0 {
+			response.Share = float64(throw.Count) / float64(total)
+		}
+		throws = append(throws, response)
+		if throw.Count > favouriteCount {
+			favourite, favouriteCount = &response.Throw, throw.Count
+		}
+	}
+
+	return responses.PlayerStatsResponse{
+		PlayerID:         stats.PlayerID,
+		GamesPlayed:      stats.GamesPlayed,
+		GamesWon:         stats.GamesWon,
+		WinRate:          stats.WinRate(),
+		CurrentWinStreak: stats.CurrentWinStreak,
+		LongestWinStreak: stats.LongestWinStreak,
+		MultiPlayerGames: stats.MultiPlayerGames,
+		AveragePlace:     stats.AveragePlace(),
+		RuleSets:         ruleSets,
+		Throws:           throws,
+		FavouriteThrow:   favourite,
+		UpdatedAt:        stats.UpdatedAt,
+	}
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"testing"
+)
+
+const playerStatsUrlPattern = "/player/%s/stats"
+
+type statsTestCase struct {
+	url string
+	*expectedError
+	name string
+}
+
+func TestPlayerStats(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	playerOneId := uuid.MustParse(fixtures.Player1Uuid)
+	playerTwoId := uuid.MustParse(fixtures.Player2Uuid)
+	playerThreeId := uuid.MustParse(fixtures.Player3Uuid)
+	client := newTestClient(t, layers)
+
+	// play runs a game of the players, every round is given as the throws in the order of the players.
+	play := func(players []uuid.UUID, rounds ...[]dictionary.Throw) uuid.UUID {
+		game, err := layers.service.Game.NewGameRequest(players[0])
+		if err != nil {
+			t.Fatalf("Failed to create game, %s", err)
+		}
+		for _, playerId := range players[1:] {
+			if _, err := layers.service.Game.JoinGame(playerId, game.ID); err != nil {
+				t.Fatalf("Failed to join game, %s", err)
+			}
+		}
+		for _, playerId := range players {
+			if err := layers.service.Game.StartGame(playerId, game.ID); err != nil {
+				t.Fatalf("Failed to start game, %s", err)
+			}
+		}
+		for _, throws := range rounds {
+			for index, throw := range throws {
+				if _, err := layers.service.Game.Move(players[index], game.ID, throw); err != nil {
+					t.Fatalf("Failed to move, %s", err)
+				}
+			}
+		}
+
+		return game.ID
+	}
+
+	play(
+		[]uuid.UUID{playerOneId, playerTwoId},
+		[]dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowScissors},
+	)
+	// the third player goes out first, then the second one loses to the first
+	play(
+		[]uuid.UUID{playerOneId, playerTwoId, playerThreeId},
+		[]dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowRock, dictionary.ThrowScissors},
+		[]dictionary.Throw{dictionary.ThrowPaper, dictionary.ThrowRock},
+	)
+	lostGameId := play(
+		[]uuid.UUID{playerOneId, playerTwoId},
+		[]dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowPaper},
+	)
+
+	unknownId := uuid.New()
+	statsFailedTestCases := []statsTestCase{
+		{
+			url: fmt.Sprintf(playerStatsUrlPattern, "nope"),
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "player id is invalid",
+			},
+			name: "invalid player id",
+		},
+		{
+			url: fmt.Sprintf(playerStatsUrlPattern+"?opponent=%s", playerOneId, "nope"),
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "opponent is invalid",
+			},
+			name: "invalid opponent id",
+		},
+		{
+			url: fmt.Sprintf(playerStatsUrlPattern+"?opponent=%s", playerOneId, playerOneId),
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "a player has no record against themselves",
+			},
+			name: "record against yourself",
+		},
+		{
+			url: fmt.Sprintf(playerStatsUrlPattern, unknownId),
+			expectedError: &expectedError{
+				code:    http.StatusNotFound,
+				message: fmt.Sprintf("player with id %s not found", unknownId),
+			},
+			name: "unknown player",
+		},
+		{
+			url: fmt.Sprintf(playerStatsUrlPattern+"?opponent=%s", playerOneId, unknownId),
+			expectedError: &expectedError{
+				code:    http.StatusNotFound,
+				message: fmt.Sprintf("player with id %s not found", unknownId),
+			},
+			name: "unknown opponent",
+		},
+	}
+
+	for _, tCase := range statsFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			client.sendFailing(tt, tCase.expectedError, uuid.Nil, http.MethodGet, tCase.url, nil)
+		})
+	}
+
+	t.Run("stats", func(tt *testing.T) {
+		var stats responses.PlayerStatsResponse
+		resCode := client.send(uuid.Nil, http.MethodGet, fmt.Sprintf(playerStatsUrlPattern, playerOneId), nil, &stats)
+		if !assert.Equal(tt, http.StatusOK, resCode) {
+			return
+		}
+		assert.Equal(tt, uint(3), stats.GamesPlayed)
+		assert.Equal(tt, uint(2), stats.GamesWon)
+		assert.InDelta(tt, 2.0/3, stats.WinRate, 0.001)
+		assert.Equal(tt, uint(2), stats.LongestWinStreak)
+		assert.Equal(tt, uint(0), stats.CurrentWinStreak)
+		assert.Equal(tt, uint(1), stats.MultiPlayerGames)
+		if assert.NotNil(tt, stats.AveragePlace) {
+			assert.Equal(tt, 1.0, *stats.AveragePlace)
+		}
+		if assert.Len(tt, stats.RuleSets, 1) {
+			assert.Equal(tt, string(dictionary.GameRuleSetClassic), stats.RuleSets[0].RuleSet)
+			assert.Equal(tt, uint(3), stats.RuleSets[0].GamesPlayed)
+		}
+		assert.Len(tt, stats.Throws, len(dictionary.Throws))
+		if assert.NotNil(tt, stats.FavouriteThrow) {
+			assert.Equal(tt, string(dictionary.ThrowRock), *stats.FavouriteThrow)
+		}
+		assert.Nil(tt, stats.HeadToHead)
+
+		resCode = client.send(uuid.Nil, http.MethodGet, fmt.Sprintf(playerStatsUrlPattern, playerThreeId), nil, &stats)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.NotNil(tt, stats.AveragePlace) {
+			assert.Equal(tt, 3.0, *stats.AveragePlace)
+		}
+	})
+
+	t.Run("head to head", func(tt *testing.T) {
+		var stats responses.PlayerStatsResponse
+		headToHeadUrl := fmt.Sprintf(playerStatsUrlPattern+"?opponent=%s", playerOneId, playerTwoId)
+		resCode := client.send(uuid.Nil, http.MethodGet, headToHeadUrl, nil, &stats)
+		assert.Equal(tt, http.StatusOK, resCode)
+		if assert.NotNil(tt, stats.HeadToHead) {
+			assert.Equal(tt, playerTwoId, stats.HeadToHead.OpponentID)
+			assert.Equal(tt, uint(3), stats.HeadToHead.GamesPlayed)
+			assert.Equal(tt, uint(2), stats.HeadToHead.Wins)
+			assert.Equal(tt, uint(1), stats.HeadToHead.Losses)
+		}
+	})
+
+	t.Run("backfill", func(tt *testing.T) {
+		for _, model := range []interface{}{
+			&entities.PlayerOpponentStats{},
+			&entities.PlayerThrowStats{},
+			&entities.PlayerRuleSetStats{},
+			&entities.PlayerStats{},
+		} {
+			assert.NoError(tt, layers.db.Where("player_id = ?", playerThreeId).Delete(model).Error)
+		}
+
+		var stats responses.PlayerStatsResponse
+		resCode := client.send(uuid.Nil, http.MethodGet, fmt.Sprintf(playerStatsUrlPattern, playerThreeId), nil, &stats)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, uint(0), stats.GamesPlayed)
+
+		if !assert.NoError(tt, layers.service.Stats.Backfill()) {
+			return
+		}
+		client.send(uuid.Nil, http.MethodGet, fmt.Sprintf(playerStatsUrlPattern, playerThreeId), nil, &stats)
+		assert.Equal(tt, uint(1), stats.GamesPlayed)
+		if assert.NotNil(tt, stats.AveragePlace) {
+			assert.Equal(tt, 3.0, *stats.AveragePlace)
+		}
+	})
+
+	t.Run("a cancelled game drops out", func(tt *testing.T) {
+		_, _, err := layers.service.Game.Cancel(lostGameId, nil, "testing", nil)
+		if !assert.NoError(tt, err) {
+			return
+		}
+
+		var stats responses.PlayerStatsResponse
+		client.send(uuid.Nil, http.MethodGet, fmt.Sprintf(playerStatsUrlPattern, playerOneId), nil, &stats)
+		assert.Equal(tt, uint(2), stats.GamesPlayed)
+		assert.Equal(tt, uint(2), stats.GamesWon)
+		assert.Equal(tt, uint(2), stats.CurrentWinStreak)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
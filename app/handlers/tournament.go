@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"net/http"
+)
+
+func (h *Handler) tournamentNew(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.TournamentNewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tournament, err := h.service.Tournament.Create(
+		playerId,
+		request.Name,
+		dictionary.TournamentType(request.Type),
+		dictionary.TournamentSeeding(request.Seeding),
+		request.NoShowMinutes,
+		request.Rounds,
+	)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusCreated, newTournamentResponse(tournament))
+}
+
+func (h *Handler) tournamentJoin(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	tournamentId, err := h.getTournamentIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tournament, err := h.service.Tournament.Join(playerId, tournamentId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newTournamentResponse(tournament))
+}
+
+func (h *Handler) tournamentStart(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	tournamentId, err := h.getTournamentIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tournament, err := h.service.Tournament.Start(playerId, tournamentId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newTournamentBracketResponse(tournament))
+}
+
+func (h *Handler) tournamentBracket(c *gin.Context) {
+	tournamentId, err := h.getTournamentIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tournament, err := h.service.Tournament.Find(tournamentId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newTournamentBracketResponse(tournament))
+}
+
+func (h *Handler) tournamentStandings(c *gin.Context) {
+	tournamentId, err := h.getTournamentIdParam(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tournament, err := h.service.Tournament.Find(tournamentId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+	standings, err := h.service.Tournament.Standings(tournamentId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, newTournamentStandingsResponse(tournament, standings))
+}
+
+func (h *Handler) getTournamentIdParam(c *gin.Context) (uuid.UUID, error) {
+	tournamentIdParam, err := h.checkGetParam(c, "id")
+	if err != nil {
+		return uuid.Nil, err
+	}
+	tournamentId, err := uuid.Parse(tournamentIdParam)
+	if err != nil {
+		return uuid.Nil, errors.New("tournament id is invalid")
+	}
+
+	return tournamentId, nil
+}
+
+func newTournamentResponse(tournament *entities.Tournament) responses.TournamentResponse {
+	players := make([]responses.GamePlayerResponse, 0, len(tournament.Players))
+	for _, player := range tournament.Players {
+		players = append(players, responses.GamePlayerResponse{
+			ID:   player.ID,
+			Name: player.DisplayName,
+		})
+	}
+
+	return responses.TournamentResponse{
+		ID:        tournament.ID,
+		Name:      tournament.Name,
+		Type:      string(tournament.Type),
+		Seeding:   string(tournament.Seeding),
+		Status:    string(tournament.Status),
+		OwnerID:   tournament.OwnerID,
+		WinnerID:  tournament.WinnerID,
+		StartedAt: tournament.StartedAt,
+		Players:   players,
+	}
+}
+
+func newTournamentBracketResponse(tournament *entities.Tournament) responses.TournamentBracketResponse {
+	names := make(map[uuid.UUID]string, len(tournament.Players))
+	for _, player := range tournament.Players {
+		names[player.ID] = player.DisplayName
+	}
+
+	matches := make([]responses.TournamentMatchResponse, 0, len(tournament.Matches))
+	for _, match := range tournament.Matches {
+		players := make([]responses.GamePlayerResponse, 0, 2)
+		for _, playerId := range match.Players() {
+			players = append(players, responses.GamePlayerResponse{
+				ID:   playerId,
+				Name: names[playerId],
+			})
+		}
+
+		matches = append(matches, responses.TournamentMatchResponse{
+			ID:        match.ID,
+			Bracket:   string(match.Bracket),
+			Round:     match.Round,
+			Position:  match.Position,
+			Status:    string(match.Status),
+			Players:   players,
+			GameID:    match.GameID,
+			WinnerID:  match.WinnerID,
+			Deadline:  match.Deadline,
+			NextMatch: match.NextMatchID,
+		})
+	}
+
+	return responses.TournamentBracketResponse{
+		TournamentResponse: newTournamentResponse(tournament),
+		Matches:            matches,
+	}
+}
+
+func newTournamentStandingsResponse(
+	tournament *entities.Tournament,
+	standings []entities.TournamentStanding,
+) responses.TournamentStandingsResponse {
+	names := make(map[uuid.UUID]string, len(tournament.Players))
+	for _, player := range tournament.Players {
+		names[player.ID] = player.DisplayName
+	}
+
+	rows := make([]responses.TournamentStandingResponse, 0, len(standings))
+	for index, standing := range standings {
+		rows = append(rows, responses.TournamentStandingResponse{
+			Rank:       uint(index + 1),
+			Player:     responses.GamePlayerResponse{ID: standing.PlayerID, Name: names[standing.PlayerID]},
+			Seed:       standing.Seed,
+			Played:     standing.Played,
+			Wins:       standing.Wins,
+			Draws:      standing.Draws,
+			Losses:     standing.Losses,
+			Byes:       standing.Byes,
+			Score:      standing.Score,
+			Buchholz:   standing.Buchholz,
+			HeadToHead: standing.HeadToHead,
+		})
+	}
+
+	return responses.TournamentStandingsResponse{
+		TournamentResponse: newTournamentResponse(tournament),
+		Rounds:             tournament.Rounds,
+		Standings:          rows,
+	}
+}
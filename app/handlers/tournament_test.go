@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"testing"
+)
+
+const (
+	tournamentNewUrl   = "/tournament/new"
+	tournamentJoinUrl  = "/tournament/join/"
+	tournamentStartUrl = "/tournament/start/"
+	tournamentUrl      = "/tournament/"
+)
+
+type tournamentNewTestCase struct {
+	requestBody *requests.TournamentNewRequest
+	*expectedError
+	name string
+}
+
+func TestTournament(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	authHeaders := func(playerId string) []*testRequestHeader {
+		token, err := newAuthToken(layers, uuid.MustParse(playerId))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return []*testRequestHeader{
+			{
+				key:   authorizationToken,
+				value: token,
+			},
+		}
+	}
+	ownerHeaders := authHeaders(fixtures.Player1Uuid)
+
+	tournamentNewFailedTestCases := []tournamentNewTestCase{
+		{
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "Request is empty.",
+			},
+			name: "empty request body",
+		},
+		{
+			requestBody: &requests.TournamentNewRequest{
+				Name: "Spring cup",
+				Type: "ladder",
+			},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "tournament type is invalid",
+			},
+			name: "unknown tournament type",
+		},
+		{
+			requestBody: &requests.TournamentNewRequest{
+				Name:   "Spring cup",
+				Type:   string(dictionary.TournamentTypeRoundRobin),
+				Rounds: 3,
+			},
+			expectedError: &expectedError{
+				code:    http.StatusBadRequest,
+				message: "rounds can be set only for a swiss tournament",
+			},
+			name: "rounds of a round-robin tournament",
+		},
+	}
+
+	for _, tCase := range tournamentNewFailedTestCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			var body []byte
+			if tCase.requestBody != nil {
+				body, _ = json.Marshal(tCase.requestBody)
+			}
+
+			resBody, resCode := sendRequestAndGetResponse(requestData{
+				router:      layers.router,
+				headers:     ownerHeaders,
+				requestBody: body,
+				method:      http.MethodPost,
+				url:         tournamentNewUrl,
+			})
+
+			var resErr responseError
+			err := json.Unmarshal(resBody, &resErr)
+			if isNotError := assert.NoError(tt, err); !isNotError {
+				return
+			}
+			if isNotError := assert.Equal(tt, tCase.expectedError.code, resCode); !isNotError {
+				return
+			}
+			assert.Equal(tt, tCase.expectedError.message, resErr.Message)
+		})
+	}
+
+	body, _ := json.Marshal(requests.TournamentNewRequest{
+		Name:    "Spring cup",
+		Type:    string(dictionary.TournamentTypeSingleElimination),
+		Seeding: string(dictionary.TournamentSeedingPoints),
+	})
+	resBody, resCode := sendRequestAndGetResponse(requestData{
+		router:      layers.router,
+		headers:     ownerHeaders,
+		requestBody: body,
+		method:      http.MethodPost,
+		url:         tournamentNewUrl,
+	})
+	var tournament responses.TournamentResponse
+	if err := json.Unmarshal(resBody, &tournament); err != nil {
+		t.Fatalf("Failed to create tournament, %s", err)
+	}
+	assert.Equal(t, http.StatusCreated, resCode)
+	assert.Equal(t, string(dictionary.TournamentStatusRegistration), tournament.Status)
+
+	for _, playerId := range []string{fixtures.Player1Uuid, fixtures.Player2Uuid, fixtures.Player3Uuid} {
+		_, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: authHeaders(playerId),
+			method:  http.MethodPost,
+			url:     fmt.Sprintf("%s%s", tournamentJoinUrl, tournament.ID),
+		})
+		assert.Equal(t, http.StatusOK, resCode)
+	}
+
+	t.Run("start by non-organiser", func(tt *testing.T) {
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: authHeaders(fixtures.Player2Uuid),
+			method:  http.MethodPost,
+			url:     fmt.Sprintf("%s%s", tournamentStartUrl, tournament.ID),
+		})
+
+		var resErr responseError
+		if isNotError := assert.NoError(tt, json.Unmarshal(resBody, &resErr)); !isNotError {
+			return
+		}
+		assert.Equal(tt, http.StatusForbidden, resCode)
+		assert.Equal(tt, "only the organiser can start the tournament", resErr.Message)
+	})
+
+	t.Run("start and bracket", func(tt *testing.T) {
+		_, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: ownerHeaders,
+			method:  http.MethodPost,
+			url:     fmt.Sprintf("%s%s", tournamentStartUrl, tournament.ID),
+		})
+		assert.Equal(tt, http.StatusOK, resCode)
+
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: ownerHeaders,
+			method:  http.MethodGet,
+			url:     fmt.Sprintf("%s%s/bracket", tournamentUrl, tournament.ID),
+		})
+
+		var bracket responses.TournamentBracketResponse
+		if isNotError := assert.NoError(tt, json.Unmarshal(resBody, &bracket)); !isNotError {
+			return
+		}
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Equal(tt, string(dictionary.TournamentStatusRunning), bracket.Status)
+		assert.Equal(tt, 3, len(bracket.Matches))
+
+		playing, byes := 0, 0
+		for _, match := range bracket.Matches {
+			if match.Round != 1 {
+				continue
+			}
+			switch match.Status {
+			case string(dictionary.TournamentMatchStatusPlaying):
+				playing++
+				assert.NotNil(tt, match.GameID)
+			case string(dictionary.TournamentMatchStatusFinished):
+				byes++
+				assert.Nil(tt, match.GameID)
+			}
+		}
+		assert.Equal(tt, 1, playing)
+		assert.Equal(tt, 1, byes)
+	})
+
+	t.Run("standings", func(tt *testing.T) {
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: ownerHeaders,
+			method:  http.MethodGet,
+			url:     fmt.Sprintf("%s%s/standings", tournamentUrl, tournament.ID),
+		})
+
+		var standings responses.TournamentStandingsResponse
+		if isNotError := assert.NoError(tt, json.Unmarshal(resBody, &standings)); !isNotError {
+			return
+		}
+		assert.Equal(tt, http.StatusOK, resCode)
+		if isNotError := assert.Equal(tt, 3, len(standings.Standings)); !isNotError {
+			return
+		}
+		assert.Equal(tt, uint(1), standings.Standings[0].Rank)
+		assert.Equal(tt, uint(1), standings.Standings[0].Byes)
+		assert.Equal(tt, 1.0, standings.Standings[0].Score)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"net/http"
+)
+
+func (h *Handler) twoFactorStatus(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	enabled, recoveryCodesLeft, err := h.service.TwoFactor.Status(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, responses.TwoFactorStatusResponse{
+		Enabled:           enabled,
+		RecoveryCodesLeft: recoveryCodesLeft,
+	})
+}
+
+func (h *Handler) twoFactorEnroll(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	enrollment, err := h.service.TwoFactor.Enroll(playerId)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, responses.TwoFactorEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	})
+}
+
+func (h *Handler) twoFactorConfirm(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	request, ok := h.bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := h.service.TwoFactor.Confirm(playerId, request.Code)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, responses.TwoFactorRecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+func (h *Handler) twoFactorDisable(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	request, ok := h.bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.TwoFactor.Disable(playerId, request.Code); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewNoContentResponse(c)
+}
+
+func (h *Handler) twoFactorRecoveryCodes(c *gin.Context) {
+	playerId, err := h.getAccessContext(c)
+	if err != nil {
+		h.response.NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	request, ok := h.bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := h.service.TwoFactor.RegenerateRecoveryCodes(playerId, request.Code)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.response.NewOkResponse(c, http.StatusOK, responses.TwoFactorRecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// authLoginTwoFactor finishes a login that was answered with a challenge.
+func (h *Handler) authLoginTwoFactor(c *gin.Context) {
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return
+	}
+
+	var request requests.AuthLoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	challengePlayerId, err := h.service.Security.ParseLoginChallengeToken(request.ChallengeToken)
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+	// The codes are throttled per player, the login itself may not be known here.
+	account := challengePlayerId.String()
+	if !h.checkLoginThrottle(c, account) {
+		return
+	}
+	playerId, err := h.service.TwoFactor.CompleteChallenge(request.ChallengeToken, request.Code)
+	if err != nil {
+		h.loginFailed(c, account, entities.NewLoginFailure(
+			&challengePlayerId, account, c.ClientIP(), c.Request.UserAgent(), dictionary.LoginFailureWrongCode,
+		), err)
+		return
+	}
+	if err := h.service.LoginThrottle.Succeeded(account); err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+	tokens, err := h.service.Auth.StartSession(playerId, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		h.response.ParseError(c, err)
+		return
+	}
+
+	h.respondWithTokens(c, tokens, request.Cookie)
+}
+
+func (h *Handler) bindTwoFactorCode(c *gin.Context) (*requests.TwoFactorCodeRequest, bool) {
+	if c.Request.Body == http.NoBody {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, "Request is empty.")
+		return nil, false
+	}
+
+	var request requests.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.response.NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	return &request, true
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/handlers/requests"
+	"knb/app/handlers/responses"
+	"knb/tests/fixtures"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	twoFactorUrl              = "/auth/2fa"
+	twoFactorEnrollUrl        = "/auth/2fa/enroll"
+	twoFactorConfirmUrl       = "/auth/2fa/confirm"
+	twoFactorDisableUrl       = "/auth/2fa/disable"
+	twoFactorRecoveryCodesUrl = "/auth/2fa/recovery-codes"
+	authLoginTwoFactorUrl     = "/auth/login/2fa"
+)
+
+// testTotpCode computes the code of an authenticator app, shifted by the given number of periods.
+func testTotpCode(t *testing.T, secret string, shift int64) string {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("Failed to decode TOTP secret, %s", err)
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(time.Now().Unix()/30+shift))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1_000_000)
+}
+
+func TestTwoFactor(t *testing.T) {
+	layers := preparationForTest(t)
+
+	if err := fixtures.NewFixtures(layers.db, layers.service).LoadPlayersFixture(); err != nil {
+		t.Errorf("Failed to load fixtures, %s", err)
+	}
+
+	accessToken, err := newAuthToken(layers, uuid.MustParse(fixtures.Player1Uuid))
+	if err != nil {
+		t.Fatalf("Failed to create auth token, %s", err)
+	}
+	send := func(method, url string, request interface{}, response interface{}) int {
+		var body []byte
+		if request != nil {
+			body, _ = json.Marshal(request)
+		}
+		resBody, resCode := sendRequestAndGetResponse(requestData{
+			router:      layers.router,
+			headers:     []*testRequestHeader{{key: authorizationToken, value: accessToken}},
+			requestBody: body,
+			method:      method,
+			url:         url,
+		})
+		if response != nil {
+			_ = json.Unmarshal(resBody, response)
+		}
+
+		return resCode
+	}
+	login := func() responses.AuthLoginChallengeResponse {
+		var response responses.AuthLoginChallengeResponse
+		send(http.MethodPost, authLoginUrl, requests.AuthLoginRequest{
+			Login:    fixtures.Player1Email,
+			Password: fixtures.Player1Password,
+		}, &response)
+
+		return response
+	}
+
+	var secret string
+	var recoveryCodes []string
+
+	t.Run("enroll and confirm", func(tt *testing.T) {
+		var resErr responseError
+		resCode := send(http.MethodPost, twoFactorConfirmUrl, requests.TwoFactorCodeRequest{Code: "123456"}, &resErr)
+		assert.Equal(tt, http.StatusBadRequest, resCode)
+		assert.Equal(tt, "two-factor authentication enrollment was not started", resErr.Message)
+
+		var enrollment responses.TwoFactorEnrollmentResponse
+		resCode = send(http.MethodPost, twoFactorEnrollUrl, nil, &enrollment)
+		if !assert.Equal(tt, http.StatusOK, resCode) {
+			return
+		}
+		secret = enrollment.Secret
+		otpauthUri, err := url.Parse(enrollment.OtpauthUri)
+		if assert.NoError(tt, err) {
+			assert.Equal(tt, "otpauth", otpauthUri.Scheme)
+			assert.Equal(tt, secret, otpauthUri.Query().Get("secret"))
+			assert.True(tt, strings.HasSuffix(otpauthUri.Path, fixtures.Player1Email))
+		}
+
+		assert.False(tt, login().TwoFactorRequired)
+
+		var codesResponse responses.TwoFactorRecoveryCodesResponse
+		resCode = send(http.MethodPost, twoFactorConfirmUrl, requests.TwoFactorCodeRequest{
+			Code: testTotpCode(tt, secret, 0),
+		}, &codesResponse)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Len(tt, codesResponse.RecoveryCodes, 10)
+		recoveryCodes = codesResponse.RecoveryCodes
+
+		var status responses.TwoFactorStatusResponse
+		send(http.MethodGet, twoFactorUrl, nil, &status)
+		assert.True(tt, status.Enabled)
+		assert.Equal(tt, int64(10), status.RecoveryCodesLeft)
+	})
+
+	t.Run("login with a second factor", func(tt *testing.T) {
+		challenge := login()
+		if !assert.True(tt, challenge.TwoFactorRequired) || !assert.NotEmpty(tt, challenge.ChallengeToken) {
+			return
+		}
+
+		// A challenge token is not an access token.
+		_, resCode := sendRequestAndGetResponse(requestData{
+			router:  layers.router,
+			headers: []*testRequestHeader{{key: authorizationToken, value: challenge.ChallengeToken}},
+			method:  http.MethodGet,
+			url:     authSessionsUrl,
+		})
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+
+		loginTwoFactor := func(code string) (responses.AuthLoginResponse, int) {
+			var response responses.AuthLoginResponse
+			resCode := send(http.MethodPost, authLoginTwoFactorUrl, requests.AuthLoginTwoFactorRequest{
+				ChallengeToken: challenge.ChallengeToken,
+				Code:           code,
+			}, &response)
+
+			return response, resCode
+		}
+
+		_, resCode = loginTwoFactor("000000")
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+
+		// The confirmation used the current period, the next one is still accepted for clock drift.
+		code := testTotpCode(tt, secret, 1)
+		tokens, resCode := loginTwoFactor(code)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.NotEmpty(tt, tokens.Token)
+
+		_, resCode = loginTwoFactor(code)
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+
+		tokens, resCode = loginTwoFactor(strings.ToUpper(recoveryCodes[0]))
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.NotEmpty(tt, tokens.Token)
+
+		_, resCode = loginTwoFactor(recoveryCodes[0])
+		assert.Equal(tt, http.StatusUnauthorized, resCode)
+	})
+
+	t.Run("regenerate recovery codes and disable", func(tt *testing.T) {
+		var codesResponse responses.TwoFactorRecoveryCodesResponse
+		resCode := send(http.MethodPost, twoFactorRecoveryCodesUrl, requests.TwoFactorCodeRequest{
+			Code: recoveryCodes[1],
+		}, &codesResponse)
+		assert.Equal(tt, http.StatusOK, resCode)
+		assert.Len(tt, codesResponse.RecoveryCodes, 10)
+
+		resCode = send(http.MethodPost, twoFactorDisableUrl, requests.TwoFactorCodeRequest{Code: recoveryCodes[2]}, nil)
+		assert.Equal(tt, http.StatusBadRequest, resCode)
+
+		resCode = send(http.MethodPost, twoFactorDisableUrl, requests.TwoFactorCodeRequest{
+			Code: codesResponse.RecoveryCodes[0],
+		}, nil)
+		assert.Equal(tt, http.StatusNoContent, resCode)
+		assert.False(tt, login().TwoFactorRequired)
+	})
+
+	if err := layers.bootstrap.TeardownTestDB(); err != nil {
+		t.Errorf("Failed to teardown test DB, %s", err)
+	}
+}
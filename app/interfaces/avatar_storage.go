@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"io"
+	"time"
+)
+
+type AvatarStorage interface {
+	Save(name string, data []byte) error
+	Open(name string) (io.ReadSeekCloser, time.Time, error)
+	Delete(name string) error
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+)
+
+type BotStrategy interface {
+	Name() dictionary.BotStrategy
+	Next(botId uuid.UUID, moves []entities.GameMove) dictionary.Throw
+}
@@ -0,0 +1,6 @@
+package interfaces
+
+// ProfanityFilter cleans up what the players write to each other.
+type ProfanityFilter interface {
+	Clean(text string) string
+}
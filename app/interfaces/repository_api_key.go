@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type RepositoryApiKey interface {
+	Create(bot *entities.Player, apiKey *entities.ApiKey) error
+	FindByHash(keyHash string) (*entities.ApiKey, error)
+	FindByOwner(ownerId uuid.UUID) ([]entities.ApiKey, error)
+	Revoke(ownerId, apiKeyId uuid.UUID) error
+	TouchLastUsed(apiKeyId uuid.UUID) error
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "knb/app/entities"
+
+type RepositoryAudit interface {
+	Create(entry *entities.AuditEntry) error
+	// Find returns the newest entries first.
+	Find(filter entities.AuditFilter) ([]entities.AuditEntry, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"time"
+)
+
+type RepositoryBan interface {
+	// Impose writes the ban and takes the player out of the games and the tournaments that have not started yet.
+	Impose(ban *entities.Ban, audit *entities.AuditEntry) error
+	FindActive(playerId uuid.UUID, now time.Time) (*entities.Ban, error)
+	// Revoke lifts the active bans of the player, false when there was none.
+	Revoke(playerId uuid.UUID, revokedBy *uuid.UUID, now time.Time, audit *entities.AuditEntry) (bool, error)
+}
@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"time"
+)
+
+type RepositoryChat interface {
+	Create(message *entities.ChatMessage) error
+	FindById(messageId uuid.UUID) (*entities.ChatMessage, error)
+	// FindPage returns the messages of the game, or of the lobby for no game, oldest first.
+	// Without a cursor it is the latest messages, otherwise the ones that came after the cursor.
+	FindPage(gameId *uuid.UUID, after *entities.ChatMessage, limit int) ([]entities.ChatMessage, error)
+	// FindByPlayer returns every message the player wrote, oldest first.
+	FindByPlayer(playerId uuid.UUID) ([]entities.ChatMessage, error)
+	// CreateReport is false when the player has already reported the message.
+	CreateReport(report *entities.ChatReport) (bool, error)
+	FindReport(reportId uuid.UUID) (*entities.ChatReport, error)
+	FindOpenReports(limit int) ([]entities.ChatReport, error)
+	// FindReportsByReporter returns every report the player made, oldest first.
+	FindReportsByReporter(reporterId uuid.UUID) ([]entities.ChatReport, error)
+	// Resolve closes all the open reports of the message, one decision settles them.
+	// It is false when another moderator has already resolved them.
+	Resolve(messageId, resolvedBy uuid.UUID, hide bool, now time.Time, audit *entities.AuditEntry) (bool, error)
+}
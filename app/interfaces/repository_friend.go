@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"time"
+)
+
+type RepositoryFriend interface {
+	// Find returns the friendship of the two players, whichever of them sent the request.
+	Find(playerId, otherId uuid.UUID) (*entities.Friendship, error)
+	FindByPlayer(playerId uuid.UUID, status dictionary.FriendshipStatus) ([]entities.Friendship, error)
+	Create(friendship *entities.Friendship) error
+	// Accept is false when there is no pending request from the requester.
+	Accept(requesterId, addresseeId uuid.UUID, now time.Time) (bool, error)
+	Delete(playerId, otherId uuid.UUID, status dictionary.FriendshipStatus) (bool, error)
+	// Block also ends the friendship of the two players and their pending requests.
+	Block(block *entities.Block) error
+	Unblock(playerId, blockedId uuid.UUID) (bool, error)
+	FindBlocks(playerId uuid.UUID) ([]entities.Block, error)
+	// IsBlocked tells whether any of the players has blocked the given one.
+	IsBlocked(playerIds []uuid.UUID, blockedId uuid.UUID) (bool, error)
+}
@@ -7,7 +7,32 @@ import (
 
 type GameRepository interface {
 	CreateGame(players ...uuid.UUID) (*entities.Game, error)
+	FlagBotOwned(game *entities.Game) error
 	FindById(gameId uuid.UUID) (*entities.Game, error)
+	FindResults(gameIds []uuid.UUID) ([]entities.GameResult, error)
 	AddPlayers(game *entities.Game, playerIds []uuid.UUID) error
+	FindGamePlayers(gameId uuid.UUID) ([]entities.GamePlayer, error)
+	SetPlayerReady(gameId, playerId uuid.UUID) error
 	StartGame(game *entities.Game) error
+	CreateMove(move *entities.GameMove) error
+	CompleteRound(
+		game *entities.Game,
+		results []entities.GameResult,
+		payouts map[uuid.UUID]uint,
+		finished bool,
+		audit *entities.AuditEntry,
+	) (bool, error)
+	Abandon(game *entities.Game, results []entities.GameResult, audit *entities.AuditEntry) error
+	FindByPlayer(playerId uuid.UUID, limit int) ([]entities.Game, error)
+	FindForExport(playerId uuid.UUID) ([]entities.Game, error)
+	FindFinishedByPlayer(playerId uuid.UUID) ([]entities.Game, error)
+	ReplacePrizes(gameId uuid.UUID, prizes []entities.GamePrize, audit *entities.AuditEntry) (bool, error)
+	Cancel(
+		game *entities.Game,
+		actorId *uuid.UUID,
+		reason string,
+		audit *entities.AuditEntry,
+	) ([]entities.PointsTransaction, bool, error)
+	RemovePlayerFromWaitingGames(playerId uuid.UUID) (int64, error)
+	RemovePlayerFromGamesWith(playerId, otherId uuid.UUID) (int64, error)
 }
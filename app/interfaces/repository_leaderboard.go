@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"time"
+)
+
+type RepositoryLeaderboard interface {
+	// Tally counts the games and the points of the player in [from, to), the points are the balance for all time.
+	Tally(playerId uuid.UUID, window dictionary.LeaderboardWindow, from, to time.Time) (*entities.LeaderboardEntry, error)
+	Save(entry *entities.LeaderboardEntry) error
+	Find(window dictionary.LeaderboardWindow, periodStart time.Time, playerId uuid.UUID) (*entities.LeaderboardEntry, error)
+	// FindTop returns the best entries of the board, those under minGames played are left out.
+	FindTop(
+		board dictionary.LeaderboardBoard,
+		window dictionary.LeaderboardWindow,
+		periodStart time.Time,
+		minGames uint,
+		limit int,
+	) ([]entities.LeaderboardEntry, error)
+	// CountAhead counts the entries with a better score than the given one.
+	CountAhead(board dictionary.LeaderboardBoard, entry *entities.LeaderboardEntry, minGames uint) (int64, error)
+	// FindUnranked returns the people with games or points who have no all-time entry yet.
+	FindUnranked() ([]uuid.UUID, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"knb/app/entities"
+	"time"
+)
+
+// RepositoryLoginThrottle keeps the failed login counters, in memory or shared between instances in Postgres.
+type RepositoryLoginThrottle interface {
+	Find(keys []string) ([]entities.LoginThrottle, error)
+	// Fail counts a failure, the counter starts over when the previous one is older than the window.
+	// The lockout returned for the new count is applied to the key.
+	Fail(key string, now time.Time, window time.Duration, lockout func(failures int) time.Duration) (*entities.LoginThrottle, error)
+	Reset(key string) error
+}
+
+type RepositoryLoginFailure interface {
+	Create(failure *entities.LoginFailure) error
+}
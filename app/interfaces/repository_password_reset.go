@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"knb/app/entities"
+)
+
+type RepositoryPasswordReset interface {
+	Create(resetToken *entities.PasswordResetToken) error
+	FindByHash(tokenHash string) (*entities.PasswordResetToken, error)
+	Reset(resetToken *entities.PasswordResetToken, passwordHash string) (bool, error)
+}
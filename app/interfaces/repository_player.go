@@ -2,11 +2,25 @@ package interfaces
 
 import (
 	"github.com/google/uuid"
+	"knb/app/dictionary"
 	"knb/app/entities"
+	"time"
 )
 
 type RepositoryPlayer interface {
-	Create(login, password string) (*entities.Player, error)
+	Create(login, password, displayName string) (*entities.Player, error)
+	CreateBot(strategy dictionary.BotStrategy) (*entities.Player, error)
 	FindById(id uuid.UUID) (*entities.Player, error)
-	FindByLoginAndPassword(login, password string) (*entities.Player, error)
+	FindByLogin(login string) (*entities.Player, error)
+	FindByIds(ids []uuid.UUID) ([]entities.Player, error)
+	UpdatePassword(playerId uuid.UUID, password string) error
+	UpdateProfile(player *entities.Player) error
+	Search(query string, limit, offset int) ([]entities.Player, error)
+	FindRoles(playerId uuid.UUID) (entities.Roles, error)
+	GrantRole(playerId uuid.UUID, role dictionary.Role, grantedBy *uuid.UUID, audit *entities.AuditEntry) (bool, error)
+	RevokeRole(playerId uuid.UUID, role dictionary.Role, audit *entities.AuditEntry) (bool, error)
+	MarkEmailVerified(playerId uuid.UUID, email string) error
+	ScheduleDeletion(playerId uuid.UUID, scheduledAt *time.Time) error
+	FindDueDeletions(now time.Time) ([]entities.Player, error)
+	Anonymise(player *entities.Player, now time.Time) (bool, error)
 }
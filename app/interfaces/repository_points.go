@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type RepositoryPoints interface {
+	// Adjust changes the points of the player together with the ledger entry,
+	// it returns false and changes nothing when the points would go below zero.
+	Adjust(transaction *entities.PointsTransaction, audit *entities.AuditEntry) (bool, error)
+	// FindByPlayer returns the newest entries first, all of them for a negative limit.
+	FindByPlayer(playerId uuid.UUID, limit int) ([]entities.PointsTransaction, error)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"time"
+)
+
+// RepositoryPresence keeps the presence of the players, in memory or shared between instances in Postgres.
+type RepositoryPresence interface {
+	// Touch records the activity unless it was already recorded after the given time.
+	Touch(playerId uuid.UUID, now, before time.Time) error
+	SetGame(playerId uuid.UUID, gameId uuid.UUID, now time.Time) error
+	// ClearGame forgets the game for the players who are still in it.
+	ClearGame(gameId uuid.UUID) error
+	// LeaveGame forgets the game of the player unless they have moved on to another one.
+	LeaveGame(playerId, gameId uuid.UUID) error
+	Find(playerIds []uuid.UUID) ([]entities.Presence, error)
+	// FindSince returns a page of the players seen after the given time, the most recent first.
+	// The stores that can leave out the bots and the anonymised players do so.
+	FindSince(since time.Time, limit, offset int) ([]entities.Presence, error)
+	Cleanup(before time.Time) error
+}
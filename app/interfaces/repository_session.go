@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"time"
+)
+
+type RepositorySession interface {
+	Create(session *entities.Session, refreshToken *entities.RefreshToken) error
+	FindById(sessionId uuid.UUID) (*entities.Session, error)
+	FindActiveByPlayer(playerId uuid.UUID, now time.Time) ([]entities.Session, error)
+	FindRefreshToken(tokenHash string) (*entities.RefreshToken, error)
+	Rotate(used, next *entities.RefreshToken) (bool, error)
+	TouchLastUsed(sessionId uuid.UUID, ip string, before time.Time) error
+	Revoke(sessionId uuid.UUID) error
+	RevokeOwned(playerId, sessionId uuid.UUID) error
+	RevokeAll(playerId uuid.UUID) error
+	RevokeAllExcept(playerId, sessionId uuid.UUID) error
+}
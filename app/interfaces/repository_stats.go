@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type RepositoryStats interface {
+	Find(playerId uuid.UUID) (*entities.PlayerStats, error)
+	FindOpponent(playerId, opponentId uuid.UUID) (*entities.PlayerOpponentStats, error)
+	// Apply locks the stats of the player, starting them when there are none, and saves what the change makes of them.
+	// The change gets the records against the opponents among the given ids that exist already.
+	Apply(
+		playerId uuid.UUID,
+		opponentIds []uuid.UUID,
+		change func(
+			stats *entities.PlayerStats,
+			opponents []entities.PlayerOpponentStats,
+		) (*entities.PlayerStats, []entities.PlayerOpponentStats),
+	) error
+	// Replace swaps all the stats of the player for the rebuilt ones, under the same lock as Apply.
+	Replace(stats *entities.PlayerStats, opponents []entities.PlayerOpponentStats) error
+	// FindUnbuilt returns the people with finished games who have no stats yet.
+	FindUnbuilt() ([]uuid.UUID, error)
+}
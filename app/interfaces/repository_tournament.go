@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"time"
+)
+
+type RepositoryTournament interface {
+	Create(tournament *entities.Tournament) error
+	FindById(tournamentId uuid.UUID) (*entities.Tournament, error)
+	AddPlayer(tournament *entities.Tournament, playerId uuid.UUID) error
+	RemovePlayerFromRegistrations(playerId uuid.UUID) (int64, error)
+	FindTournamentPlayers(tournamentId uuid.UUID) ([]entities.TournamentPlayer, error)
+	Start(tournament *entities.Tournament, seeds map[uuid.UUID]uint, matches []*entities.TournamentMatch) error
+	CreateMatches(matches []*entities.TournamentMatch) error
+	SaveMatch(match *entities.TournamentMatch) error
+	Finish(tournament *entities.Tournament, winnerId *uuid.UUID) error
+	FindMatchByGame(gameId uuid.UUID) (*entities.TournamentMatch, error)
+	FindOverdueMatches(now time.Time) ([]entities.TournamentMatch, error)
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type RepositoryTwoFactor interface {
+	Find(playerId uuid.UUID) (*entities.TwoFactor, error)
+	SavePending(twoFactor *entities.TwoFactor) error
+	Confirm(playerId uuid.UUID, step int64, recoveryCodes []*entities.RecoveryCode) (bool, error)
+	UseStep(playerId uuid.UUID, step int64) (bool, error)
+	UseRecoveryCode(playerId uuid.UUID, codeHash string) (bool, error)
+	ReplaceRecoveryCodes(playerId uuid.UUID, recoveryCodes []*entities.RecoveryCode) error
+	CountRecoveryCodes(playerId uuid.UUID) (int64, error)
+	Delete(playerId uuid.UUID) error
+}
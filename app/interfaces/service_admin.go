@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"time"
+)
+
+// ServiceAdmin is the toolbox of the support staff, every change it makes is written to the audit log.
+type ServiceAdmin interface {
+	SearchPlayers(query string, limit, offset int) ([]entities.Player, error)
+	PlayerGames(playerId uuid.UUID, limit int) ([]entities.Game, error)
+	PlayerPoints(playerId uuid.UUID, limit int) (*entities.PlayerPoints, error)
+	Ban(actorId, playerId uuid.UUID, reason string, duration time.Duration) (*entities.Ban, error)
+	Unban(actorId, playerId uuid.UUID, reason string) error
+	AdjustPoints(actorId, playerId uuid.UUID, amount int, reason string) (*entities.PointsTransaction, error)
+	CancelGame(actorId, gameId uuid.UUID, reason string) (*entities.Game, []entities.PointsTransaction, error)
+	FinishGame(actorId, gameId uuid.UUID, winnerId *uuid.UUID, reason string) (*entities.Game, error)
+	UpdateGamePrizes(actorId, gameId uuid.UUID, prizes []entities.GamePrize, reason string) (*entities.Game, error)
+	AuditLog(filter entities.AuditFilter) ([]entities.AuditEntry, error)
+}
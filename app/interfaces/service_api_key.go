@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+)
+
+type ServiceApiKey interface {
+	Create(ownerId uuid.UUID, name string, scopes []dictionary.ApiKeyScope) (string, *entities.ApiKey, error)
+	List(ownerId uuid.UUID) ([]entities.ApiKey, error)
+	Revoke(ownerId, apiKeyId uuid.UUID) error
+	Authenticate(key string) (*entities.ApiKey, error)
+}
@@ -6,6 +6,19 @@ import (
 )
 
 type ServiceAuth interface {
-	Registration(login, password string) (uuid.UUID, error)
+	Registration(login, password, displayName string) (uuid.UUID, error)
 	Login(login, password string) (*entities.Player, error)
+	VerifyEmail(verificationToken string) error
+	ResendEmailVerification(playerId uuid.UUID) error
+	ForgotPassword(login string)
+	CheckPasswordReset(resetToken string) error
+	ResetPassword(resetToken, password string) error
+	ChangePassword(playerId, sessionId uuid.UUID, currentPassword, password string) error
+	StartSession(playerId uuid.UUID, userAgent, ip string) (*entities.AuthTokens, error)
+	Refresh(refreshToken string) (*entities.AuthTokens, error)
+	CheckSession(playerId, sessionId uuid.UUID, ip string) error
+	Sessions(playerId uuid.UUID) ([]entities.Session, error)
+	RevokeSession(playerId, sessionId uuid.UUID) error
+	Logout(playerId, sessionId uuid.UUID) error
+	LogoutAll(playerId uuid.UUID) error
 }
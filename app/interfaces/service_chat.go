@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type ServiceChat interface {
+	Messages(playerId uuid.UUID, gameId *uuid.UUID, after *uuid.UUID, limit int) ([]entities.ChatMessage, error)
+	Send(playerId uuid.UUID, gameId *uuid.UUID, body string) (*entities.ChatMessage, error)
+	Report(playerId, messageId uuid.UUID, reason string) (*entities.ChatReport, error)
+	Reports(limit int) ([]entities.ChatReport, error)
+	Resolve(actorId, reportId uuid.UUID, hide bool, reason string) (*entities.ChatReport, error)
+}
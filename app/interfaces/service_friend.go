@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type ServiceFriend interface {
+	Friends(playerId uuid.UUID) ([]entities.Friend, error)
+	Requests(playerId uuid.UUID) ([]entities.Friendship, error)
+	SendRequest(playerId, otherId uuid.UUID) (*entities.Friendship, error)
+	AcceptRequest(playerId, requesterId uuid.UUID) (*entities.Friendship, error)
+	DeleteRequest(playerId, otherId uuid.UUID) error
+	Remove(playerId, friendId uuid.UUID) error
+	Blocks(playerId uuid.UUID) ([]entities.Block, error)
+	Block(playerId, blockedId uuid.UUID) error
+	Unblock(playerId, blockedId uuid.UUID) error
+}
@@ -2,12 +2,30 @@ package interfaces
 
 import (
 	"github.com/google/uuid"
+	"knb/app/dictionary"
 	"knb/app/entities"
 )
 
 type ServiceGame interface {
 	NewGameRequest(playerOwnerId uuid.UUID) (*entities.Game, error)
+	NewPracticeGame(playerId uuid.UUID, strategy dictionary.BotStrategy) (*entities.Game, error)
 	FindGame(gameId uuid.UUID) (*entities.Game, error)
 	JoinGame(playerId uuid.UUID, gameId uuid.UUID) (*entities.Game, error)
-	StartGame(gameId uuid.UUID) error
+	AddBots(playerId uuid.UUID, gameId uuid.UUID, count int, strategy dictionary.BotStrategy) (*entities.Game, error)
+	StartGame(playerId uuid.UUID, gameId uuid.UUID) error
+	Move(playerId uuid.UUID, gameId uuid.UUID, throw dictionary.Throw) (*entities.Game, error)
+	// ForceFinish, Cancel and UpdatePrizes write the audit entry, when given, in the same transaction as the change.
+	ForceFinish(gameId uuid.UUID, winnerId *uuid.UUID, audit *entities.AuditEntry) (*entities.Game, error)
+	Cancel(
+		gameId uuid.UUID,
+		actorId *uuid.UUID,
+		reason string,
+		audit *entities.AuditEntry,
+	) (*entities.Game, []entities.PointsTransaction, error)
+	UpdatePrizes(gameId uuid.UUID, prizes []entities.GamePrize, audit *entities.AuditEntry) (*entities.Game, error)
+	AddFinishedListener(listener GameFinishedListener)
+}
+
+type GameFinishedListener interface {
+	GameFinished(game *entities.Game)
 }
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+)
+
+type ServiceLeaderboard interface {
+	Board(
+		playerId uuid.UUID,
+		board dictionary.LeaderboardBoard,
+		window dictionary.LeaderboardWindow,
+		limit int,
+	) (*entities.Leaderboard, error)
+	GameFinished(game *entities.Game)
+	PointsChanged(playerId uuid.UUID)
+	Backfill() error
+}
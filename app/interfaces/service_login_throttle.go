@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"knb/app/entities"
+	"time"
+)
+
+type ServiceLoginThrottle interface {
+	Check(account, ip string) (time.Duration, error)
+	Failed(account string, failure *entities.LoginFailure) (time.Duration, error)
+	Succeeded(account string) error
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"io"
+	"knb/app/entities"
+	"time"
+)
+
+type ServicePlayer interface {
+	Profile(playerId uuid.UUID) (*entities.Player, error)
+	UpdateProfile(playerId uuid.UUID, update *entities.ProfileUpdate) (*entities.Player, error)
+	UploadAvatar(playerId uuid.UUID, data []byte) (*entities.Player, error)
+	Avatar(name string) (io.ReadSeekCloser, time.Time, error)
+	Export(playerId uuid.UUID) (*entities.PlayerExport, error)
+	ScheduleDeletion(playerId uuid.UUID, password string) (*entities.Player, error)
+	CancelDeletion(playerId uuid.UUID) (*entities.Player, error)
+	DeleteDue() error
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type ServicePresence interface {
+	Touch(playerId uuid.UUID)
+	EnterGame(playerId, gameId uuid.UUID)
+	GameFinished(game *entities.Game)
+	LeftGames(playerId uuid.UUID)
+	Find(playerIds []uuid.UUID) (map[uuid.UUID]entities.PlayerPresence, error)
+	// Visible is the presence of the player as the viewer sees it, empty when the player has blocked them.
+	Visible(viewerId, playerId uuid.UUID) (entities.PlayerPresence, error)
+	Online(limit int) ([]entities.OnlinePlayer, error)
+	Cleanup() error
+}
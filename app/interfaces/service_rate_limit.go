@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"knb/app/dictionary"
+	"time"
+)
+
+type ServiceRateLimit interface {
+	Allow(kind dictionary.RateLimit, identity string) (bool, time.Duration)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+)
+
+type ServiceRole interface {
+	Roles(playerId uuid.UUID) (entities.Roles, error)
+	Grant(playerId uuid.UUID, role dictionary.Role, grantedBy *uuid.UUID) (entities.Roles, error)
+	Revoke(playerId uuid.UUID, role dictionary.Role, revokedBy *uuid.UUID) (entities.Roles, error)
+}
@@ -1,9 +1,32 @@
 package interfaces
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"time"
+)
 
 type ServiceSecurity interface {
-	GeneratePasswordHash(password string) string
-	GenerateAuthToken(playerId uuid.UUID) (string, error)
-	ParseAuthToken(accessToken string) (string, error)
+	GeneratePasswordHash(password string) (string, error)
+	VerifyPassword(password, hash string) (valid, needsRehash bool)
+	GenerateAuthToken(playerId, sessionId uuid.UUID, roles entities.Roles) (string, time.Time, error)
+	ParseAuthToken(accessToken string) (playerId, sessionId uuid.UUID, roles entities.Roles, err error)
+	PublicTokenKeys() []entities.TokenPublicKey
+	GenerateEmailVerificationToken(playerId uuid.UUID, email string) (string, error)
+	ParseEmailVerificationToken(verificationToken string) (playerId uuid.UUID, email string, err error)
+	GenerateLoginChallengeToken(playerId uuid.UUID) (string, time.Time, error)
+	ParseLoginChallengeToken(challengeToken string) (playerId uuid.UUID, err error)
+	GenerateTotpSecret() (string, error)
+	TotpURI(account, secret string) string
+	VerifyTotp(secret, code string, now time.Time) (step int64, valid bool)
+	GenerateRecoveryCode() (code, hash string, err error)
+	HashRecoveryCode(code string) string
+	GenerateApiKey() (key, prefix, hash string, err error)
+	IsApiKey(token string) bool
+	HashApiKey(key string) string
+	GenerateRefreshToken() (token, hash string, err error)
+	HashRefreshToken(token string) string
+	GeneratePasswordResetToken() (token, hash string, err error)
+	HashPasswordResetToken(token string) string
+	GenerateCsrfToken() (string, error)
 }
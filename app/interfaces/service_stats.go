@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type ServiceStats interface {
+	Find(playerId uuid.UUID) (*entities.PlayerStats, error)
+	HeadToHead(playerId, opponentId uuid.UUID) (*entities.PlayerOpponentStats, error)
+	GameFinished(game *entities.Game)
+	Backfill() error
+}
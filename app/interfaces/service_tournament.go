@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+)
+
+type ServiceTournament interface {
+	Create(
+		ownerId uuid.UUID,
+		name string,
+		tournamentType dictionary.TournamentType,
+		seeding dictionary.TournamentSeeding,
+		noShowMinutes uint,
+		rounds uint,
+	) (*entities.Tournament, error)
+	Find(tournamentId uuid.UUID) (*entities.Tournament, error)
+	Standings(tournamentId uuid.UUID) ([]entities.TournamentStanding, error)
+	Join(playerId, tournamentId uuid.UUID) (*entities.Tournament, error)
+	Start(playerId, tournamentId uuid.UUID) (*entities.Tournament, error)
+	CheckNoShows() error
+	GameFinishedListener
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+)
+
+type ServiceTwoFactor interface {
+	Status(playerId uuid.UUID) (enabled bool, recoveryCodesLeft int64, err error)
+	Enroll(playerId uuid.UUID) (*entities.TwoFactorEnrollment, error)
+	Confirm(playerId uuid.UUID, code string) ([]string, error)
+	Disable(playerId uuid.UUID, code string) error
+	RegenerateRecoveryCodes(playerId uuid.UUID, code string) ([]string, error)
+	Challenge(playerId uuid.UUID) (*entities.LoginChallenge, error)
+	CompleteChallenge(challengeToken, code string) (uuid.UUID, error)
+}
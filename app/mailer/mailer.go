@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"fmt"
+	"knb/app/config"
+	"knb/app/interfaces"
+	"mime"
+	"strings"
+	"time"
+)
+
+func NewMailer(mailConfig config.MailConfig) interfaces.Mailer {
+	if mailConfig.Driver == config.MailDriverSmtp {
+		return newSmtpMailer(mailConfig)
+	}
+
+	return newOutboxMailer(mailConfig.From, mailConfig.OutboxDir)
+}
+
+// buildMessage renders a plain text RFC 5322 message.
+func buildMessage(from, to, subject, body string) []byte {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("From: %s\r\n", from))
+	message.WriteString(fmt.Sprintf("To: %s\r\n", to))
+	message.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject)))
+	message.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return []byte(message.String())
+}
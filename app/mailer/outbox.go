@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// outboxMailer keeps the messages locally for development and tests: as .eml files or in the log.
+type outboxMailer struct {
+	from string
+	dir  string
+	mu   sync.Mutex
+}
+
+func newOutboxMailer(from, dir string) *outboxMailer {
+	return &outboxMailer{from: from, dir: dir}
+}
+
+func (m *outboxMailer) Send(to, subject, body string) error {
+	message := buildMessage(m.from, to, subject, body)
+	if m.dir == "" {
+		log.Printf("Outbox mail to %s:\n%s\n", to, message)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), sanitizeFileName(to))
+
+	return os.WriteFile(filepath.Join(m.dir, name), message, 0o644)
+}
+
+func sanitizeFileName(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '@':
+			return r
+		}
+		return '_'
+	}, value)
+}
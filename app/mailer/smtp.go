@@ -0,0 +1,30 @@
+package mailer
+
+import (
+	"knb/app/config"
+	"net"
+	"net/smtp"
+)
+
+type smtpMailer struct {
+	address string
+	from    string
+	auth    smtp.Auth
+}
+
+func newSmtpMailer(mailConfig config.MailConfig) *smtpMailer {
+	var auth smtp.Auth
+	if mailConfig.SmtpUser != "" {
+		auth = smtp.PlainAuth("", mailConfig.SmtpUser, mailConfig.SmtpPassword, mailConfig.SmtpHost)
+	}
+
+	return &smtpMailer{
+		address: net.JoinHostPort(mailConfig.SmtpHost, mailConfig.SmtpPort),
+		from:    mailConfig.From,
+		auth:    auth,
+	}
+}
+
+func (m *smtpMailer) Send(to, subject, body string) error {
+	return smtp.SendMail(m.address, m.auth, m.from, []string{to}, buildMessage(m.from, to, subject, body))
+}
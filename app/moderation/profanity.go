@@ -0,0 +1,49 @@
+package moderation
+
+import (
+	"knb/app/interfaces"
+	"strings"
+	"unicode"
+)
+
+// wordListFilter masks the listed words wherever they stand as whole words, regardless of the case.
+type wordListFilter struct {
+	words map[string]struct{}
+}
+
+func NewProfanityFilter(words []string) interfaces.ProfanityFilter {
+	filter := &wordListFilter{words: make(map[string]struct{}, len(words))}
+	for _, word := range words {
+		filter.words[strings.ToLower(word)] = struct{}{}
+	}
+
+	return filter
+}
+
+func (w *wordListFilter) Clean(text string) string {
+	if len(w.words) == 0 {
+		return text
+	}
+
+	var cleaned strings.Builder
+	word := make([]rune, 0)
+	flush := func() {
+		if _, ok := w.words[strings.ToLower(string(word))]; ok {
+			cleaned.WriteString(strings.Repeat("*", len(word)))
+		} else {
+			cleaned.WriteString(string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		cleaned.WriteRune(r)
+	}
+	flush()
+
+	return cleaned.String()
+}
@@ -0,0 +1,53 @@
+package moderation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type cleanTestCase struct {
+	words    []string
+	text     string
+	expected string
+	name     string
+}
+
+func TestClean(t *testing.T) {
+	testCases := []cleanTestCase{
+		{
+			words:    []string{"darn"},
+			text:     "darn it, DARN!",
+			expected: "**** it, ****!",
+			name:     "whole words in any case",
+		},
+		{
+			words:    []string{"darn"},
+			text:     "darning the darned socks",
+			expected: "darning the darned socks",
+			name:     "parts of words are kept",
+		},
+		{
+			words:    []string{"Ёлки"},
+			text:     "ёлки-палки",
+			expected: "****-палки",
+			name:     "the mask has a star per letter",
+		},
+		{
+			words:    []string{"darn"},
+			text:     "darn2 darn_",
+			expected: "darn2 ****_",
+			name:     "digits belong to the word",
+		},
+		{
+			text:     "darn it",
+			expected: "darn it",
+			name:     "no words",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			assert.Equal(tt, tCase.expected, NewProfanityFilter(tCase.words).Clean(tCase.text))
+		})
+	}
+}
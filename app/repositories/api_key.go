@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func newApiKeyRepository(db *gorm.DB) *apiKeyRepository {
+	return &apiKeyRepository{db}
+}
+
+// Create stores the key along with the bot that plays with it, a key never comes without its bot.
+func (a *apiKeyRepository) Create(bot *entities.Player, apiKey *entities.ApiKey) error {
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(bot).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(apiKey).Error
+	})
+}
+
+func (a *apiKeyRepository) FindByHash(keyHash string) (*entities.ApiKey, error) {
+	var apiKeys []entities.ApiKey
+	if err := a.db.Limit(1).Find(&apiKeys, "key_hash = ?", keyHash).Error; err != nil {
+		return nil, err
+	}
+
+	if len(apiKeys) == 0 {
+		return nil, customErrors.NewNotFoundError("api key was not found")
+	}
+
+	return &apiKeys[0], nil
+}
+
+func (a *apiKeyRepository) FindByOwner(ownerId uuid.UUID) ([]entities.ApiKey, error) {
+	var apiKeys []entities.ApiKey
+	err := a.db.Order("created_at").Find(&apiKeys, "owner_id = ?", ownerId).Error
+
+	return apiKeys, err
+}
+
+func (a *apiKeyRepository) Revoke(ownerId, apiKeyId uuid.UUID) error {
+	result := a.db.
+		Model(&entities.ApiKey{}).
+		Where("id = ? AND owner_id = ? AND revoked_at IS NULL", apiKeyId, ownerId).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return customErrors.NewNotFoundError("api key was not found")
+	}
+
+	return nil
+}
+
+func (a *apiKeyRepository) TouchLastUsed(apiKeyId uuid.UUID) error {
+	return a.db.
+		Model(&entities.ApiKey{}).
+		Where("id = ?", apiKeyId).
+		Update("last_used_at", time.Now()).
+		Error
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"knb/app/entities"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func newAuditRepository(db *gorm.DB) *auditRepository {
+	return &auditRepository{db}
+}
+
+func (a *auditRepository) Create(entry *entities.AuditEntry) error {
+	return a.db.Create(entry).Error
+}
+
+// createAudit writes the entry of an admin action in the transaction of the action, nil when there is none.
+func createAudit(tx *gorm.DB, entry *entities.AuditEntry) error {
+	if entry == nil {
+		return nil
+	}
+
+	return tx.Create(entry).Error
+}
+
+func (a *auditRepository) Find(filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	query := a.db.Order("created_at DESC").Limit(filter.Limit)
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.Before != nil {
+		query = query.Where("created_at < ?", *filter.Before)
+	}
+
+	entries := make([]entities.AuditEntry, 0)
+	err := query.Find(&entries).Error
+
+	return entries, err
+}
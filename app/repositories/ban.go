@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+type banRepository struct {
+	db *gorm.DB
+}
+
+func newBanRepository(db *gorm.DB) *banRepository {
+	return &banRepository{db}
+}
+
+// Impose bans the player and takes them out of the games and the tournaments that have not started yet,
+// the audit entry is told how many they left.
+func (b *banRepository) Impose(ban *entities.Ban, audit *entities.AuditEntry) error {
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ban).Error; err != nil {
+			return err
+		}
+		leftGames, err := removePlayerFromWaitingGames(tx, ban.PlayerID)
+		if err != nil {
+			return err
+		}
+		leftTournaments, err := removePlayerFromRegistrations(tx, ban.PlayerID)
+		if err != nil {
+			return err
+		}
+		if audit == nil {
+			return nil
+		}
+		audit.AddDetails(map[string]interface{}{
+			"left_games":       leftGames,
+			"left_tournaments": leftTournaments,
+		})
+
+		return createAudit(tx, audit)
+	})
+}
+
+// FindActive returns the ban that lasts longest when several overlap.
+func (b *banRepository) FindActive(playerId uuid.UUID, now time.Time) (*entities.Ban, error) {
+	var bans []entities.Ban
+	if err := b.db.
+		Where("player_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", playerId, now).
+		Order("expires_at DESC NULLS FIRST").
+		Limit(1).
+		Find(&bans).
+		Error; err != nil {
+		return nil, err
+	}
+
+	if len(bans) == 0 {
+		return nil, customErrors.NewNotFoundError(fmt.Sprintf("player with id %s is not banned", playerId))
+	}
+
+	return &bans[0], nil
+}
+
+func (b *banRepository) Revoke(
+	playerId uuid.UUID,
+	revokedBy *uuid.UUID,
+	now time.Time,
+	audit *entities.AuditEntry,
+) (bool, error) {
+	revoked := false
+
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&entities.Ban{}).
+			Where("player_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", playerId, now).
+			Updates(map[string]interface{}{
+				"revoked_at": now,
+				"revoked_by": revokedBy,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		revoked = true
+
+		return createAudit(tx, audit)
+	})
+
+	return revoked, err
+}
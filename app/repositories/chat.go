@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"slices"
+	"time"
+)
+
+type chatRepository struct {
+	db *gorm.DB
+}
+
+func newChatRepository(db *gorm.DB) *chatRepository {
+	return &chatRepository{db}
+}
+
+func (c *chatRepository) Create(message *entities.ChatMessage) error {
+	return c.db.Omit(clause.Associations).Create(message).Error
+}
+
+func (c *chatRepository) FindById(messageId uuid.UUID) (*entities.ChatMessage, error) {
+	var messages []entities.ChatMessage
+	if err := c.db.Preload("Player").Limit(1).Find(&messages, "id = ?", messageId).Error; err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, customErrors.NewNotFoundError(fmt.Sprintf("chat message with id %s not found", messageId))
+	}
+
+	return &messages[0], nil
+}
+
+func (c *chatRepository) FindPage(
+	gameId *uuid.UUID,
+	after *entities.ChatMessage,
+	limit int,
+) ([]entities.ChatMessage, error) {
+	query := c.db.Preload("Player").Limit(limit)
+	if gameId == nil {
+		query = query.Where("game_id IS NULL")
+	} else {
+		query = query.Where("game_id = ?", *gameId)
+	}
+
+	messages := make([]entities.ChatMessage, 0)
+	if after != nil {
+		err := query.
+			Where("(created_at, id) > (?, ?)", after.CreatedAt, after.ID).
+			Order("created_at, id").
+			Find(&messages).
+			Error
+
+		return messages, err
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+
+	return messages, nil
+}
+
+func (c *chatRepository) CreateReport(report *entities.ChatReport) (bool, error) {
+	result := c.db.
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(report)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (c *chatRepository) FindReport(reportId uuid.UUID) (*entities.ChatReport, error) {
+	var reports []entities.ChatReport
+	if err := c.db.
+		Preload("Message.Player").
+		Limit(1).
+		Find(&reports, "id = ?", reportId).
+		Error; err != nil {
+		return nil, err
+	}
+
+	if len(reports) == 0 {
+		return nil, customErrors.NewNotFoundError(fmt.Sprintf("chat report with id %s not found", reportId))
+	}
+
+	return &reports[0], nil
+}
+
+func (c *chatRepository) FindByPlayer(playerId uuid.UUID) ([]entities.ChatMessage, error) {
+	messages := make([]entities.ChatMessage, 0)
+	err := c.db.
+		Preload("Player").
+		Where("player_id = ?", playerId).
+		Order("created_at, id").
+		Find(&messages).
+		Error
+
+	return messages, err
+}
+
+func (c *chatRepository) FindReportsByReporter(reporterId uuid.UUID) ([]entities.ChatReport, error) {
+	reports := make([]entities.ChatReport, 0)
+	err := c.db.
+		Preload("Message.Player").
+		Where("reporter_id = ?", reporterId).
+		Order("created_at, id").
+		Find(&reports).
+		Error
+
+	return reports, err
+}
+
+func (c *chatRepository) FindOpenReports(limit int) ([]entities.ChatReport, error) {
+	reports := make([]entities.ChatReport, 0)
+	err := c.db.
+		Preload("Message.Player").
+		Where("resolved_at IS NULL").
+		Order("created_at").
+		Limit(limit).
+		Find(&reports).
+		Error
+
+	return reports, err
+}
+
+// Resolve closes the open reports of the message, hides it when asked and writes the audit entry told how many
+// reports were closed, all in one transaction. It is false when the reports have already been resolved.
+func (c *chatRepository) Resolve(
+	messageId, resolvedBy uuid.UUID,
+	hide bool,
+	now time.Time,
+	audit *entities.AuditEntry,
+) (bool, error) {
+	resolved := false
+
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&entities.ChatReport{}).
+			Where("message_id = ? AND resolved_at IS NULL", messageId).
+			Updates(map[string]interface{}{
+				"resolved_at": now,
+				"resolved_by": resolvedBy,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		resolved = true
+
+		if hide {
+			if err := tx.
+				Model(&entities.ChatMessage{}).
+				Where("id = ? AND hidden_at IS NULL", messageId).
+				Updates(map[string]interface{}{
+					"hidden_at": now,
+					"hidden_by": resolvedBy,
+				}).
+				Error; err != nil {
+				return err
+			}
+		}
+		if audit == nil {
+			return nil
+		}
+		audit.AddDetails(map[string]interface{}{"reports": result.RowsAffected})
+
+		return createAudit(tx, audit)
+	})
+
+	return resolved, err
+}
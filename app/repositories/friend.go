@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+const friendshipPairCondition = "((requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?))"
+
+type friendRepository struct {
+	db *gorm.DB
+}
+
+func newFriendRepository(db *gorm.DB) *friendRepository {
+	return &friendRepository{db}
+}
+
+func (f *friendRepository) Find(playerId, otherId uuid.UUID) (*entities.Friendship, error) {
+	var friendships []entities.Friendship
+	if err := f.db.
+		Where(friendshipPairCondition, playerId, otherId, otherId, playerId).
+		Limit(1).
+		Find(&friendships).
+		Error; err != nil {
+		return nil, err
+	}
+
+	if len(friendships) == 0 {
+		return nil, customErrors.NewNotFoundError(fmt.Sprintf("player with id %s is not your friend", otherId))
+	}
+
+	return &friendships[0], nil
+}
+
+func (f *friendRepository) FindByPlayer(
+	playerId uuid.UUID,
+	status dictionary.FriendshipStatus,
+) ([]entities.Friendship, error) {
+	friendships := make([]entities.Friendship, 0)
+	err := f.db.
+		Preload("Requester").
+		Preload("Addressee").
+		Where("(requester_id = ? OR addressee_id = ?) AND status = ?", playerId, playerId, status).
+		Order("created_at DESC").
+		Find(&friendships).
+		Error
+
+	return friendships, err
+}
+
+func (f *friendRepository) Create(friendship *entities.Friendship) error {
+	return f.db.Omit(clause.Associations).Create(friendship).Error
+}
+
+func (f *friendRepository) Accept(requesterId, addresseeId uuid.UUID, now time.Time) (bool, error) {
+	result := f.db.
+		Model(&entities.Friendship{}).
+		Where(
+			"requester_id = ? AND addressee_id = ? AND status = ?",
+			requesterId,
+			addresseeId,
+			dictionary.FriendshipStatusPending,
+		).
+		Updates(map[string]interface{}{
+			"status":      dictionary.FriendshipStatusAccepted,
+			"accepted_at": now,
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (f *friendRepository) Delete(playerId, otherId uuid.UUID, status dictionary.FriendshipStatus) (bool, error) {
+	result := f.db.
+		Where(friendshipPairCondition+" AND status = ?", playerId, otherId, otherId, playerId, status).
+		Delete(&entities.Friendship{})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (f *friendRepository) Block(block *entities.Block) error {
+	return f.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where(friendshipPairCondition, block.PlayerID, block.BlockedID, block.BlockedID, block.PlayerID).
+			Delete(&entities.Friendship{}).
+			Error; err != nil {
+			return err
+		}
+
+		return tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(block).Error
+	})
+}
+
+func (f *friendRepository) Unblock(playerId, blockedId uuid.UUID) (bool, error) {
+	result := f.db.
+		Where("player_id = ? AND blocked_id = ?", playerId, blockedId).
+		Delete(&entities.Block{})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (f *friendRepository) FindBlocks(playerId uuid.UUID) ([]entities.Block, error) {
+	blocks := make([]entities.Block, 0)
+	err := f.db.
+		Preload("Blocked").
+		Where("player_id = ?", playerId).
+		Order("created_at DESC").
+		Find(&blocks).
+		Error
+
+	return blocks, err
+}
+
+func (f *friendRepository) IsBlocked(playerIds []uuid.UUID, blockedId uuid.UUID) (bool, error) {
+	if len(playerIds) == 0 {
+		return false, nil
+	}
+
+	var count int64
+	err := f.db.
+		Model(&entities.Block{}).
+		Where("player_id IN ? AND blocked_id = ?", playerIds, blockedId).
+		Count(&count).
+		Error
+
+	return count > 0, err
+}
@@ -3,7 +3,10 @@ package repositories
 import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"knb/app/dictionary"
 	"knb/app/entities"
+	"time"
 )
 
 type gameRepository struct {
@@ -33,17 +36,44 @@ func (g *gameRepository) CreateGame(players ...uuid.UUID) (*entities.Game, error
 	return game, nil
 }
 
+func (g *gameRepository) FlagBotOwned(game *entities.Game) error {
+	game.BotOwned = true
+
+	return g.db.Model(&entities.Game{}).Where("id = ?", game.ID).Update("bot_owned", true).Error
+}
+
 func (g *gameRepository) FindById(gameId uuid.UUID) (*entities.Game, error) {
 	var game *entities.Game
 
 	err := g.db.
 		Preload("Players").
+		Preload("Prizes").
+		Preload("Result").
+		Preload("Moves", func(db *gorm.DB) *gorm.DB {
+			return db.Order("round, created_at")
+		}).
 		First(&game, "id = ?", gameId).
 		Error
 
 	return game, err
 }
 
+// FindResults leaves out the games an admin cancelled, their results no longer count.
+func (g *gameRepository) FindResults(gameIds []uuid.UUID) ([]entities.GameResult, error) {
+	var results []entities.GameResult
+	if len(gameIds) == 0 {
+		return results, nil
+	}
+
+	err := g.db.
+		Joins("JOIN games ON games.id = game_results.game_id").
+		Where("game_results.game_id IN ? AND games.cancelled_at IS NULL", gameIds).
+		Find(&results).
+		Error
+
+	return results, err
+}
+
 func (g *gameRepository) AddPlayers(game *entities.Game, playerIds []uuid.UUID) error {
 	players := make([]entities.Player, 0, len(playerIds))
 	for _, playerId := range playerIds {
@@ -53,7 +83,379 @@ func (g *gameRepository) AddPlayers(game *entities.Game, playerIds []uuid.UUID)
 	return g.db.Model(&game).Association("Players").Append(&players)
 }
 
+func (g *gameRepository) FindGamePlayers(gameId uuid.UUID) ([]entities.GamePlayer, error) {
+	var gamePlayers []entities.GamePlayer
+	err := g.db.Find(&gamePlayers, "game_id = ?", gameId).Error
+
+	return gamePlayers, err
+}
+
+func (g *gameRepository) SetPlayerReady(gameId, playerId uuid.UUID) error {
+	return g.db.
+		Model(&entities.GamePlayer{}).
+		Where("game_id = ? AND player_id = ?", gameId, playerId).
+		Update("ready", true).
+		Error
+}
+
 func (g *gameRepository) StartGame(game *entities.Game) error {
-	//TODO implement me
-	panic("implement me")
+	game.Status = dictionary.GameStatusStarted
+	game.StartedAt = time.Now()
+	game.Round = 1
+
+	return g.db.
+		Model(&entities.Game{}).
+		Where("id = ?", game.ID).
+		Updates(map[string]interface{}{
+			"status":     game.Status,
+			"started_at": game.StartedAt,
+			"round":      game.Round,
+		}).
+		Error
+}
+
+func (g *gameRepository) CreateMove(move *entities.GameMove) error {
+	return g.db.Create(move).Error
+}
+
+func (g *gameRepository) CompleteRound(
+	game *entities.Game,
+	results []entities.GameResult,
+	payouts map[uuid.UUID]uint,
+	finished bool,
+	audit *entities.AuditEntry,
+) (bool, error) {
+	completed := false
+
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"round": game.Round + 1}
+		if finished {
+			updates["status"] = dictionary.GameStatusFinished
+			updates["finished_at"] = time.Now()
+		}
+
+		result := tx.
+			Model(&entities.Game{}).
+			Where("id = ? AND round = ? AND status = ?", game.ID, game.Round, dictionary.GameStatusStarted).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if len(results) > 0 {
+			if err := tx.Create(&results).Error; err != nil {
+				return err
+			}
+		}
+
+		for playerId, prize := range payouts {
+			if err := tx.
+				Model(&entities.Player{}).
+				Where("id = ?", playerId).
+				Update("points", gorm.Expr("points + ?", prize)).
+				Error; err != nil {
+				return err
+			}
+			if err := tx.Create(entities.NewPointsTransaction(
+				playerId, int(prize), dictionary.PointsKindPrize, &game.ID, nil, "",
+			)).Error; err != nil {
+				return err
+			}
+		}
+
+		completed = true
+
+		return createAudit(tx, audit)
+	})
+
+	return completed, err
+}
+
+func (g *gameRepository) Abandon(
+	game *entities.Game,
+	results []entities.GameResult,
+	audit *entities.AuditEntry,
+) error {
+	return g.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&entities.Game{}).
+			Where("id = ? AND status <> ?", game.ID, dictionary.GameStatusFinished).
+			Updates(map[string]interface{}{
+				"status":      dictionary.GameStatusFinished,
+				"finished_at": time.Now(),
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if len(results) > 0 {
+			if err := tx.Create(&results).Error; err != nil {
+				return err
+			}
+		}
+
+		return createAudit(tx, audit)
+	})
+}
+
+func (g *gameRepository) FindByPlayer(playerId uuid.UUID, limit int) ([]entities.Game, error) {
+	games := make([]entities.Game, 0)
+	err := g.db.
+		Preload("Players").
+		Preload("Prizes").
+		Preload("Result").
+		Where("id IN (?)", g.db.Model(&entities.GamePlayer{}).Select("game_id").Where("player_id = ?", playerId)).
+		Order("started_at DESC, id").
+		Limit(limit).
+		Find(&games).
+		Error
+
+	return games, err
+}
+
+// FindForExport returns every game of the player with only their own moves.
+func (g *gameRepository) FindForExport(playerId uuid.UUID) ([]entities.Game, error) {
+	games := make([]entities.Game, 0)
+	err := g.db.
+		Preload("Players").
+		Preload("Prizes").
+		Preload("Result").
+		Preload("Moves", func(db *gorm.DB) *gorm.DB {
+			return db.Where("player_id = ?", playerId).Order("round, created_at")
+		}).
+		Where("id IN (?)", g.db.Model(&entities.GamePlayer{}).Select("game_id").Where("player_id = ?", playerId)).
+		Order("started_at, id").
+		Find(&games).
+		Error
+
+	return games, err
+}
+
+// FindFinishedByPlayer returns the games of the player that count, in the order they ended, with only their own moves.
+func (g *gameRepository) FindFinishedByPlayer(playerId uuid.UUID) ([]entities.Game, error) {
+	games := make([]entities.Game, 0)
+	err := g.db.
+		Preload("Players").
+		Preload("Prizes").
+		Preload("Result").
+		Preload("Moves", func(db *gorm.DB) *gorm.DB {
+			return db.Where("player_id = ?", playerId)
+		}).
+		Where("id IN (?)", g.db.Model(&entities.GamePlayer{}).Select("game_id").Where("player_id = ?", playerId)).
+		Where("status = ? AND cancelled_at IS NULL", dictionary.GameStatusFinished).
+		Order("finished_at, id").
+		Find(&games).
+		Error
+
+	return games, err
+}
+
+// ReplacePrizes swaps the prize table of a game that has not started yet, false when it already has.
+func (g *gameRepository) ReplacePrizes(
+	gameId uuid.UUID,
+	prizes []entities.GamePrize,
+	audit *entities.AuditEntry,
+) (bool, error) {
+	replaced := false
+
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		var games []entities.Game
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status IN ?", gameId, []dictionary.GameStatus{
+				dictionary.GameStatusPlanned,
+				dictionary.GameStatusWaiting,
+			}).
+			Limit(1).
+			Find(&games).
+			Error; err != nil || len(games) == 0 {
+			return err
+		}
+
+		if err := tx.Where("game_id = ?", gameId).Delete(&entities.GamePrize{}).Error; err != nil {
+			return err
+		}
+		if len(prizes) > 0 {
+			if err := tx.Create(&prizes).Error; err != nil {
+				return err
+			}
+		}
+
+		replaced = true
+
+		return createAudit(tx, audit)
+	})
+
+	return replaced, err
+}
+
+// Cancel finishes the game as void and takes back the prizes it paid, a balance never goes below zero.
+// It returns the refunds, false when the game was already cancelled. The audit entry is given the refunds.
+func (g *gameRepository) Cancel(
+	game *entities.Game,
+	actorId *uuid.UUID,
+	reason string,
+	audit *entities.AuditEntry,
+) ([]entities.PointsTransaction, bool, error) {
+	refunds := make([]entities.PointsTransaction, 0)
+	cancelled := false
+
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.
+			Model(&entities.Game{}).
+			Where("id = ? AND cancelled_at IS NULL", game.ID).
+			Updates(map[string]interface{}{
+				"status":        dictionary.GameStatusFinished,
+				"finished_at":   gorm.Expr("CASE WHEN status = ? THEN finished_at ELSE ? END", dictionary.GameStatusFinished, now),
+				"cancelled_at":  now,
+				"cancel_reason": reason,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		var paid []struct {
+			PlayerID uuid.UUID
+			Amount   int
+		}
+		if err := tx.
+			Model(&entities.PointsTransaction{}).
+			Select("player_id, SUM(amount) AS amount").
+			Where("game_id = ?", game.ID).
+			Group("player_id").
+			Having("SUM(amount) > 0").
+			Scan(&paid).
+			Error; err != nil {
+			return err
+		}
+
+		for _, payout := range paid {
+			var player entities.Player
+			if err := tx.
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "points").
+				First(&player, "id = ?", payout.PlayerID).
+				Error; err != nil {
+				return err
+			}
+			amount := min(payout.Amount, int(player.Points))
+			if amount == 0 {
+				continue
+			}
+
+			if err := tx.
+				Model(&entities.Player{}).
+				Where("id = ?", payout.PlayerID).
+				Update("points", gorm.Expr("points - ?", amount)).
+				Error; err != nil {
+				return err
+			}
+			refund := entities.NewPointsTransaction(
+				payout.PlayerID, -amount, dictionary.PointsKindRefund, &game.ID, actorId, reason,
+			)
+			if err := tx.Create(refund).Error; err != nil {
+				return err
+			}
+			refunds = append(refunds, *refund)
+		}
+
+		cancelled = true
+
+		if audit == nil {
+			return nil
+		}
+		refunded := make(map[string]int, len(refunds))
+		for _, refund := range refunds {
+			refunded[refund.PlayerID.String()] = refund.Amount
+		}
+		audit.AddDetails(map[string]interface{}{"refunds": refunded})
+
+		return createAudit(tx, audit)
+	})
+
+	return refunds, cancelled, err
+}
+
+// RemovePlayerFromWaitingGames takes the player and the bots of their api keys out of the games
+// that have not started yet, a game left without players is finished.
+func (g *gameRepository) RemovePlayerFromWaitingGames(playerId uuid.UUID) (int64, error) {
+	var removed int64
+
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = removePlayerFromWaitingGames(tx, playerId)
+
+		return err
+	})
+
+	return removed, err
+}
+
+// RemovePlayerFromGamesWith takes the player and the bots of their api keys out of the games
+// of the other player that have not started yet.
+func (g *gameRepository) RemovePlayerFromGamesWith(playerId, otherId uuid.UUID) (int64, error) {
+	var removed int64
+
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = removeFromWaitingGames(
+			tx,
+			tx.Model(&entities.Player{}).Select("id").Where("id = ? OR owner_id = ?", playerId, playerId),
+			tx.Model(&entities.GamePlayer{}).Select("game_id").Where("player_id = ?", otherId),
+		)
+
+		return err
+	})
+
+	return removed, err
+}
+
+// removePlayerFromWaitingGames runs in the transaction of the caller, the bots of the player's api keys
+// leave along with them since they can't play without their owner.
+func removePlayerFromWaitingGames(tx *gorm.DB, playerId uuid.UUID) (int64, error) {
+	return removeFromWaitingGames(
+		tx,
+		tx.Model(&entities.Player{}).Select("id").Where("id = ? OR owner_id = ?", playerId, playerId),
+		tx.Model(&entities.Game{}).Select("id"),
+	)
+}
+
+// removeFromWaitingGames takes the players out of the given games that have not started yet,
+// a game left without players is finished.
+func removeFromWaitingGames(tx *gorm.DB, playerIds, gameIds interface{}) (int64, error) {
+	var waitingIds []uuid.UUID
+	if err := tx.
+		Model(&entities.GamePlayer{}).
+		Where("player_id IN (?) AND game_id IN (?)", playerIds, tx.
+			Model(&entities.Game{}).
+			Select("id").
+			Where("id IN (?)", gameIds).
+			Where("status IN ?", []dictionary.GameStatus{
+				dictionary.GameStatusPlanned,
+				dictionary.GameStatusWaiting,
+			}),
+		).
+		Distinct().
+		Pluck("game_id", &waitingIds).
+		Error; err != nil || len(waitingIds) == 0 {
+		return 0, err
+	}
+
+	result := tx.Where("player_id IN (?) AND game_id IN ?", playerIds, waitingIds).Delete(&entities.GamePlayer{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, tx.
+		Model(&entities.Game{}).
+		Where("id IN ? AND id NOT IN (?)", waitingIds, tx.Model(&entities.GamePlayer{}).Select("game_id")).
+		Updates(map[string]interface{}{
+			"status":      dictionary.GameStatusFinished,
+			"finished_at": time.Now(),
+		}).
+		Error
 }
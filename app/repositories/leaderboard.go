@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+// leaderboardListed leaves out the entries a refresh emptied, after a cancelled game for instance.
+const leaderboardListed = "(games_played > 0 OR points <> 0)"
+
+type leaderboardRepository struct {
+	db *gorm.DB
+}
+
+func newLeaderboardRepository(db *gorm.DB) *leaderboardRepository {
+	return &leaderboardRepository{db}
+}
+
+func (l *leaderboardRepository) Tally(
+	playerId uuid.UUID,
+	window dictionary.LeaderboardWindow,
+	from, to time.Time,
+) (*entities.LeaderboardEntry, error) {
+	var games struct {
+		Played uint
+		Won    uint
+	}
+	if err := l.db.
+		Model(&entities.GameResult{}).
+		Select("COUNT(*) AS played, COUNT(*) FILTER (WHERE game_results.place = 1) AS won").
+		Joins("JOIN games ON games.id = game_results.game_id").
+		Where("game_results.player_id = ? AND games.status = ? AND games.cancelled_at IS NULL",
+			playerId, dictionary.GameStatusFinished).
+		Where("games.finished_at >= ? AND games.finished_at < ?", from, to).
+		Scan(&games).
+		Error; err != nil {
+		return nil, err
+	}
+
+	var points int
+	var err error
+	if window == dictionary.LeaderboardWindowAllTime {
+		err = l.db.Model(&entities.Player{}).Select("points").Where("id = ?", playerId).Scan(&points).Error
+	} else {
+		err = l.db.
+			Model(&entities.PointsTransaction{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("player_id = ? AND created_at >= ? AND created_at < ?", playerId, from, to).
+			Scan(&points).
+			Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewLeaderboardEntry(window, from, playerId, points, games.Played, games.Won), nil
+}
+
+func (l *leaderboardRepository) Save(entry *entities.LeaderboardEntry) error {
+	entry.UpdatedAt = time.Now()
+
+	return l.db.
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "time_window"}, {Name: "period_start"}, {Name: "player_id"}},
+			DoUpdates: clause.AssignmentColumns(
+				[]string{"points", "games_played", "games_won", "win_rate", "updated_at"},
+			),
+		}).
+		Create(entry).
+		Error
+}
+
+func (l *leaderboardRepository) Find(
+	window dictionary.LeaderboardWindow,
+	periodStart time.Time,
+	playerId uuid.UUID,
+) (*entities.LeaderboardEntry, error) {
+	var entries []entities.LeaderboardEntry
+	if err := l.db.
+		Preload("Player").
+		Limit(1).
+		Find(&entries, "time_window = ? AND period_start = ? AND player_id = ?", window, periodStart, playerId).
+		Error; err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, customErrors.NewNotFoundError(fmt.Sprintf("player with id %s is not on the leaderboard", playerId))
+	}
+
+	return &entries[0], nil
+}
+
+func (l *leaderboardRepository) FindTop(
+	board dictionary.LeaderboardBoard,
+	window dictionary.LeaderboardWindow,
+	periodStart time.Time,
+	minGames uint,
+	limit int,
+) ([]entities.LeaderboardEntry, error) {
+	entries := make([]entities.LeaderboardEntry, 0, limit)
+	err := l.db.
+		Preload("Player").
+		Where("time_window = ? AND period_start = ? AND games_played >= ?", window, periodStart, minGames).
+		Where(leaderboardListed).
+		Order(clause.OrderBy{Columns: []clause.OrderByColumn{
+			{Column: clause.Column{Name: leaderboardColumn(board)}, Desc: true},
+			{Column: clause.Column{Name: "player_id"}},
+		}}).
+		Limit(limit).
+		Find(&entries).
+		Error
+
+	return entries, err
+}
+
+func (l *leaderboardRepository) CountAhead(
+	board dictionary.LeaderboardBoard,
+	entry *entities.LeaderboardEntry,
+	minGames uint,
+) (int64, error) {
+	var score interface{}
+	switch board {
+	case dictionary.LeaderboardBoardWins:
+		score = entry.GamesWon
+	case dictionary.LeaderboardBoardWinRate:
+		score = entry.WinRate
+	default:
+		score = entry.Points
+	}
+
+	var count int64
+	err := l.db.
+		Model(&entities.LeaderboardEntry{}).
+		Where("time_window = ? AND period_start = ? AND games_played >= ?", entry.Window, entry.PeriodStart, minGames).
+		Where(leaderboardListed).
+		Where(clause.Gt{Column: clause.Column{Name: leaderboardColumn(board)}, Value: score}).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+func (l *leaderboardRepository) FindUnranked() ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	err := l.db.
+		Model(&entities.Player{}).
+		Where("NOT is_bot AND anonymised_at IS NULL").
+		Where("points > 0 OR id IN (?)", l.db.Model(&entities.GameResult{}).Select("player_id")).
+		Where("id NOT IN (?)", l.db.
+			Model(&entities.LeaderboardEntry{}).
+			Select("player_id").
+			Where("time_window = ?", dictionary.LeaderboardWindowAllTime),
+		).
+		Pluck("id", &ids).
+		Error
+
+	return ids, err
+}
+
+func leaderboardColumn(board dictionary.LeaderboardBoard) string {
+	switch board {
+	case dictionary.LeaderboardBoardWins:
+		return "games_won"
+	case dictionary.LeaderboardBoardWinRate:
+		return "win_rate"
+	default:
+		return "points"
+	}
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"knb/app/entities"
+)
+
+type loginFailureRepository struct {
+	db *gorm.DB
+}
+
+func newLoginFailureRepository(db *gorm.DB) *loginFailureRepository {
+	return &loginFailureRepository{db}
+}
+
+func (l *loginFailureRepository) Create(failure *entities.LoginFailure) error {
+	return l.db.Create(failure).Error
+}
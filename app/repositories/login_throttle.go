@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"knb/app/entities"
+	"time"
+)
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func newLoginThrottleRepository(db *gorm.DB) *loginThrottleRepository {
+	return &loginThrottleRepository{db}
+}
+
+func (l *loginThrottleRepository) Find(keys []string) ([]entities.LoginThrottle, error) {
+	var throttles []entities.LoginThrottle
+	err := l.db.Find(&throttles, "key IN ?", keys).Error
+
+	return throttles, err
+}
+
+// Fail increments the counter with an upsert, so the concurrent failures on several instances are all counted.
+func (l *loginThrottleRepository) Fail(
+	key string,
+	now time.Time,
+	window time.Duration,
+	lockout func(failures int) time.Duration,
+) (*entities.LoginThrottle, error) {
+	throttle := &entities.LoginThrottle{}
+	err := l.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`
+			INSERT INTO login_throttles (key, failures, last_failed_at)
+			VALUES (?, 1, ?)
+			ON CONFLICT (key) DO UPDATE SET
+				failures = CASE
+					WHEN login_throttles.last_failed_at < ? THEN 1
+					ELSE login_throttles.failures + 1
+				END,
+				last_failed_at = EXCLUDED.last_failed_at
+			RETURNING key, failures, last_failed_at, locked_until`,
+			key, now, now.Add(-window),
+		).Scan(throttle).Error; err != nil {
+			return err
+		}
+
+		duration := lockout(throttle.Failures)
+		if duration <= 0 {
+			return nil
+		}
+		lockedUntil := now.Add(duration)
+		throttle.LockedUntil = &lockedUntil
+
+		return tx.
+			Model(&entities.LoginThrottle{}).
+			Where("key = ?", key).
+			Update("locked_until", lockedUntil).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return throttle, nil
+}
+
+func (l *loginThrottleRepository) Reset(key string) error {
+	return l.db.Where("key = ?", key).Delete(&entities.LoginThrottle{}).Error
+}
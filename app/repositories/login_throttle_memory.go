@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"knb/app/entities"
+	"knb/app/interfaces"
+	"sync"
+	"time"
+)
+
+// memoryLoginThrottleRepository keeps the counters in the memory of a single instance.
+type memoryLoginThrottleRepository struct {
+	mu        sync.Mutex
+	throttles map[string]*entities.LoginThrottle
+	cleanedAt time.Time
+}
+
+func NewMemoryLoginThrottleRepository() interfaces.RepositoryLoginThrottle {
+	return &memoryLoginThrottleRepository{throttles: make(map[string]*entities.LoginThrottle)}
+}
+
+func (m *memoryLoginThrottleRepository) Find(keys []string) ([]entities.LoginThrottle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	throttles := make([]entities.LoginThrottle, 0, len(keys))
+	for _, key := range keys {
+		if throttle, ok := m.throttles[key]; ok {
+			throttles = append(throttles, *throttle)
+		}
+	}
+
+	return throttles, nil
+}
+
+func (m *memoryLoginThrottleRepository) Fail(
+	key string,
+	now time.Time,
+	window time.Duration,
+	lockout func(failures int) time.Duration,
+) (*entities.LoginThrottle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cleanup(now, window)
+
+	throttle, ok := m.throttles[key]
+	if !ok || throttle.LastFailedAt.Before(now.Add(-window)) {
+		throttle = &entities.LoginThrottle{Key: key}
+		m.throttles[key] = throttle
+	}
+	throttle.Failures++
+	throttle.LastFailedAt = now
+
+	if duration := lockout(throttle.Failures); duration > 0 {
+		lockedUntil := now.Add(duration)
+		throttle.LockedUntil = &lockedUntil
+	}
+
+	result := *throttle
+
+	return &result, nil
+}
+
+func (m *memoryLoginThrottleRepository) Reset(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.throttles, key)
+
+	return nil
+}
+
+// cleanup drops the counters that have expired, at most once per window.
+func (m *memoryLoginThrottleRepository) cleanup(now time.Time, window time.Duration) {
+	if now.Sub(m.cleanedAt) < window {
+		return
+	}
+	m.cleanedAt = now
+
+	for key, throttle := range m.throttles {
+		if throttle.LastFailedAt.Before(now.Add(-window)) && throttle.RetryAfter(now) == 0 {
+			delete(m.throttles, key)
+		}
+	}
+}
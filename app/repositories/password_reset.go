@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func newPasswordResetRepository(db *gorm.DB) *passwordResetRepository {
+	return &passwordResetRepository{db}
+}
+
+// Create stores the new token and invalidates the ones mailed to the player before.
+func (p *passwordResetRepository) Create(resetToken *entities.PasswordResetToken) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&entities.PasswordResetToken{}).
+			Where("player_id = ? AND used_at IS NULL", resetToken.PlayerID).
+			Update("used_at", time.Now()).
+			Error; err != nil {
+			return err
+		}
+
+		return tx.Create(resetToken).Error
+	})
+}
+
+func (p *passwordResetRepository) FindByHash(tokenHash string) (*entities.PasswordResetToken, error) {
+	var resetTokens []entities.PasswordResetToken
+	if err := p.db.Limit(1).Find(&resetTokens, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+
+	if len(resetTokens) == 0 {
+		return nil, customErrors.NewNotFoundError("password reset token was not found")
+	}
+
+	return &resetTokens[0], nil
+}
+
+// Reset uses up the token, sets the new password and revokes all the sessions of the player.
+// It returns false when the token has expired or has already been used by a concurrent request.
+func (p *passwordResetRepository) Reset(resetToken *entities.PasswordResetToken, passwordHash string) (bool, error) {
+	reset := false
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.
+			Model(&entities.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", resetToken.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.
+			Model(&entities.Player{}).
+			Where("id = ?", resetToken.PlayerID).
+			Update("password", passwordHash).
+			Error; err != nil {
+			return err
+		}
+		if err := tx.
+			Model(&entities.Session{}).
+			Where("player_id = ? AND revoked_at IS NULL", resetToken.PlayerID).
+			Update("revoked_at", now).
+			Error; err != nil {
+			return err
+		}
+
+		reset = true
+
+		return nil
+	})
+
+	return reset, err
+}
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"knb/app/dictionary"
 	"knb/app/entities"
 	customErrors "knb/app/errors"
 	"log"
+	"strings"
+	"time"
 )
 
 type playerRepository struct {
@@ -17,8 +21,8 @@ func newPlayerRepository(db *gorm.DB) *playerRepository {
 	return &playerRepository{db}
 }
 
-func (p *playerRepository) Create(login, password string) (*entities.Player, error) {
-	player := entities.NewPlayer(login, password, "")
+func (p *playerRepository) Create(login, password, displayName string) (*entities.Player, error) {
+	player := entities.NewPlayer(login, password, displayName)
 
 	if err := p.db.Create(player).Error; err != nil {
 		return nil, err
@@ -27,6 +31,16 @@ func (p *playerRepository) Create(login, password string) (*entities.Player, err
 	return player, nil
 }
 
+func (p *playerRepository) CreateBot(strategy dictionary.BotStrategy) (*entities.Player, error) {
+	bot := entities.NewBot(strategy)
+
+	if err := p.db.Create(bot).Error; err != nil {
+		return nil, err
+	}
+
+	return bot, nil
+}
+
 func (p *playerRepository) FindById(id uuid.UUID) (*entities.Player, error) {
 	var players []entities.Player
 	result := p.db.Limit(1).Find(&players, "id = ?", id.String())
@@ -41,13 +55,9 @@ func (p *playerRepository) FindById(id uuid.UUID) (*entities.Player, error) {
 	return &players[0], nil
 }
 
-func (p *playerRepository) FindByLoginAndPassword(login, password string) (*entities.Player, error) {
+func (p *playerRepository) FindByLogin(login string) (*entities.Player, error) {
 	var players []entities.Player
-	result := p.db.Limit(1).Find(
-		&players,
-		"email = ? AND password = ?",
-		login, password,
-	)
+	result := p.db.Limit(1).Find(&players, "email = ?", login)
 	if result.Error != nil {
 		log.Fatal(result.Error)
 	}
@@ -58,3 +68,215 @@ func (p *playerRepository) FindByLoginAndPassword(login, password string) (*enti
 
 	return &players[0], nil
 }
+
+func (p *playerRepository) UpdatePassword(playerId uuid.UUID, password string) error {
+	return p.db.Model(&entities.Player{}).
+		Where("id = ?", playerId).
+		Update("password", password).
+		Error
+}
+
+func (p *playerRepository) UpdateProfile(player *entities.Player) error {
+	return p.db.Model(player).
+		Select("display_name", "display_name_key", "avatar_url", "avatar_file", "locale").
+		Updates(player).
+		Error
+}
+
+func (p *playerRepository) FindByIds(ids []uuid.UUID) ([]entities.Player, error) {
+	players := make([]entities.Player, 0, len(ids))
+	if len(ids) == 0 {
+		return players, nil
+	}
+
+	err := p.db.Where("id IN ?", ids).Find(&players).Error
+
+	return players, err
+}
+
+// Search matches a part of the email or of the display name, regardless of the case; bots are left out.
+func (p *playerRepository) Search(query string, limit, offset int) ([]entities.Player, error) {
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+
+	players := make([]entities.Player, 0)
+	err := p.db.
+		Where("NOT is_bot AND (email ILIKE ? OR display_name ILIKE ?)", pattern, pattern).
+		Order("email").
+		Limit(limit).
+		Offset(offset).
+		Find(&players).
+		Error
+
+	return players, err
+}
+
+func (p *playerRepository) FindRoles(playerId uuid.UUID) (entities.Roles, error) {
+	roles := make(entities.Roles, 0)
+	err := p.db.
+		Model(&entities.PlayerRole{}).
+		Where("player_id = ?", playerId).
+		Order("role").
+		Pluck("role", &roles).
+		Error
+
+	return roles, err
+}
+
+// GrantRole does nothing when the player already has the role, the audit entry is written for a new one only.
+func (p *playerRepository) GrantRole(
+	playerId uuid.UUID,
+	role dictionary.Role,
+	grantedBy *uuid.UUID,
+	audit *entities.AuditEntry,
+) (bool, error) {
+	granted := false
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entities.PlayerRole{PlayerID: playerId, Role: role, GrantedBy: grantedBy})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		granted = true
+
+		return createAudit(tx, audit)
+	})
+
+	return granted, err
+}
+
+// RevokeRole ends the sessions of the player along with the role.
+func (p *playerRepository) RevokeRole(playerId uuid.UUID, role dictionary.Role, audit *entities.AuditEntry) (bool, error) {
+	revoked := false
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("player_id = ? AND role = ?", playerId, role).Delete(&entities.PlayerRole{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		revoked = true
+
+		if err := revokeSessions(tx, playerId); err != nil {
+			return err
+		}
+
+		return createAudit(tx, audit)
+	})
+
+	return revoked, err
+}
+
+// ScheduleDeletion sets when the account is deleted, nil takes the deletion back.
+func (p *playerRepository) ScheduleDeletion(playerId uuid.UUID, scheduledAt *time.Time) error {
+	return p.db.
+		Model(&entities.Player{}).
+		Where("id = ? AND anonymised_at IS NULL", playerId).
+		Update("deletion_scheduled_at", scheduledAt).
+		Error
+}
+
+func (p *playerRepository) FindDueDeletions(now time.Time) ([]entities.Player, error) {
+	players := make([]entities.Player, 0)
+	err := p.db.
+		Where("deletion_scheduled_at <= ? AND anonymised_at IS NULL", now).
+		Find(&players).
+		Error
+
+	return players, err
+}
+
+// Anonymise scrubs the player and deletes the credentials and the login history in one transaction,
+// the games, the results and the points ledger stay. It returns false when the deletion was taken back.
+func (p *playerRepository) Anonymise(player *entities.Player, now time.Time) (bool, error) {
+	anonymised := false
+	login := strings.ToLower(player.Email)
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		player.Anonymise(now)
+		result := tx.
+			Model(&entities.Player{}).
+			Where("id = ? AND anonymised_at IS NULL AND deletion_scheduled_at <= ?", player.ID, now).
+			Updates(map[string]interface{}{
+				"email":                 player.Email,
+				"password":              player.Password,
+				"display_name":          player.DisplayName,
+				"display_name_key":      player.DisplayNameKey,
+				"avatar_url":            player.AvatarURL,
+				"avatar_file":           player.AvatarFile,
+				"locale":                player.Locale,
+				"email_verified_at":     player.EmailVerifiedAt,
+				"deletion_scheduled_at": player.DeletionScheduledAt,
+				"anonymised_at":         player.AnonymisedAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		sessions := tx.Model(&entities.Session{}).Select("id").Where("player_id = ?", player.ID)
+		for _, deletion := range []struct {
+			model interface{}
+			query string
+			args  []interface{}
+		}{
+			{&entities.RefreshToken{}, "session_id IN (?)", []interface{}{sessions}},
+			{&entities.Session{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.PasswordResetToken{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.RecoveryCode{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.TwoFactor{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.PlayerRole{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.ApiKey{}, "owner_id = ?", []interface{}{player.ID}},
+			{&entities.Friendship{}, "requester_id = ? OR addressee_id = ?", []interface{}{player.ID, player.ID}},
+			{&entities.Block{}, "player_id = ? OR blocked_id = ?", []interface{}{player.ID, player.ID}},
+			{&entities.Presence{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.PlayerOpponentStats{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.PlayerThrowStats{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.PlayerRuleSetStats{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.PlayerStats{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.LeaderboardEntry{}, "player_id = ?", []interface{}{player.ID}},
+			{&entities.LoginFailure{}, "player_id = ? OR LOWER(login) = ?", []interface{}{player.ID, login}},
+			// The throttle keys are the login behind the prefix of their kind.
+			{&entities.LoginThrottle{}, "SUBSTRING(key FROM POSITION(':' IN key) + 1) = ?", []interface{}{login}},
+		} {
+			if err := tx.Where(deletion.query, deletion.args...).Delete(deletion.model).Error; err != nil {
+				return err
+			}
+		}
+		// The games and the tournaments that have not started yet go on without the player.
+		if _, err := removePlayerFromWaitingGames(tx, player.ID); err != nil {
+			return err
+		}
+		if _, err := removePlayerFromRegistrations(tx, player.ID); err != nil {
+			return err
+		}
+		// The messages stay in the chat history of the other players, without what was said.
+		if err := tx.
+			Model(&entities.ChatMessage{}).
+			Where("player_id = ?", player.ID).
+			Update("body", "").
+			Error; err != nil {
+			return err
+		}
+
+		anonymised = true
+
+		return nil
+	})
+
+	return anonymised, err
+}
+
+// MarkEmailVerified verifies the email only if the player still has the one the link was sent to.
+func (p *playerRepository) MarkEmailVerified(playerId uuid.UUID, email string) error {
+	result := p.db.Model(&entities.Player{}).
+		Where("id = ? AND email = ?", playerId, email).
+		Where("email_verified_at IS NULL").
+		Update("email_verified_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"knb/app/entities"
+)
+
+type pointsRepository struct {
+	db *gorm.DB
+}
+
+func newPointsRepository(db *gorm.DB) *pointsRepository {
+	return &pointsRepository{db}
+}
+
+func (p *pointsRepository) Adjust(transaction *entities.PointsTransaction, audit *entities.AuditEntry) (bool, error) {
+	adjusted := false
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&entities.Player{}).
+			Where("id = ? AND points + ? >= 0", transaction.PlayerID, transaction.Amount).
+			Update("points", gorm.Expr("points + ?", transaction.Amount))
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		adjusted = true
+
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+
+		return createAudit(tx, audit)
+	})
+
+	return adjusted, err
+}
+
+func (p *pointsRepository) FindByPlayer(playerId uuid.UUID, limit int) ([]entities.PointsTransaction, error) {
+	transactions := make([]entities.PointsTransaction, 0)
+	err := p.db.
+		Where("player_id = ?", playerId).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&transactions).
+		Error
+
+	return transactions, err
+}
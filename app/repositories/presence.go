@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"knb/app/entities"
+	"time"
+)
+
+type presenceRepository struct {
+	db *gorm.DB
+}
+
+func newPresenceRepository(db *gorm.DB) *presenceRepository {
+	return &presenceRepository{db}
+}
+
+func (p *presenceRepository) Touch(playerId uuid.UUID, now, before time.Time) error {
+	return p.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "player_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_seen_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "presences.last_seen_at < ?", Vars: []interface{}{before}},
+			}},
+		}).
+		Create(&entities.Presence{PlayerID: playerId, LastSeenAt: now}).
+		Error
+}
+
+func (p *presenceRepository) SetGame(playerId uuid.UUID, gameId uuid.UUID, now time.Time) error {
+	return p.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "player_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"game_id", "last_seen_at"}),
+		}).
+		Create(&entities.Presence{PlayerID: playerId, GameID: &gameId, LastSeenAt: now}).
+		Error
+}
+
+func (p *presenceRepository) ClearGame(gameId uuid.UUID) error {
+	return p.db.
+		Model(&entities.Presence{}).
+		Where("game_id = ?", gameId).
+		Update("game_id", nil).
+		Error
+}
+
+func (p *presenceRepository) LeaveGame(playerId, gameId uuid.UUID) error {
+	return p.db.
+		Model(&entities.Presence{}).
+		Where("player_id = ? AND game_id = ?", playerId, gameId).
+		Update("game_id", nil).
+		Error
+}
+
+func (p *presenceRepository) Find(playerIds []uuid.UUID) ([]entities.Presence, error) {
+	presences := make([]entities.Presence, 0, len(playerIds))
+	if len(playerIds) == 0 {
+		return presences, nil
+	}
+
+	err := p.db.Where("player_id IN ?", playerIds).Find(&presences).Error
+
+	return presences, err
+}
+
+func (p *presenceRepository) FindSince(since time.Time, limit, offset int) ([]entities.Presence, error) {
+	presences := make([]entities.Presence, 0, max(limit, 0))
+	err := p.db.
+		Joins("JOIN players ON players.id = presences.player_id").
+		Where("presences.last_seen_at > ?", since).
+		Where("NOT players.is_bot AND players.anonymised_at IS NULL").
+		Order("presences.last_seen_at DESC, presences.player_id").
+		Limit(limit).
+		Offset(offset).
+		Find(&presences).
+		Error
+
+	return presences, err
+}
+
+func (p *presenceRepository) Cleanup(before time.Time) error {
+	return p.db.Where("last_seen_at < ?", before).Delete(&entities.Presence{}).Error
+}
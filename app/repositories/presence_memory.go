@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"bytes"
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"knb/app/interfaces"
+	"slices"
+	"sync"
+	"time"
+)
+
+// memoryPresenceRepository keeps the presence in the memory of a single instance.
+type memoryPresenceRepository struct {
+	mu        sync.Mutex
+	presences map[uuid.UUID]*entities.Presence
+}
+
+func NewMemoryPresenceRepository() interfaces.RepositoryPresence {
+	return &memoryPresenceRepository{presences: make(map[uuid.UUID]*entities.Presence)}
+}
+
+func (m *memoryPresenceRepository) Touch(playerId uuid.UUID, now, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	presence, ok := m.presences[playerId]
+	if !ok {
+		m.presences[playerId] = &entities.Presence{PlayerID: playerId, LastSeenAt: now}
+		return nil
+	}
+	if presence.LastSeenAt.Before(before) {
+		presence.LastSeenAt = now
+	}
+
+	return nil
+}
+
+func (m *memoryPresenceRepository) SetGame(playerId uuid.UUID, gameId uuid.UUID, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.presences[playerId] = &entities.Presence{PlayerID: playerId, GameID: &gameId, LastSeenAt: now}
+
+	return nil
+}
+
+func (m *memoryPresenceRepository) ClearGame(gameId uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, presence := range m.presences {
+		if presence.GameID != nil && *presence.GameID == gameId {
+			presence.GameID = nil
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryPresenceRepository) LeaveGame(playerId, gameId uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if presence, ok := m.presences[playerId]; ok && presence.GameID != nil && *presence.GameID == gameId {
+		presence.GameID = nil
+	}
+
+	return nil
+}
+
+func (m *memoryPresenceRepository) Find(playerIds []uuid.UUID) ([]entities.Presence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	presences := make([]entities.Presence, 0, len(playerIds))
+	for _, playerId := range playerIds {
+		if presence, ok := m.presences[playerId]; ok {
+			presences = append(presences, *presence)
+		}
+	}
+
+	return presences, nil
+}
+
+func (m *memoryPresenceRepository) FindSince(since time.Time, limit, offset int) ([]entities.Presence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	presences := make([]entities.Presence, 0)
+	for _, presence := range m.presences {
+		if presence.LastSeenAt.After(since) {
+			presences = append(presences, *presence)
+		}
+	}
+	slices.SortFunc(presences, func(a, b entities.Presence) int {
+		if order := b.LastSeenAt.Compare(a.LastSeenAt); order != 0 {
+			return order
+		}
+
+		return bytes.Compare(a.PlayerID[:], b.PlayerID[:])
+	})
+	presences = presences[min(max(offset, 0), len(presences)):]
+	if limit >= 0 && len(presences) > limit {
+		presences = presences[:limit]
+	}
+
+	return presences, nil
+}
+
+func (m *memoryPresenceRepository) Cleanup(before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for playerId, presence := range m.presences {
+		if presence.LastSeenAt.Before(before) {
+			delete(m.presences, playerId)
+		}
+	}
+
+	return nil
+}
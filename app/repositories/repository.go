@@ -16,13 +16,43 @@ const (
 )
 
 type Repository struct {
-	Player interfaces.RepositoryPlayer
-	Game   interfaces.GameRepository
+	Player        interfaces.RepositoryPlayer
+	Game          interfaces.GameRepository
+	ApiKey        interfaces.RepositoryApiKey
+	Tournament    interfaces.RepositoryTournament
+	Session       interfaces.RepositorySession
+	PasswordReset interfaces.RepositoryPasswordReset
+	TwoFactor     interfaces.RepositoryTwoFactor
+	LoginThrottle interfaces.RepositoryLoginThrottle
+	LoginFailure  interfaces.RepositoryLoginFailure
+	Audit         interfaces.RepositoryAudit
+	Points        interfaces.RepositoryPoints
+	Ban           interfaces.RepositoryBan
+	Friend        interfaces.RepositoryFriend
+	Presence      interfaces.RepositoryPresence
+	Chat          interfaces.RepositoryChat
+	Stats         interfaces.RepositoryStats
+	Leaderboard   interfaces.RepositoryLeaderboard
 }
 
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		Player: newPlayerRepository(db),
-		Game:   newGameRepository(db),
+		Player:        newPlayerRepository(db),
+		Game:          newGameRepository(db),
+		ApiKey:        newApiKeyRepository(db),
+		Tournament:    newTournamentRepository(db),
+		Session:       newSessionRepository(db),
+		PasswordReset: newPasswordResetRepository(db),
+		TwoFactor:     newTwoFactorRepository(db),
+		LoginThrottle: newLoginThrottleRepository(db),
+		LoginFailure:  newLoginFailureRepository(db),
+		Audit:         newAuditRepository(db),
+		Points:        newPointsRepository(db),
+		Ban:           newBanRepository(db),
+		Friend:        newFriendRepository(db),
+		Presence:      newPresenceRepository(db),
+		Chat:          newChatRepository(db),
+		Stats:         newStatsRepository(db),
+		Leaderboard:   newLeaderboardRepository(db),
 	}
 }
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func newSessionRepository(db *gorm.DB) *sessionRepository {
+	return &sessionRepository{db}
+}
+
+func (s *sessionRepository) Create(session *entities.Session, refreshToken *entities.RefreshToken) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(refreshToken).Error
+	})
+}
+
+func (s *sessionRepository) FindById(sessionId uuid.UUID) (*entities.Session, error) {
+	var sessions []entities.Session
+	if err := s.db.Limit(1).Find(&sessions, "id = ?", sessionId).Error; err != nil {
+		return nil, err
+	}
+
+	if len(sessions) == 0 {
+		return nil, customErrors.NewNotFoundError("session was not found")
+	}
+
+	return &sessions[0], nil
+}
+
+func (s *sessionRepository) FindActiveByPlayer(playerId uuid.UUID, now time.Time) ([]entities.Session, error) {
+	var sessions []entities.Session
+	err := s.db.
+		Order("last_used_at DESC").
+		Find(&sessions, "player_id = ? AND revoked_at IS NULL AND expires_at > ?", playerId, now).
+		Error
+
+	return sessions, err
+}
+
+func (s *sessionRepository) FindRefreshToken(tokenHash string) (*entities.RefreshToken, error) {
+	var refreshTokens []entities.RefreshToken
+	if err := s.db.Limit(1).Find(&refreshTokens, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+
+	if len(refreshTokens) == 0 {
+		return nil, customErrors.NewNotFoundError("refresh token was not found")
+	}
+
+	return &refreshTokens[0], nil
+}
+
+// Rotate marks the used refresh token and stores the next one of the same session.
+// It returns false when the token has already been used by a concurrent request.
+func (s *sessionRepository) Rotate(used, next *entities.RefreshToken) (bool, error) {
+	rotated := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&entities.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", used.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		if err := tx.
+			Model(&entities.Session{}).
+			Where("id = ?", next.SessionID).
+			Updates(map[string]interface{}{
+				"expires_at":   next.ExpiresAt,
+				"last_used_at": time.Now(),
+			}).
+			Error; err != nil {
+			return err
+		}
+
+		rotated = true
+
+		return nil
+	})
+
+	return rotated, err
+}
+
+// TouchLastUsed records the session activity unless it was already recorded after the given time.
+func (s *sessionRepository) TouchLastUsed(sessionId uuid.UUID, ip string, before time.Time) error {
+	return s.db.
+		Model(&entities.Session{}).
+		Where("id = ? AND last_used_at < ?", sessionId, before).
+		Updates(map[string]interface{}{
+			"last_used_at": time.Now(),
+			"ip":           ip,
+		}).
+		Error
+}
+
+func (s *sessionRepository) Revoke(sessionId uuid.UUID) error {
+	return s.db.
+		Model(&entities.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionId).
+		Update("revoked_at", time.Now()).
+		Error
+}
+
+func (s *sessionRepository) RevokeAll(playerId uuid.UUID) error {
+	return revokeSessions(s.db, playerId)
+}
+
+func revokeSessions(tx *gorm.DB, playerId uuid.UUID) error {
+	return tx.
+		Model(&entities.Session{}).
+		Where("player_id = ? AND revoked_at IS NULL", playerId).
+		Update("revoked_at", time.Now()).
+		Error
+}
+
+func (s *sessionRepository) RevokeAllExcept(playerId, sessionId uuid.UUID) error {
+	return s.db.
+		Model(&entities.Session{}).
+		Where("player_id = ? AND id <> ? AND revoked_at IS NULL", playerId, sessionId).
+		Update("revoked_at", time.Now()).
+		Error
+}
+
+func (s *sessionRepository) RevokeOwned(playerId, sessionId uuid.UUID) error {
+	result := s.db.
+		Model(&entities.Session{}).
+		Where("id = ? AND player_id = ? AND revoked_at IS NULL", sessionId, playerId).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return customErrors.NewNotFoundError("session was not found")
+	}
+
+	return nil
+}
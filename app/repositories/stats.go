@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+func newStatsRepository(db *gorm.DB) *statsRepository {
+	return &statsRepository{db}
+}
+
+func (s *statsRepository) Find(playerId uuid.UUID) (*entities.PlayerStats, error) {
+	var stats []entities.PlayerStats
+	if err := s.db.
+		Preload("RuleSets").
+		Preload("Throws").
+		Limit(1).
+		Find(&stats, "player_id = ?", playerId).
+		Error; err != nil {
+		return nil, err
+	}
+
+	if len(stats) == 0 {
+		return nil, customErrors.NewNotFoundError(fmt.Sprintf("stats of player with id %s not found", playerId))
+	}
+
+	return &stats[0], nil
+}
+
+func (s *statsRepository) FindOpponent(playerId, opponentId uuid.UUID) (*entities.PlayerOpponentStats, error) {
+	var stats []entities.PlayerOpponentStats
+	if err := s.db.Limit(1).Find(&stats, "player_id = ? AND opponent_id = ?", playerId, opponentId).Error; err != nil {
+		return nil, err
+	}
+
+	if len(stats) == 0 {
+		return nil, customErrors.NewNotFoundError(fmt.Sprintf("player with id %s never met %s", playerId, opponentId))
+	}
+
+	return &stats[0], nil
+}
+
+func (s *statsRepository) Apply(
+	playerId uuid.UUID,
+	opponentIds []uuid.UUID,
+	change func(
+		stats *entities.PlayerStats,
+		opponents []entities.PlayerOpponentStats,
+	) (*entities.PlayerStats, []entities.PlayerOpponentStats),
+) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		stats, err := lockStats(tx, playerId)
+		if err != nil {
+			return err
+		}
+		if err := tx.Find(&stats.RuleSets, "player_id = ?", playerId).Error; err != nil {
+			return err
+		}
+		if err := tx.Find(&stats.Throws, "player_id = ?", playerId).Error; err != nil {
+			return err
+		}
+		opponents := make([]entities.PlayerOpponentStats, 0, len(opponentIds))
+		if len(opponentIds) > 0 {
+			if err := tx.Find(&opponents, "player_id = ? AND opponent_id IN ?", playerId, opponentIds).Error; err != nil {
+				return err
+			}
+		}
+
+		stats, opponents = change(stats, opponents)
+
+		return saveStats(tx, stats, opponents)
+	})
+}
+
+func (s *statsRepository) Replace(stats *entities.PlayerStats, opponents []entities.PlayerOpponentStats) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockStats(tx, stats.PlayerID); err != nil {
+			return err
+		}
+		for _, model := range []interface{}{
+			&entities.PlayerOpponentStats{},
+			&entities.PlayerThrowStats{},
+			&entities.PlayerRuleSetStats{},
+		} {
+			if err := tx.Where("player_id = ?", stats.PlayerID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return saveStats(tx, stats, opponents)
+	})
+}
+
+func (s *statsRepository) FindUnbuilt() ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	err := s.db.
+		Model(&entities.Player{}).
+		Where("NOT is_bot AND anonymised_at IS NULL").
+		Where("id IN (?)", s.db.
+			Model(&entities.GamePlayer{}).
+			Select("game_players.player_id").
+			Joins("JOIN games ON games.id = game_players.game_id").
+			Where("games.status = ? AND games.cancelled_at IS NULL", dictionary.GameStatusFinished),
+		).
+		Where("id NOT IN (?)", s.db.Model(&entities.PlayerStats{}).Select("player_id")).
+		Pluck("id", &ids).
+		Error
+
+	return ids, err
+}
+
+// lockStats creates the stats row of the player when there is none yet and locks it for the transaction,
+// the updates of one player queue up on it.
+func lockStats(tx *gorm.DB, playerId uuid.UUID) (*entities.PlayerStats, error) {
+	if err := tx.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entities.PlayerStats{PlayerID: playerId, UpdatedAt: time.Now()}).
+		Error; err != nil {
+		return nil, err
+	}
+
+	stats := &entities.PlayerStats{}
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(stats, "player_id = ?", playerId).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func saveStats(tx *gorm.DB, stats *entities.PlayerStats, opponents []entities.PlayerOpponentStats) error {
+	if err := tx.Omit(clause.Associations).Save(stats).Error; err != nil {
+		return err
+	}
+	if len(stats.RuleSets) > 0 {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&stats.RuleSets).Error; err != nil {
+			return err
+		}
+	}
+	if len(stats.Throws) > 0 {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&stats.Throws).Error; err != nil {
+			return err
+		}
+	}
+	if len(opponents) > 0 {
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&opponents).Error
+	}
+
+	return nil
+}
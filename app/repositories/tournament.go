@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+type tournamentRepository struct {
+	db *gorm.DB
+}
+
+func newTournamentRepository(db *gorm.DB) *tournamentRepository {
+	return &tournamentRepository{db}
+}
+
+func (t *tournamentRepository) Create(tournament *entities.Tournament) error {
+	return t.db.Create(tournament).Error
+}
+
+func (t *tournamentRepository) FindById(tournamentId uuid.UUID) (*entities.Tournament, error) {
+	var tournament *entities.Tournament
+
+	err := t.db.
+		Preload("Players").
+		Preload("Matches", func(db *gorm.DB) *gorm.DB {
+			return db.Order("bracket DESC, round, position")
+		}).
+		First(&tournament, "id = ?", tournamentId).
+		Error
+
+	return tournament, err
+}
+
+func (t *tournamentRepository) AddPlayer(tournament *entities.Tournament, playerId uuid.UUID) error {
+	return t.db.Model(&tournament).Association("Players").Append(&entities.Player{ID: playerId})
+}
+
+// RemovePlayerFromRegistrations takes the player out of the tournaments that have not started yet.
+func (t *tournamentRepository) RemovePlayerFromRegistrations(playerId uuid.UUID) (int64, error) {
+	return removePlayerFromRegistrations(t.db, playerId)
+}
+
+func removePlayerFromRegistrations(db *gorm.DB, playerId uuid.UUID) (int64, error) {
+	result := db.
+		Where("player_id = ? AND tournament_id IN (?)", playerId, db.
+			Model(&entities.Tournament{}).
+			Select("id").
+			Where("status = ?", dictionary.TournamentStatusRegistration),
+		).
+		Delete(&entities.TournamentPlayer{})
+
+	return result.RowsAffected, result.Error
+}
+
+func (t *tournamentRepository) FindTournamentPlayers(tournamentId uuid.UUID) ([]entities.TournamentPlayer, error) {
+	var tournamentPlayers []entities.TournamentPlayer
+	err := t.db.Order("seed").Find(&tournamentPlayers, "tournament_id = ?", tournamentId).Error
+
+	return tournamentPlayers, err
+}
+
+func (t *tournamentRepository) Start(
+	tournament *entities.Tournament,
+	seeds map[uuid.UUID]uint,
+	matches []*entities.TournamentMatch,
+) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		for playerId, seed := range seeds {
+			if err := tx.
+				Model(&entities.TournamentPlayer{}).
+				Where("tournament_id = ? AND player_id = ?", tournament.ID, playerId).
+				Update("seed", seed).
+				Error; err != nil {
+				return err
+			}
+		}
+
+		if len(matches) > 0 {
+			if err := tx.Create(matches).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		tournament.Status = dictionary.TournamentStatusRunning
+		tournament.StartedAt = &now
+
+		return tx.
+			Model(&entities.Tournament{}).
+			Where("id = ?", tournament.ID).
+			Updates(map[string]interface{}{
+				"status":     tournament.Status,
+				"started_at": tournament.StartedAt,
+				"rounds":     tournament.Rounds,
+			}).
+			Error
+	})
+}
+
+func (t *tournamentRepository) CreateMatches(matches []*entities.TournamentMatch) error {
+	return t.db.Create(matches).Error
+}
+
+func (t *tournamentRepository) SaveMatch(match *entities.TournamentMatch) error {
+	return t.db.Save(match).Error
+}
+
+func (t *tournamentRepository) Finish(tournament *entities.Tournament, winnerId *uuid.UUID) error {
+	now := time.Now()
+	tournament.Status = dictionary.TournamentStatusFinished
+	tournament.FinishedAt = &now
+	tournament.WinnerID = winnerId
+
+	return t.db.
+		Model(&entities.Tournament{}).
+		Where("id = ?", tournament.ID).
+		Updates(map[string]interface{}{
+			"status":      tournament.Status,
+			"finished_at": tournament.FinishedAt,
+			"winner_id":   tournament.WinnerID,
+		}).
+		Error
+}
+
+func (t *tournamentRepository) FindMatchByGame(gameId uuid.UUID) (*entities.TournamentMatch, error) {
+	var matches []entities.TournamentMatch
+	if err := t.db.Limit(1).Find(&matches, "game_id = ?", gameId).Error; err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, customErrors.NewNotFoundError("tournament match was not found")
+	}
+
+	return &matches[0], nil
+}
+
+func (t *tournamentRepository) FindOverdueMatches(now time.Time) ([]entities.TournamentMatch, error) {
+	var matches []entities.TournamentMatch
+	err := t.db.Find(
+		&matches,
+		"status = ? AND deadline < ?",
+		dictionary.TournamentMatchStatusPlaying, now,
+	).Error
+
+	return matches, err
+}
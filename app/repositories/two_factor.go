@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"time"
+)
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func newTwoFactorRepository(db *gorm.DB) *twoFactorRepository {
+	return &twoFactorRepository{db}
+}
+
+func (t *twoFactorRepository) Find(playerId uuid.UUID) (*entities.TwoFactor, error) {
+	var twoFactors []entities.TwoFactor
+	if err := t.db.Limit(1).Find(&twoFactors, "player_id = ?", playerId).Error; err != nil {
+		return nil, err
+	}
+
+	if len(twoFactors) == 0 {
+		return nil, customErrors.NewNotFoundError("two-factor authentication was not found")
+	}
+
+	return &twoFactors[0], nil
+}
+
+// SavePending replaces an unconfirmed enrollment, a confirmed one is kept and the insert fails.
+func (t *twoFactorRepository) SavePending(twoFactor *entities.TwoFactor) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("player_id = ? AND confirmed_at IS NULL", twoFactor.PlayerID).
+			Delete(&entities.TwoFactor{}).
+			Error; err != nil {
+			return err
+		}
+
+		return tx.Create(twoFactor).Error
+	})
+}
+
+// Confirm enables the pending enrollment with its first code and stores the recovery codes.
+// It returns false when there is nothing to confirm or the code has already been used.
+func (t *twoFactorRepository) Confirm(
+	playerId uuid.UUID,
+	step int64,
+	recoveryCodes []*entities.RecoveryCode,
+) (bool, error) {
+	confirmed := false
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&entities.TwoFactor{}).
+			Where("player_id = ? AND confirmed_at IS NULL AND last_used_step < ?", playerId, step).
+			Updates(map[string]interface{}{
+				"confirmed_at":   time.Now(),
+				"last_used_step": step,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := replaceRecoveryCodes(tx, playerId, recoveryCodes); err != nil {
+			return err
+		}
+
+		confirmed = true
+
+		return nil
+	})
+
+	return confirmed, err
+}
+
+// UseStep accepts a code of the given time step only if no code of this or a later step was accepted.
+func (t *twoFactorRepository) UseStep(playerId uuid.UUID, step int64) (bool, error) {
+	result := t.db.
+		Model(&entities.TwoFactor{}).
+		Where("player_id = ? AND confirmed_at IS NOT NULL AND last_used_step < ?", playerId, step).
+		Update("last_used_step", step)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (t *twoFactorRepository) UseRecoveryCode(playerId uuid.UUID, codeHash string) (bool, error) {
+	result := t.db.
+		Model(&entities.RecoveryCode{}).
+		Where("player_id = ? AND code_hash = ? AND used_at IS NULL", playerId, codeHash).
+		Update("used_at", time.Now())
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (t *twoFactorRepository) ReplaceRecoveryCodes(playerId uuid.UUID, recoveryCodes []*entities.RecoveryCode) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, playerId, recoveryCodes)
+	})
+}
+
+func (t *twoFactorRepository) CountRecoveryCodes(playerId uuid.UUID) (int64, error) {
+	var count int64
+	err := t.db.
+		Model(&entities.RecoveryCode{}).
+		Where("player_id = ? AND used_at IS NULL", playerId).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+func (t *twoFactorRepository) Delete(playerId uuid.UUID) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("player_id = ?", playerId).Delete(&entities.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("player_id = ?", playerId).Delete(&entities.TwoFactor{}).Error
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, playerId uuid.UUID, recoveryCodes []*entities.RecoveryCode) error {
+	if err := tx.Where("player_id = ?", playerId).Delete(&entities.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(recoveryCodes).Error
+}
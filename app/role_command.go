@@ -0,0 +1,61 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/repositories"
+	"knb/app/services"
+	"strings"
+)
+
+const RoleCommandName = "role"
+
+const roleCommandUsage = "Usage: knb role grant|revoke <login> <role>"
+
+// RunRoleCommand executes `knb role grant|revoke <login> <role>` and returns the process exit code,
+// the first admin can only be appointed this way.
+func (app *Application) RunRoleCommand(args []string, out io.Writer) int {
+	if len(args) != 3 {
+		_, _ = fmt.Fprintln(out, roleCommandUsage)
+		return 2
+	}
+	action, login, role := args[0], args[1], dictionary.Role(args[2])
+	if action != "grant" && action != "revoke" {
+		_, _ = fmt.Fprintln(out, roleCommandUsage)
+		return 2
+	}
+
+	if err := app.connectDB(); err != nil {
+		_, _ = fmt.Fprint(out, err.Error())
+		return 1
+	}
+	repository := repositories.NewRepository(app.db.DB())
+	service := services.NewService(repository, app.config)
+
+	player, err := repository.Player.FindByLogin(login)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Player %s is not found: %s\n", login, err.Error())
+		return 1
+	}
+
+	var roles entities.Roles
+	if action == "grant" {
+		roles, err = service.Role.Grant(player.ID, role, nil)
+	} else {
+		roles, err = service.Role.Revoke(player.ID, role, nil)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Failed to %s role %s: %s\n", action, role, err.Error())
+		return 1
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, name := range roles {
+		names = append(names, string(name))
+	}
+	_, _ = fmt.Fprintf(out, "Roles of %s: [%s]\n", login, strings.Join(names, ", "))
+
+	return 0
+}
@@ -0,0 +1,151 @@
+package rules
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+)
+
+const (
+	MinPlayers = 2
+	MaxRounds  = 100
+)
+
+type RoundOutcome struct {
+	Results  []entities.GameResult
+	Finished bool
+}
+
+func Beats(throw, other dictionary.Throw) bool {
+	switch throw {
+	case dictionary.ThrowRock:
+		return other == dictionary.ThrowScissors
+	case dictionary.ThrowScissors:
+		return other == dictionary.ThrowPaper
+	case dictionary.ThrowPaper:
+		return other == dictionary.ThrowRock
+	}
+
+	return false
+}
+
+func Counter(throw dictionary.Throw) dictionary.Throw {
+	for _, candidate := range dictionary.Throws {
+		if Beats(candidate, throw) {
+			return candidate
+		}
+	}
+
+	return dictionary.ThrowRock
+}
+
+func IsValidThrow(throw dictionary.Throw) bool {
+	for _, candidate := range dictionary.Throws {
+		if candidate == throw {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Losers returns the players eliminated by a single round of moves. A round is a draw when
+// everybody shows the same throw or all three throws are on the table.
+func Losers(moves []entities.GameMove) []uuid.UUID {
+	shown := make(map[dictionary.Throw]bool, len(dictionary.Throws))
+	for _, move := range moves {
+		shown[move.Throw] = true
+	}
+	if len(shown) != 2 {
+		return nil
+	}
+
+	var losing dictionary.Throw
+	for throw := range shown {
+		for other := range shown {
+			if Beats(other, throw) {
+				losing = throw
+			}
+		}
+	}
+
+	losers := make([]uuid.UUID, 0, len(moves))
+	for _, move := range moves {
+		if move.Throw == losing {
+			losers = append(losers, move.PlayerID)
+		}
+	}
+
+	return losers
+}
+
+// CompleteRound settles a round played by the active players and returns the places decided by it.
+func CompleteRound(gameId uuid.UUID, active []uuid.UUID, moves []entities.GameMove, round uint) RoundOutcome {
+	losers := Losers(moves)
+	if len(losers) == 0 {
+		if round < MaxRounds {
+			return RoundOutcome{}
+		}
+
+		results := make([]entities.GameResult, 0, len(active))
+		for _, playerId := range active {
+			results = append(results, entities.GameResult{GameID: gameId, PlayerID: playerId, Place: 1})
+		}
+
+		return RoundOutcome{Results: results, Finished: true}
+	}
+
+	eliminated := make(map[uuid.UUID]bool, len(losers))
+	for _, playerId := range losers {
+		eliminated[playerId] = true
+	}
+
+	place := uint8(len(active) - len(losers) + 1)
+	results := make([]entities.GameResult, 0, len(active))
+	remaining := make([]uuid.UUID, 0, len(active))
+	for _, playerId := range active {
+		if eliminated[playerId] {
+			results = append(results, entities.GameResult{GameID: gameId, PlayerID: playerId, Place: place})
+		} else {
+			remaining = append(remaining, playerId)
+		}
+	}
+
+	if len(remaining) > 1 {
+		return RoundOutcome{Results: results}
+	}
+
+	for _, playerId := range remaining {
+		results = append(results, entities.GameResult{GameID: gameId, PlayerID: playerId, Place: 1})
+	}
+
+	return RoundOutcome{Results: results, Finished: true}
+}
+
+func Payouts(prizes []entities.GamePrize, results []entities.GameResult) map[uuid.UUID]uint {
+	payouts := make(map[uuid.UUID]uint, len(results))
+	for _, result := range results {
+		for _, prize := range prizes {
+			if prize.Place == result.Place && prize.Prize > 0 {
+				payouts[result.PlayerID] += prize.Prize
+			}
+		}
+	}
+
+	return payouts
+}
+
+// RuleSet classifies a game, a game against a built-in bot is a practice whatever its prizes.
+// The bots of api keys play for their owners, their games count as any other.
+func RuleSet(game *entities.Game) dictionary.GameRuleSet {
+	for _, player := range game.Players {
+		if player.IsBuiltInBot() {
+			return dictionary.GameRuleSetPractice
+		}
+	}
+	if len(game.Prizes) > 0 {
+		return dictionary.GameRuleSetStaked
+	}
+
+	return dictionary.GameRuleSetClassic
+}
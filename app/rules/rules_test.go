@@ -0,0 +1,188 @@
+package rules
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"testing"
+)
+
+var (
+	gameId      = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	playerOne   = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	playerTwo   = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	playerThree = uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+)
+
+type losersTestCase struct {
+	throws   []dictionary.Throw
+	expected []uuid.UUID
+	name     string
+}
+
+type completeRoundTestCase struct {
+	active   []uuid.UUID
+	throws   []dictionary.Throw
+	round    uint
+	expected RoundOutcome
+	name     string
+}
+
+// roundMoves gives the throws to the players in order.
+func roundMoves(players []uuid.UUID, throws []dictionary.Throw) []entities.GameMove {
+	moves := make([]entities.GameMove, 0, len(throws))
+	for index, throw := range throws {
+		moves = append(moves, entities.GameMove{GameID: gameId, PlayerID: players[index], Throw: throw})
+	}
+
+	return moves
+}
+
+func TestLosers(t *testing.T) {
+	players := []uuid.UUID{playerOne, playerTwo, playerThree}
+	testCases := []losersTestCase{
+		{
+			throws:   []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowScissors},
+			expected: []uuid.UUID{playerTwo},
+			name:     "rock beats scissors",
+		},
+		{
+			throws:   []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowPaper},
+			expected: []uuid.UUID{playerOne},
+			name:     "paper beats rock",
+		},
+		{
+			throws:   []dictionary.Throw{dictionary.ThrowPaper, dictionary.ThrowScissors},
+			expected: []uuid.UUID{playerOne},
+			name:     "scissors beat paper",
+		},
+		{
+			throws: []dictionary.Throw{dictionary.ThrowPaper, dictionary.ThrowPaper},
+			name:   "the same throw is a draw",
+		},
+		{
+			throws:   []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowScissors, dictionary.ThrowScissors},
+			expected: []uuid.UUID{playerTwo, playerThree},
+			name:     "several players lose at once",
+		},
+		{
+			throws: []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowScissors, dictionary.ThrowPaper},
+			name:   "all three throws are a draw",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			losers := Losers(roundMoves(players, tCase.throws))
+			if tCase.expected == nil {
+				assert.Empty(tt, losers)
+				return
+			}
+			assert.ElementsMatch(tt, tCase.expected, losers)
+		})
+	}
+}
+
+func TestCompleteRound(t *testing.T) {
+	testCases := []completeRoundTestCase{
+		{
+			active: []uuid.UUID{playerOne, playerTwo},
+			throws: []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowRock},
+			round:  1,
+			name:   "a draw settles nothing",
+		},
+		{
+			active: []uuid.UUID{playerOne, playerTwo},
+			throws: []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowScissors},
+			round:  1,
+			expected: RoundOutcome{
+				Results: []entities.GameResult{
+					{GameID: gameId, PlayerID: playerTwo, Place: 2},
+					{GameID: gameId, PlayerID: playerOne, Place: 1},
+				},
+				Finished: true,
+			},
+			name: "the winner of two players finishes the game",
+		},
+		{
+			active: []uuid.UUID{playerOne, playerTwo, playerThree},
+			throws: []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowRock, dictionary.ThrowScissors},
+			round:  1,
+			expected: RoundOutcome{
+				Results: []entities.GameResult{{GameID: gameId, PlayerID: playerThree, Place: 3}},
+			},
+			name: "the loser of three players takes the last place",
+		},
+		{
+			active: []uuid.UUID{playerOne, playerTwo, playerThree},
+			throws: []dictionary.Throw{dictionary.ThrowPaper, dictionary.ThrowRock, dictionary.ThrowRock},
+			round:  1,
+			expected: RoundOutcome{
+				Results: []entities.GameResult{
+					{GameID: gameId, PlayerID: playerTwo, Place: 2},
+					{GameID: gameId, PlayerID: playerThree, Place: 2},
+					{GameID: gameId, PlayerID: playerOne, Place: 1},
+				},
+				Finished: true,
+			},
+			name: "the losers of one round share the place",
+		},
+		{
+			active: []uuid.UUID{playerOne, playerTwo},
+			throws: []dictionary.Throw{dictionary.ThrowRock, dictionary.ThrowRock},
+			round:  MaxRounds,
+			expected: RoundOutcome{
+				Results: []entities.GameResult{
+					{GameID: gameId, PlayerID: playerOne, Place: 1},
+					{GameID: gameId, PlayerID: playerTwo, Place: 1},
+				},
+				Finished: true,
+			},
+			name: "a draw in the last round makes everybody first",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			outcome := CompleteRound(gameId, tCase.active, roundMoves(tCase.active, tCase.throws), tCase.round)
+			assert.Equal(tt, tCase.expected.Finished, outcome.Finished)
+			assert.ElementsMatch(tt, tCase.expected.Results, outcome.Results)
+		})
+	}
+}
+
+func TestPayouts(t *testing.T) {
+	prizes := []entities.GamePrize{
+		{GameID: gameId, Place: 1, Prize: 10},
+		{GameID: gameId, Place: 2, Prize: 5},
+	}
+	results := []entities.GameResult{
+		{GameID: gameId, PlayerID: playerOne, Place: 1},
+		{GameID: gameId, PlayerID: playerTwo, Place: 1},
+		{GameID: gameId, PlayerID: playerThree, Place: 3},
+	}
+
+	payouts := Payouts(prizes, results)
+	assert.Equal(t, map[uuid.UUID]uint{playerOne: 10, playerTwo: 10}, payouts)
+	assert.Empty(t, Payouts(nil, results))
+}
+
+func TestRuleSet(t *testing.T) {
+	human := entities.Player{ID: playerOne}
+	bot := *entities.NewBot(dictionary.BotStrategyRandom)
+
+	assert.Equal(t, dictionary.GameRuleSetClassic, RuleSet(&entities.Game{Players: []entities.Player{human}}))
+	assert.Equal(t, dictionary.GameRuleSetStaked, RuleSet(&entities.Game{
+		Players: []entities.Player{human},
+		Prizes:  []entities.GamePrize{{Place: 1, Prize: 1}},
+	}))
+	assert.Equal(t, dictionary.GameRuleSetPractice, RuleSet(&entities.Game{
+		Players: []entities.Player{human, bot},
+		Prizes:  []entities.GamePrize{{Place: 1, Prize: 1}},
+	}))
+	assert.Equal(t, dictionary.GameRuleSetStaked, RuleSet(&entities.Game{
+		Players: []entities.Player{human, *entities.NewOwnedBot(&human)},
+		Prizes:  []entities.GamePrize{{Place: 1, Prize: 1}},
+	}))
+}
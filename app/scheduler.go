@@ -0,0 +1,36 @@
+package app
+
+import (
+	"log"
+	"time"
+)
+
+type scheduler struct {
+	stop chan struct{}
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{stop: make(chan struct{})}
+}
+
+func (s *scheduler) every(interval time.Duration, name string, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := job(); err != nil {
+					log.Printf("Scheduled job %s failed: %s\n", name, err.Error())
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *scheduler) shutdown() {
+	close(s.stop)
+}
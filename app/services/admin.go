@@ -0,0 +1,280 @@
+package services
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAdminPageSize = 50
+	maxAdminPageSize     = 200
+	maxAuditReasonLength = 512
+)
+
+type adminService struct {
+	game             interfaces.ServiceGame
+	playerRepository interfaces.RepositoryPlayer
+	gameRepository   interfaces.GameRepository
+	pointsRepository interfaces.RepositoryPoints
+	banRepository    interfaces.RepositoryBan
+	auditRepository  interfaces.RepositoryAudit
+	leaderboard      interfaces.ServiceLeaderboard
+	presence         interfaces.ServicePresence
+}
+
+func newAdminService(
+	game interfaces.ServiceGame,
+	playerRepository interfaces.RepositoryPlayer,
+	gameRepository interfaces.GameRepository,
+	pointsRepository interfaces.RepositoryPoints,
+	banRepository interfaces.RepositoryBan,
+	auditRepository interfaces.RepositoryAudit,
+	leaderboard interfaces.ServiceLeaderboard,
+	presence interfaces.ServicePresence,
+) *adminService {
+	return &adminService{
+		game:             game,
+		playerRepository: playerRepository,
+		gameRepository:   gameRepository,
+		pointsRepository: pointsRepository,
+		banRepository:    banRepository,
+		auditRepository:  auditRepository,
+		leaderboard:      leaderboard,
+		presence:         presence,
+	}
+}
+
+func (a *adminService) SearchPlayers(query string, limit, offset int) ([]entities.Player, error) {
+	return a.playerRepository.Search(strings.TrimSpace(query), pageSize(limit), max(offset, 0))
+}
+
+func (a *adminService) PlayerGames(playerId uuid.UUID, limit int) ([]entities.Game, error) {
+	if _, err := a.playerRepository.FindById(playerId); err != nil {
+		return nil, err
+	}
+
+	return a.gameRepository.FindByPlayer(playerId, pageSize(limit))
+}
+
+func (a *adminService) PlayerPoints(playerId uuid.UUID, limit int) (*entities.PlayerPoints, error) {
+	player, err := a.playerRepository.FindById(playerId)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := a.pointsRepository.FindByPlayer(playerId, pageSize(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.PlayerPoints{Balance: player.Points, Transactions: transactions}, nil
+}
+
+// Ban takes the player out of the games and the tournaments that have not started yet,
+// the running ones are left to the admins to finish. A zero duration bans for good.
+func (a *adminService) Ban(actorId, playerId uuid.UUID, reason string, duration time.Duration) (*entities.Ban, error) {
+	reason, err := auditReason(reason, true)
+	if err != nil {
+		return nil, err
+	}
+	if duration < 0 {
+		return nil, customErrors.NewBadRequestError("ban duration must not be negative")
+	}
+	if actorId == playerId {
+		return nil, customErrors.NewBadRequestError("you can't ban yourself")
+	}
+	if _, err := a.playerRepository.FindById(playerId); err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if duration > 0 {
+		expiration := time.Now().Add(duration)
+		expiresAt = &expiration
+	}
+	ban := entities.NewBan(playerId, reason, &actorId, expiresAt)
+	details := map[string]interface{}{"ban_id": ban.ID, "expires_at": ban.ExpiresAt}
+	if err := a.banRepository.Impose(
+		ban,
+		playerAudit(actorId, dictionary.AuditActionPlayerBan, playerId, reason, details),
+	); err != nil {
+		return nil, err
+	}
+	a.presence.LeftGames(playerId)
+
+	return ban, nil
+}
+
+func (a *adminService) Unban(actorId, playerId uuid.UUID, reason string) error {
+	reason, err := auditReason(reason, false)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := a.banRepository.Revoke(
+		playerId,
+		&actorId,
+		time.Now(),
+		playerAudit(actorId, dictionary.AuditActionPlayerUnban, playerId, reason, nil),
+	)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return customErrors.NewNotFoundError("the player is not banned")
+	}
+
+	return nil
+}
+
+func (a *adminService) AdjustPoints(
+	actorId, playerId uuid.UUID,
+	amount int,
+	reason string,
+) (*entities.PointsTransaction, error) {
+	reason, err := auditReason(reason, true)
+	if err != nil {
+		return nil, err
+	}
+	if amount == 0 {
+		return nil, customErrors.NewBadRequestError("amount must not be zero")
+	}
+	if _, err := a.playerRepository.FindById(playerId); err != nil {
+		return nil, err
+	}
+
+	transaction := entities.NewPointsTransaction(
+		playerId, amount, dictionary.PointsKindAdjustment, nil, &actorId, reason,
+	)
+	details := map[string]interface{}{"amount": amount, "transaction_id": transaction.ID}
+	adjusted, err := a.pointsRepository.Adjust(
+		transaction,
+		playerAudit(actorId, dictionary.AuditActionPointsAdjust, playerId, reason, details),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !adjusted {
+		return nil, customErrors.NewBadRequestError("points can't go below zero")
+	}
+	a.leaderboard.PointsChanged(playerId)
+
+	return transaction, nil
+}
+
+func (a *adminService) CancelGame(
+	actorId, gameId uuid.UUID,
+	reason string,
+) (*entities.Game, []entities.PointsTransaction, error) {
+	reason, err := auditReason(reason, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.game.Cancel(
+		gameId,
+		&actorId,
+		reason,
+		gameAudit(actorId, dictionary.AuditActionGameCancel, gameId, reason, nil),
+	)
+}
+
+func (a *adminService) FinishGame(
+	actorId, gameId uuid.UUID,
+	winnerId *uuid.UUID,
+	reason string,
+) (*entities.Game, error) {
+	reason, err := auditReason(reason, false)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]interface{}{"winner_id": winnerId}
+
+	return a.game.ForceFinish(
+		gameId,
+		winnerId,
+		gameAudit(actorId, dictionary.AuditActionGameFinish, gameId, reason, details),
+	)
+}
+
+func (a *adminService) UpdateGamePrizes(
+	actorId, gameId uuid.UUID,
+	prizes []entities.GamePrize,
+	reason string,
+) (*entities.Game, error) {
+	reason, err := auditReason(reason, false)
+	if err != nil {
+		return nil, err
+	}
+
+	table := make(map[string]uint, len(prizes))
+	for _, prize := range prizes {
+		table[strconv.Itoa(int(prize.Place))] = prize.Prize
+	}
+
+	details := map[string]interface{}{"prizes": table}
+
+	return a.game.UpdatePrizes(
+		gameId,
+		prizes,
+		gameAudit(actorId, dictionary.AuditActionGamePrizes, gameId, reason, details),
+	)
+}
+
+func (a *adminService) AuditLog(filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	filter.Limit = pageSize(filter.Limit)
+
+	return a.auditRepository.Find(filter)
+}
+
+// playerAudit and gameAudit make the entries the repositories write in the transaction of the action,
+// so that a change is never left without its entry.
+func playerAudit(
+	actorId uuid.UUID,
+	action dictionary.AuditAction,
+	playerId uuid.UUID,
+	reason string,
+	details map[string]interface{},
+) *entities.AuditEntry {
+	return entities.NewAuditEntry(&actorId, action, dictionary.AuditTargetPlayer, playerId, reason, details)
+}
+
+func gameAudit(
+	actorId uuid.UUID,
+	action dictionary.AuditAction,
+	gameId uuid.UUID,
+	reason string,
+	details map[string]interface{},
+) *entities.AuditEntry {
+	return entities.NewAuditEntry(&actorId, action, dictionary.AuditTargetGame, gameId, reason, details)
+}
+
+// auditReason trims the reason an admin gives for an action, the actions hurting a player require one.
+func auditReason(reason string, required bool) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if required && reason == "" {
+		return "", customErrors.NewBadRequestError("reason is required")
+	}
+	if len(reason) > maxAuditReasonLength {
+		return "", customErrors.NewBadRequestError(
+			fmt.Sprintf("reason must not exceed %d characters", maxAuditReasonLength),
+		)
+	}
+
+	return reason, nil
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultAdminPageSize
+	}
+
+	return min(limit, maxAdminPageSize)
+}
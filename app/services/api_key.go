@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"log"
+)
+
+type apiKeyService struct {
+	apiKeyRepository interfaces.RepositoryApiKey
+	playerRepository interfaces.RepositoryPlayer
+	banRepository    interfaces.RepositoryBan
+	security         interfaces.ServiceSecurity
+}
+
+func newApiKeyService(
+	apiKeyRepository interfaces.RepositoryApiKey,
+	playerRepository interfaces.RepositoryPlayer,
+	banRepository interfaces.RepositoryBan,
+	security interfaces.ServiceSecurity,
+) *apiKeyService {
+	return &apiKeyService{
+		apiKeyRepository,
+		playerRepository,
+		banRepository,
+		security,
+	}
+}
+
+func (a *apiKeyService) Create(
+	ownerId uuid.UUID,
+	name string,
+	scopes []dictionary.ApiKeyScope,
+) (string, *entities.ApiKey, error) {
+	owner, err := a.playerRepository.FindById(ownerId)
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return "", nil, customErrors.NewWrongLoginError("Unauthorized")
+		}
+
+		return "", nil, err
+	}
+	if owner.IsBot {
+		return "", nil, customErrors.NewForbiddenError("bots can't create api keys")
+	}
+	if len(scopes) == 0 {
+		return "", nil, customErrors.NewBadRequestError("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !isKnownScope(scope) {
+			return "", nil, customErrors.NewBadRequestError("scope " + string(scope) + " is invalid")
+		}
+	}
+
+	key, prefix, hash, err := a.security.GenerateApiKey()
+	if err != nil {
+		return "", nil, err
+	}
+
+	bot := entities.NewOwnedBot(owner)
+	apiKey := entities.NewApiKey(ownerId, bot.ID, name, prefix, hash, scopes)
+	if err := a.apiKeyRepository.Create(bot, apiKey); err != nil {
+		return "", nil, err
+	}
+
+	return key, apiKey, nil
+}
+
+func (a *apiKeyService) List(ownerId uuid.UUID) ([]entities.ApiKey, error) {
+	return a.apiKeyRepository.FindByOwner(ownerId)
+}
+
+func (a *apiKeyService) Revoke(ownerId, apiKeyId uuid.UUID) error {
+	return a.apiKeyRepository.Revoke(ownerId, apiKeyId)
+}
+
+func (a *apiKeyService) Authenticate(key string) (*entities.ApiKey, error) {
+	apiKey, err := a.apiKeyRepository.FindByHash(a.security.HashApiKey(key))
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, customErrors.NewWrongLoginError("Unauthorized")
+		}
+
+		return nil, err
+	}
+	if apiKey.IsRevoked() {
+		return nil, customErrors.NewWrongLoginError("Unauthorized")
+	}
+	// The bots of a banned player are banned along with them.
+	if err := checkBan(a.banRepository, apiKey.OwnerID); err != nil {
+		return nil, err
+	}
+
+	if err := a.apiKeyRepository.TouchLastUsed(apiKey.ID); err != nil {
+		log.Printf("Failed to update api key %s usage: %s\n", apiKey.ID, err.Error())
+	}
+
+	return apiKey, nil
+}
+
+func isKnownScope(scope dictionary.ApiKeyScope) bool {
+	for _, knownScope := range dictionary.ApiKeyScopes {
+		if knownScope == scope {
+			return true
+		}
+	}
+
+	return false
+}
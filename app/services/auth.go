@@ -2,28 +2,87 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"knb/app/entities"
 	customErrors "knb/app/errors"
 	"knb/app/interfaces"
 	"knb/app/repositories"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	refreshTokenTTL      = 30 * 24 * time.Hour
+	sessionTouchInterval = time.Minute
+	maxUserAgentLength   = 512
+	passwordResetTTL     = time.Hour
 )
 
 type authService struct {
-	playerRepository interfaces.RepositoryPlayer
+	playerRepository        interfaces.RepositoryPlayer
+	sessionRepository       interfaces.RepositorySession
+	passwordResetRepository interfaces.RepositoryPasswordReset
+	banRepository           interfaces.RepositoryBan
+	security                interfaces.ServiceSecurity
+	mailer                  interfaces.Mailer
+	publicUrl               string
+	passwordResetUrl        string
 }
 
-func newAuthService(authRepository interfaces.RepositoryPlayer) *authService {
-	return &authService{authRepository}
+func newAuthService(
+	authRepository interfaces.RepositoryPlayer,
+	sessionRepository interfaces.RepositorySession,
+	passwordResetRepository interfaces.RepositoryPasswordReset,
+	banRepository interfaces.RepositoryBan,
+	security interfaces.ServiceSecurity,
+	mailer interfaces.Mailer,
+	publicUrl string,
+	passwordResetUrl string,
+) *authService {
+	return &authService{
+		authRepository,
+		sessionRepository,
+		passwordResetRepository,
+		banRepository,
+		security,
+		mailer,
+		publicUrl,
+		passwordResetUrl,
+	}
 }
 
-func (a *authService) Registration(login, password string) (uuid.UUID, error) {
-	player, err := a.playerRepository.Create(login, password)
+// Registration creates an unverified player and mails the verification link,
+// a failed delivery doesn't fail the registration since the link can be sent again.
+// Without a display name the player is named after the id until it's changed in the profile.
+func (a *authService) Registration(login, password, displayName string) (uuid.UUID, error) {
+	if !isValidEmail(login) {
+		return uuid.Nil, customErrors.NewBadRequestError("login must be a valid email")
+	}
+	if displayName != "" {
+		var err error
+		if displayName, err = normalizeDisplayName(displayName); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	passwordHash, err := a.security.GeneratePasswordHash(password)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	player, err := a.playerRepository.Create(login, passwordHash, displayName)
 	if err != nil {
 		var pgError *pgconn.PgError
 		if errors.As(err, &pgError) {
 			if pgError.Code == repositories.UniqueViolation {
+				if strings.Contains(pgError.ConstraintName, "display_name") {
+					return uuid.Nil, customErrors.NewUniqueViolationError("This display name is already taken")
+				}
 				return uuid.Nil, customErrors.NewUniqueViolationError("This login already exists")
 			}
 		}
@@ -31,19 +90,402 @@ func (a *authService) Registration(login, password string) (uuid.UUID, error) {
 		return uuid.Nil, err
 	}
 
+	if err := a.sendEmailVerification(player); err != nil {
+		log.Printf("Failed to send verification email to player %s: %s\n", player.ID, err.Error())
+	}
+
 	return player.ID, nil
 }
 
+func (a *authService) VerifyEmail(verificationToken string) error {
+	playerId, email, err := a.security.ParseEmailVerificationToken(verificationToken)
+	if err != nil {
+		return customErrors.NewBadRequestError("verification link is invalid or expired")
+	}
+	player, err := a.playerRepository.FindById(playerId)
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return customErrors.NewBadRequestError("verification link is invalid or expired")
+		}
+
+		return err
+	}
+	if player.Email != email {
+		return customErrors.NewBadRequestError("verification link is invalid or expired")
+	}
+
+	return a.playerRepository.MarkEmailVerified(player.ID, email)
+}
+
+func (a *authService) ResendEmailVerification(playerId uuid.UUID) error {
+	player, err := a.playerRepository.FindById(playerId)
+	if err != nil {
+		return unauthorizedIfNotFound(err)
+	}
+	if player.IsBot {
+		return customErrors.NewBadRequestError("bots have no email to verify")
+	}
+	if player.EmailVerifiedAt != nil {
+		return customErrors.NewBadRequestError("the email is already verified")
+	}
+
+	return a.sendEmailVerification(player)
+}
+
+func (a *authService) sendEmailVerification(player *entities.Player) error {
+	verificationToken, err := a.security.GenerateEmailVerificationToken(player.ID, player.Email)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/verify-email?token=%s", a.publicUrl, url.QueryEscape(verificationToken))
+
+	return a.mailer.Send(
+		player.Email,
+		"Confirm your email",
+		fmt.Sprintf("Welcome to knb!\n\nConfirm your email to play the games with prizes:\n%s\n", link),
+	)
+}
+
 func (a *authService) Login(login, password string) (*entities.Player, error) {
-	player, err := a.playerRepository.FindByLoginAndPassword(login, password)
+	player, err := a.playerRepository.FindByLogin(login)
 	if err != nil {
 		var notFoundErr *customErrors.NotFoundError
 		if errors.As(err, &notFoundErr) {
+			a.security.VerifyPassword(password, dummyPasswordHash)
 			return nil, customErrors.NewWrongLoginError("Login or Password are incorrect")
 		}
 
 		return nil, err
 	}
 
+	valid, needsRehash := a.security.VerifyPassword(password, player.Password)
+	if !valid {
+		return nil, customErrors.NewWrongLoginError("Login or Password are incorrect")
+	}
+	// Only after the password, the ban of an account is nobody else's business.
+	if err := checkBan(a.banRepository, player.ID); err != nil {
+		return nil, err
+	}
+
+	if needsRehash {
+		a.rehashPassword(player, password)
+	}
+
 	return player, nil
 }
+
+// ForgotPassword mails a reset link when the login belongs to a player. The caller gets the same
+// answer for any login, so failures are only logged and the mail is sent in the background.
+func (a *authService) ForgotPassword(login string) {
+	player, err := a.playerRepository.FindByLogin(login)
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if !errors.As(err, &notFoundErr) {
+			log.Printf("Failed to find player for password reset: %s\n", err.Error())
+		}
+		return
+	}
+	if player.IsBot {
+		return
+	}
+
+	resetToken, resetTokenHash, err := a.security.GeneratePasswordResetToken()
+	if err == nil {
+		err = a.passwordResetRepository.Create(
+			entities.NewPasswordResetToken(player.ID, resetTokenHash, time.Now().Add(passwordResetTTL)),
+		)
+	}
+	if err != nil {
+		log.Printf("Failed to create password reset token for player %s: %s\n", player.ID, err.Error())
+		return
+	}
+
+	go func() {
+		if err := a.sendPasswordReset(player.Email, resetToken); err != nil {
+			log.Printf("Failed to send password reset email to player %s: %s\n", player.ID, err.Error())
+		}
+	}()
+}
+
+// CheckPasswordReset tells whether a mailed token can still reset the password, before the new one is asked for.
+func (a *authService) CheckPasswordReset(resetToken string) error {
+	_, err := a.usablePasswordReset(resetToken)
+
+	return err
+}
+
+// ResetPassword sets the new password by a mailed token and logs the player out everywhere.
+func (a *authService) ResetPassword(resetToken, password string) error {
+	storedToken, err := a.usablePasswordReset(resetToken)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := a.security.GeneratePasswordHash(password)
+	if err != nil {
+		return err
+	}
+
+	reset, err := a.passwordResetRepository.Reset(storedToken, passwordHash)
+	if err != nil {
+		return err
+	}
+	if !reset {
+		return customErrors.NewBadRequestError("reset link is invalid or expired")
+	}
+
+	return nil
+}
+
+// ChangePassword replaces the password of a logged in player and revokes the other sessions.
+func (a *authService) ChangePassword(playerId, sessionId uuid.UUID, currentPassword, password string) error {
+	player, err := a.playerRepository.FindById(playerId)
+	if err != nil {
+		return unauthorizedIfNotFound(err)
+	}
+	if valid, _ := a.security.VerifyPassword(currentPassword, player.Password); !valid {
+		return customErrors.NewBadRequestError("current password is incorrect")
+	}
+
+	passwordHash, err := a.security.GeneratePasswordHash(password)
+	if err != nil {
+		return err
+	}
+	if err := a.playerRepository.UpdatePassword(player.ID, passwordHash); err != nil {
+		return err
+	}
+
+	return a.sessionRepository.RevokeAllExcept(player.ID, sessionId)
+}
+
+func (a *authService) usablePasswordReset(resetToken string) (*entities.PasswordResetToken, error) {
+	storedToken, err := a.passwordResetRepository.FindByHash(a.security.HashPasswordResetToken(resetToken))
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, customErrors.NewBadRequestError("reset link is invalid or expired")
+		}
+
+		return nil, err
+	}
+	if !storedToken.IsUsable(time.Now()) {
+		return nil, customErrors.NewBadRequestError("reset link is invalid or expired")
+	}
+
+	return storedToken, nil
+}
+
+func (a *authService) sendPasswordReset(email, resetToken string) error {
+	link := fmt.Sprintf("%s?token=%s", a.passwordResetUrl, url.QueryEscape(resetToken))
+
+	return a.mailer.Send(
+		email,
+		"Reset your password",
+		fmt.Sprintf(
+			"Someone asked to reset the password of your knb account.\n\n"+
+				"Follow the link within an hour to choose a new one:\n%s\n\n"+
+				"If it wasn't you, just ignore this email.\n",
+			link,
+		),
+	)
+}
+
+// StartSession opens a session for a logged in player and issues its first pair of tokens.
+func (a *authService) StartSession(playerId uuid.UUID, userAgent, ip string) (*entities.AuthTokens, error) {
+	if err := checkBan(a.banRepository, playerId); err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshTokenHash, err := a.security.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(refreshTokenTTL)
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	session := entities.NewSession(playerId, userAgent, ip, expiresAt)
+	if err := a.sessionRepository.Create(
+		session,
+		entities.NewRefreshToken(session.ID, refreshTokenHash, expiresAt),
+	); err != nil {
+		return nil, err
+	}
+
+	return a.issueTokens(session, refreshToken, expiresAt)
+}
+
+// Refresh exchanges a refresh token for a new pair. A token that was already exchanged
+// means it has been stolen, so the whole session is revoked.
+func (a *authService) Refresh(refreshToken string) (*entities.AuthTokens, error) {
+	used, err := a.sessionRepository.FindRefreshToken(a.security.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+	session, err := a.sessionRepository.FindById(used.SessionID)
+	if err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) || !now.Before(used.ExpiresAt) {
+		return nil, customErrors.NewWrongLoginError("Unauthorized")
+	}
+	if used.UsedAt != nil {
+		return nil, a.revokeReusedSession(session)
+	}
+	if err := checkBan(a.banRepository, session.PlayerID); err != nil {
+		return nil, err
+	}
+
+	nextToken, nextTokenHash, err := a.security.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := now.Add(refreshTokenTTL)
+	rotated, err := a.sessionRepository.Rotate(
+		used,
+		entities.NewRefreshToken(session.ID, nextTokenHash, expiresAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, a.revokeReusedSession(session)
+	}
+
+	return a.issueTokens(session, nextToken, expiresAt)
+}
+
+// CheckSession rejects the access tokens of revoked sessions and of banned players
+// and keeps track of the session activity.
+func (a *authService) CheckSession(playerId, sessionId uuid.UUID, ip string) error {
+	session, err := a.findActiveSession(playerId, sessionId)
+	if err != nil {
+		return err
+	}
+	if err := checkBan(a.banRepository, playerId); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastUsedAt) >= sessionTouchInterval {
+		if err := a.sessionRepository.TouchLastUsed(session.ID, ip, now.Add(-sessionTouchInterval)); err != nil {
+			log.Printf("Failed to update session %s usage: %s\n", session.ID, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (a *authService) Sessions(playerId uuid.UUID) ([]entities.Session, error) {
+	return a.sessionRepository.FindActiveByPlayer(playerId, time.Now())
+}
+
+func (a *authService) RevokeSession(playerId, sessionId uuid.UUID) error {
+	return a.sessionRepository.RevokeOwned(playerId, sessionId)
+}
+
+func (a *authService) Logout(playerId, sessionId uuid.UUID) error {
+	if _, err := a.findActiveSession(playerId, sessionId); err != nil {
+		return err
+	}
+
+	return a.sessionRepository.Revoke(sessionId)
+}
+
+func (a *authService) LogoutAll(playerId uuid.UUID) error {
+	return a.sessionRepository.RevokeAll(playerId)
+}
+
+func (a *authService) findActiveSession(playerId, sessionId uuid.UUID) (*entities.Session, error) {
+	session, err := a.sessionRepository.FindById(sessionId)
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, customErrors.NewSessionRevokedError("Unauthorized")
+		}
+
+		return nil, err
+	}
+	if session.PlayerID != playerId || !session.IsActive(time.Now()) {
+		return nil, customErrors.NewSessionRevokedError("Unauthorized")
+	}
+
+	return session, nil
+}
+
+func (a *authService) issueTokens(
+	session *entities.Session,
+	refreshToken string,
+	refreshExpiresAt time.Time,
+) (*entities.AuthTokens, error) {
+	roles, err := a.playerRepository.FindRoles(session.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, accessExpiresAt, err := a.security.GenerateAuthToken(session.PlayerID, session.ID, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.AuthTokens{
+		SessionID:        session.ID,
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func (a *authService) revokeReusedSession(session *entities.Session) error {
+	log.Printf("Refresh token reuse detected, revoking session %s of player %s\n", session.ID, session.PlayerID)
+	if err := a.sessionRepository.Revoke(session.ID); err != nil {
+		return err
+	}
+
+	return customErrors.NewWrongLoginError("Unauthorized")
+}
+
+// checkBan fails with the active ban of the player, the sessions outlive a timed ban.
+func checkBan(banRepository interfaces.RepositoryBan, playerId uuid.UUID) error {
+	ban, err := banRepository.FindActive(playerId, time.Now())
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+
+		return err
+	}
+
+	if ban.ExpiresAt == nil {
+		return customErrors.NewBannedError("your account is banned", nil)
+	}
+
+	return customErrors.NewBannedError("your account is suspended", ban.ExpiresAt)
+}
+
+// isValidEmail accepts a bare address, without a display name.
+func isValidEmail(login string) bool {
+	address, err := mail.ParseAddress(login)
+
+	return err == nil && address.Address == login
+}
+
+// rehashPassword upgrades a legacy or outdated hash; a failure doesn't block the login.
+func (a *authService) rehashPassword(player *entities.Player, password string) {
+	passwordHash, err := a.security.GeneratePasswordHash(password)
+	if err == nil {
+		err = a.playerRepository.UpdatePassword(player.ID, passwordHash)
+	}
+	if err != nil {
+		log.Printf("Failed to rehash password of player %s: %s\n", player.ID, err.Error())
+		return
+	}
+
+	player.Password = passwordHash
+}
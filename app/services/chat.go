@@ -0,0 +1,190 @@
+package services
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"knb/app/repositories"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxChatMessageLength = 500
+
+	defaultChatPageSize = 50
+	maxChatPageSize     = 100
+)
+
+type chatService struct {
+	chatRepository interfaces.RepositoryChat
+	gameRepository interfaces.GameRepository
+	filter         interfaces.ProfanityFilter
+}
+
+func newChatService(
+	chatRepository interfaces.RepositoryChat,
+	gameRepository interfaces.GameRepository,
+	filter interfaces.ProfanityFilter,
+) *chatService {
+	return &chatService{
+		chatRepository: chatRepository,
+		gameRepository: gameRepository,
+		filter:         filter,
+	}
+}
+
+// Messages reads the chat of a game, or of the lobby for no game; after is the last message the player has.
+func (c *chatService) Messages(
+	playerId uuid.UUID,
+	gameId *uuid.UUID,
+	after *uuid.UUID,
+	limit int,
+) ([]entities.ChatMessage, error) {
+	if err := c.checkRoom(playerId, gameId); err != nil {
+		return nil, err
+	}
+
+	var cursor *entities.ChatMessage
+	if after != nil {
+		message, err := c.chatRepository.FindById(*after)
+		if err != nil {
+			return nil, err
+		}
+		if !sameRoom(message.GameID, gameId) {
+			return nil, customErrors.NewBadRequestError("the cursor is a message of another chat")
+		}
+		cursor = message
+	}
+
+	if limit <= 0 {
+		limit = defaultChatPageSize
+	}
+
+	return c.chatRepository.FindPage(gameId, cursor, min(limit, maxChatPageSize))
+}
+
+func (c *chatService) Send(playerId uuid.UUID, gameId *uuid.UUID, body string) (*entities.ChatMessage, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, customErrors.NewBadRequestError("message is empty")
+	}
+	if utf8.RuneCountInString(body) > maxChatMessageLength {
+		return nil, customErrors.NewBadRequestError(
+			fmt.Sprintf("message must not exceed %d characters", maxChatMessageLength),
+		)
+	}
+	if err := c.checkRoom(playerId, gameId); err != nil {
+		return nil, err
+	}
+
+	message := entities.NewChatMessage(gameId, playerId, c.filter.Clean(body))
+	if err := c.chatRepository.Create(message); err != nil {
+		return nil, err
+	}
+
+	return c.chatRepository.FindById(message.ID)
+}
+
+// Report is for the messages the player can read and did not write.
+func (c *chatService) Report(playerId, messageId uuid.UUID, reason string) (*entities.ChatReport, error) {
+	reason, err := auditReason(reason, false)
+	if err != nil {
+		return nil, err
+	}
+	message, err := c.chatRepository.FindById(messageId)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkRoom(playerId, message.GameID); err != nil {
+		return nil, err
+	}
+	if message.PlayerID == playerId {
+		return nil, customErrors.NewBadRequestError("you can't report your own message")
+	}
+
+	report := entities.NewChatReport(messageId, playerId, reason)
+	created, err := c.chatRepository.CreateReport(report)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, customErrors.NewUniqueViolationError("you have already reported this message")
+	}
+	report.Message = *message
+
+	return report, nil
+}
+
+func (c *chatService) Reports(limit int) ([]entities.ChatReport, error) {
+	return c.chatRepository.FindOpenReports(pageSize(limit))
+}
+
+// Resolve settles all the reports of the message, hiding it when the moderator finds it abusive.
+func (c *chatService) Resolve(actorId, reportId uuid.UUID, hide bool, reason string) (*entities.ChatReport, error) {
+	reason, err := auditReason(reason, false)
+	if err != nil {
+		return nil, err
+	}
+	report, err := c.chatRepository.FindReport(reportId)
+	if err != nil {
+		return nil, err
+	}
+	if report.ResolvedAt != nil {
+		return nil, customErrors.NewBadRequestError("the report is already resolved")
+	}
+
+	audit := entities.NewAuditEntry(
+		&actorId,
+		dictionary.AuditActionChatResolve,
+		dictionary.AuditTargetChatMessage,
+		report.MessageID,
+		reason,
+		map[string]interface{}{
+			"author_id": report.Message.PlayerID,
+			"hidden":    hide,
+		},
+	)
+	resolved, err := c.chatRepository.Resolve(report.MessageID, actorId, hide, time.Now(), audit)
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, customErrors.NewBadRequestError("the report is already resolved")
+	}
+
+	return c.chatRepository.FindReport(reportId)
+}
+
+// checkRoom lets only the players of a game into its chat, the lobby is open to everybody.
+func (c *chatService) checkRoom(playerId uuid.UUID, gameId *uuid.UUID) error {
+	if gameId == nil {
+		return nil
+	}
+
+	game, err := c.gameRepository.FindById(*gameId)
+	if err != nil {
+		if err.Error() == repositories.RecordNotFoundError {
+			return customErrors.NewNotFoundError(fmt.Sprintf("game with id %s not found", *gameId))
+		}
+
+		return err
+	}
+	if !isParticipant(game, playerId) {
+		return customErrors.NewForbiddenError("you can't participate in this game")
+	}
+
+	return nil
+}
+
+func sameRoom(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"time"
+)
+
+type friendService struct {
+	friendRepository interfaces.RepositoryFriend
+	playerRepository interfaces.RepositoryPlayer
+	gameRepository   interfaces.GameRepository
+	presence         interfaces.ServicePresence
+}
+
+func newFriendService(
+	friendRepository interfaces.RepositoryFriend,
+	playerRepository interfaces.RepositoryPlayer,
+	gameRepository interfaces.GameRepository,
+	presence interfaces.ServicePresence,
+) *friendService {
+	return &friendService{
+		friendRepository: friendRepository,
+		playerRepository: playerRepository,
+		gameRepository:   gameRepository,
+		presence:         presence,
+	}
+}
+
+func (f *friendService) Friends(playerId uuid.UUID) ([]entities.Friend, error) {
+	friendships, err := f.friendRepository.FindByPlayer(playerId, dictionary.FriendshipStatusAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	friendIds := make([]uuid.UUID, 0, len(friendships))
+	for index := range friendships {
+		friendIds = append(friendIds, friendships[index].Other(playerId).ID)
+	}
+	presences, err := f.presence.Find(friendIds)
+	if err != nil {
+		return nil, err
+	}
+
+	friends := make([]entities.Friend, 0, len(friendships))
+	for index := range friendships {
+		friendship := &friendships[index]
+		friend := entities.Friend{Player: friendship.Other(playerId), Since: friendship.CreatedAt}
+		if friendship.AcceptedAt != nil {
+			friend.Since = *friendship.AcceptedAt
+		}
+		friend.Presence = presences[friend.Player.ID]
+		friends = append(friends, friend)
+	}
+
+	return friends, nil
+}
+
+func (f *friendService) Requests(playerId uuid.UUID) ([]entities.Friendship, error) {
+	return f.friendRepository.FindByPlayer(playerId, dictionary.FriendshipStatusPending)
+}
+
+// SendRequest accepts the request of the other player instead when they already sent one.
+func (f *friendService) SendRequest(playerId, otherId uuid.UUID) (*entities.Friendship, error) {
+	if err := f.checkOther(playerId, otherId); err != nil {
+		return nil, err
+	}
+	if err := f.checkNotBlocked(playerId, otherId); err != nil {
+		return nil, err
+	}
+
+	friendship, err := f.friendRepository.Find(playerId, otherId)
+	if err == nil {
+		switch {
+		case friendship.Status == dictionary.FriendshipStatusAccepted:
+			return nil, customErrors.NewBadRequestError("you are already friends")
+		case friendship.RequesterID == playerId:
+			return nil, customErrors.NewBadRequestError("the friend request is already sent")
+		}
+
+		return f.AcceptRequest(playerId, otherId)
+	}
+	var notFoundErr *customErrors.NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		return nil, err
+	}
+
+	friendship = entities.NewFriendship(playerId, otherId)
+	if err := f.friendRepository.Create(friendship); err != nil {
+		return nil, err
+	}
+
+	return friendship, nil
+}
+
+func (f *friendService) AcceptRequest(playerId, requesterId uuid.UUID) (*entities.Friendship, error) {
+	accepted, err := f.friendRepository.Accept(requesterId, playerId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, customErrors.NewNotFoundError("the friend request was not found")
+	}
+
+	return f.friendRepository.Find(playerId, requesterId)
+}
+
+// DeleteRequest declines a request sent to the player or takes back one they sent.
+func (f *friendService) DeleteRequest(playerId, otherId uuid.UUID) error {
+	deleted, err := f.friendRepository.Delete(playerId, otherId, dictionary.FriendshipStatusPending)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return customErrors.NewNotFoundError("the friend request was not found")
+	}
+
+	return nil
+}
+
+func (f *friendService) Remove(playerId, friendId uuid.UUID) error {
+	deleted, err := f.friendRepository.Delete(playerId, friendId, dictionary.FriendshipStatusAccepted)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return customErrors.NewNotFoundError("the player is not your friend")
+	}
+
+	return nil
+}
+
+func (f *friendService) Blocks(playerId uuid.UUID) ([]entities.Block, error) {
+	return f.friendRepository.FindBlocks(playerId)
+}
+
+// Block ends the friendship with the player and takes them out of the games of the blocker
+// that have not started yet, blocking twice is fine.
+func (f *friendService) Block(playerId, blockedId uuid.UUID) error {
+	if playerId == blockedId {
+		return customErrors.NewBadRequestError("you can't block yourself")
+	}
+	if _, err := f.playerRepository.FindById(blockedId); err != nil {
+		return err
+	}
+
+	if err := f.friendRepository.Block(&entities.Block{PlayerID: playerId, BlockedID: blockedId}); err != nil {
+		return err
+	}
+	if _, err := f.gameRepository.RemovePlayerFromGamesWith(blockedId, playerId); err != nil {
+		return err
+	}
+	f.presence.LeftGames(blockedId)
+
+	return nil
+}
+
+func (f *friendService) Unblock(playerId, blockedId uuid.UUID) error {
+	unblocked, err := f.friendRepository.Unblock(playerId, blockedId)
+	if err != nil {
+		return err
+	}
+	if !unblocked {
+		return customErrors.NewNotFoundError("the player is not blocked")
+	}
+
+	return nil
+}
+
+func (f *friendService) checkOther(playerId, otherId uuid.UUID) error {
+	if playerId == otherId {
+		return customErrors.NewBadRequestError("you can't be friends with yourself")
+	}
+	other, err := f.playerRepository.FindById(otherId)
+	if err != nil {
+		return err
+	}
+	if other.IsBot {
+		return customErrors.NewBadRequestError("bots can't have friends")
+	}
+
+	return nil
+}
+
+func (f *friendService) checkNotBlocked(playerId, otherId uuid.UUID) error {
+	blocked, err := f.friendRepository.IsBlocked([]uuid.UUID{playerId}, otherId)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return customErrors.NewBadRequestError("unblock the player first")
+	}
+
+	blocked, err = f.friendRepository.IsBlocked([]uuid.UUID{otherId}, playerId)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return customErrors.NewForbiddenError("the player doesn't accept your friend requests")
+	}
+
+	return nil
+}
@@ -4,33 +4,91 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"knb/app/bots"
+	"knb/app/dictionary"
 	"knb/app/entities"
 	customErrors "knb/app/errors"
 	"knb/app/interfaces"
 	"knb/app/repositories"
+	"knb/app/rules"
+	"log"
+	"math/rand/v2"
 )
 
+const maxBotsPerRequest = 8
+
 type gameService struct {
 	gameRepository   interfaces.GameRepository
 	playerRepository interfaces.RepositoryPlayer
+	friendRepository interfaces.RepositoryFriend
+	presence         interfaces.ServicePresence
+	listeners        []interfaces.GameFinishedListener
 }
 
 func newGameService(
 	gameRepository interfaces.GameRepository,
 	playerRepository interfaces.RepositoryPlayer,
+	friendRepository interfaces.RepositoryFriend,
+	presence interfaces.ServicePresence,
 ) *gameService {
 	return &gameService{
-		gameRepository,
-		playerRepository,
+		gameRepository:   gameRepository,
+		playerRepository: playerRepository,
+		friendRepository: friendRepository,
+		presence:         presence,
 	}
 }
 
+func (g *gameService) AddFinishedListener(listener interfaces.GameFinishedListener) {
+	g.listeners = append(g.listeners, listener)
+}
+
 func (g *gameService) NewGameRequest(playerOwnerId uuid.UUID) (*entities.Game, error) {
-	if err := g.checkUser(playerOwnerId); err != nil {
+	owner, err := g.getPlayer(playerOwnerId)
+	if err != nil {
 		return nil, err
 	}
 
-	return g.gameRepository.CreateGame(playerOwnerId)
+	game, err := g.gameRepository.CreateGame(playerOwnerId)
+	if err != nil {
+		return nil, err
+	}
+	if owner.IsBot {
+		if err := g.gameRepository.FlagBotOwned(game); err != nil {
+			return nil, err
+		}
+	}
+	g.enterGame(owner, game.ID)
+
+	return game, nil
+}
+
+func (g *gameService) NewPracticeGame(playerId uuid.UUID, strategy dictionary.BotStrategy) (*entities.Game, error) {
+	player, err := g.getPlayer(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.checkStrategy(strategy); err != nil {
+		return nil, err
+	}
+
+	bot, err := g.playerRepository.CreateBot(strategy)
+	if err != nil {
+		return nil, err
+	}
+	game, err := g.gameRepository.CreateGame(playerId, bot.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.gameRepository.StartGame(game); err != nil {
+		return nil, err
+	}
+	g.enterGame(player, game.ID)
+	if err := g.advance(game.ID); err != nil {
+		return nil, err
+	}
+
+	return g.FindGame(game.ID)
 }
 
 func (g *gameService) FindGame(gameId uuid.UUID) (*entities.Game, error) {
@@ -38,43 +96,459 @@ func (g *gameService) FindGame(gameId uuid.UUID) (*entities.Game, error) {
 }
 
 func (g *gameService) JoinGame(playerId uuid.UUID, gameId uuid.UUID) (*entities.Game, error) {
-	if err := g.checkUser(playerId); err != nil {
+	player, err := g.getPlayer(playerId)
+	if err != nil {
 		return nil, err
 	}
 	game, err := g.getGame(gameId)
 	if err != nil {
 		return nil, err
 	}
-	for _, player := range game.Players {
-		if player.ID == playerId {
+	for _, participant := range game.Players {
+		if participant.ID == playerId {
 			return nil, customErrors.NewBadRequestError("you already joined to this game")
 		}
 	}
+	switch game.Status {
+	case dictionary.GameStatusStarted:
+		return nil, customErrors.NewBadRequestError("the game has already started")
+	case dictionary.GameStatusFinished:
+		return nil, customErrors.NewBadRequestError("the game already over")
+	}
+	if err := g.checkNotBlocked(game, playerId); err != nil {
+		return nil, err
+	}
+	if len(game.Prizes) > 0 {
+		canPlayStaked, err := g.canPlayStaked(player)
+		if err != nil {
+			return nil, err
+		}
+		if !canPlayStaked {
+			return nil, customErrors.NewForbiddenError("verify your email to play games with prizes")
+		}
+	}
 
 	if err := g.gameRepository.AddPlayers(game, []uuid.UUID{playerId}); err != nil {
 		return nil, err
 	}
+	g.enterGame(player, gameId)
+
+	return g.FindGame(gameId)
+}
+
+func (g *gameService) AddBots(
+	playerId uuid.UUID,
+	gameId uuid.UUID,
+	count int,
+	strategy dictionary.BotStrategy,
+) (*entities.Game, error) {
+	if err := g.checkUser(playerId); err != nil {
+		return nil, err
+	}
+	if err := g.checkStrategy(strategy); err != nil {
+		return nil, err
+	}
+	if count < 1 || count > maxBotsPerRequest {
+		return nil, customErrors.NewBadRequestError(
+			fmt.Sprintf("bots count must be between 1 and %d", maxBotsPerRequest),
+		)
+	}
+	game, err := g.getGame(gameId)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status != dictionary.GameStatusWaiting {
+		return nil, customErrors.NewBadRequestError("bots can join only a waiting game")
+	}
+	if !isParticipant(game, playerId) {
+		return nil, customErrors.NewForbiddenError("you can't participate in this game")
+	}
+	if err := g.checkNotBlocked(game, playerId); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < count; i++ {
+		bot, err := g.playerRepository.CreateBot(strategy)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.gameRepository.AddPlayers(game, []uuid.UUID{bot.ID}); err != nil {
+			return nil, err
+		}
+		if err := g.gameRepository.SetPlayerReady(game.ID, bot.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.FindGame(gameId)
+}
+
+func (g *gameService) StartGame(playerId uuid.UUID, gameId uuid.UUID) error {
+	player, err := g.getPlayer(playerId)
+	if err != nil {
+		return err
+	}
+	game, err := g.getGame(gameId)
+	if err != nil {
+		return err
+	}
+	switch game.Status {
+	case dictionary.GameStatusPlanned:
+		return customErrors.NewBadRequestError("the game can't start yet")
+	case dictionary.GameStatusStarted:
+		return customErrors.NewBadRequestError("the game has already started")
+	case dictionary.GameStatusFinished:
+		return customErrors.NewBadRequestError("the game already over")
+	}
+	if !isParticipant(game, playerId) {
+		return customErrors.NewForbiddenError("you can't participate in this game")
+	}
+	if len(game.Players) < rules.MinPlayers {
+		return customErrors.NewBadRequestError("not enough players")
+	}
+
+	if err := g.gameRepository.SetPlayerReady(game.ID, playerId); err != nil {
+		return err
+	}
+	g.enterGame(player, game.ID)
+
+	gamePlayers, err := g.gameRepository.FindGamePlayers(game.ID)
+	if err != nil {
+		return err
+	}
+	for _, gamePlayer := range gamePlayers {
+		if !gamePlayer.Ready {
+			return nil
+		}
+	}
+
+	if err := g.gameRepository.StartGame(game); err != nil {
+		return err
+	}
+
+	return g.advance(game.ID)
+}
+
+func (g *gameService) Move(playerId uuid.UUID, gameId uuid.UUID, throw dictionary.Throw) (*entities.Game, error) {
+	player, err := g.getPlayer(playerId)
+	if err != nil {
+		return nil, err
+	}
+	game, err := g.getGame(gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.makeMove(game, playerId, throw); err != nil {
+		return nil, err
+	}
+	g.enterGame(player, gameId)
+	if err := g.advance(game.ID); err != nil {
+		return nil, err
+	}
+
+	return g.FindGame(gameId)
+}
+
+// ForceFinish ends the game now. A started game may be given a winner, the other active players share
+// the next place; without a winner the active players get no place and no prize.
+// The audit entry, when given, is written together with the finish.
+func (g *gameService) ForceFinish(
+	gameId uuid.UUID,
+	winnerId *uuid.UUID,
+	audit *entities.AuditEntry,
+) (*entities.Game, error) {
+	game, err := g.getGame(gameId)
+	if err != nil {
+		return nil, err
+	}
+	if game.Status == dictionary.GameStatusFinished {
+		return nil, customErrors.NewBadRequestError("the game already over")
+	}
+	if winnerId != nil && game.Status != dictionary.GameStatusStarted {
+		return nil, customErrors.NewBadRequestError("only a started game can have a winner")
+	}
+
+	if winnerId == nil {
+		if err := g.gameRepository.Abandon(game, nil, audit); err != nil {
+			return nil, err
+		}
+	} else {
+		results, err := forcedResults(game, *winnerId)
+		if err != nil {
+			return nil, err
+		}
+		completed, err := g.gameRepository.CompleteRound(
+			game, results, rules.Payouts(game.Prizes, results), true, audit,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !completed {
+			return nil, customErrors.NewBadRequestError("the game has moved on, try again")
+		}
+	}
+	g.notifyFinished(game.ID)
+
+	return g.FindGame(gameId)
+}
+
+// Cancel voids the game and takes back its prizes, returning the refunds.
+func (g *gameService) Cancel(
+	gameId uuid.UUID,
+	actorId *uuid.UUID,
+	reason string,
+	audit *entities.AuditEntry,
+) (*entities.Game, []entities.PointsTransaction, error) {
+	game, err := g.getGame(gameId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if game.CancelledAt != nil {
+		return nil, nil, customErrors.NewBadRequestError("the game is already cancelled")
+	}
+
+	refunds, cancelled, err := g.gameRepository.Cancel(game, actorId, reason, audit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cancelled {
+		return nil, nil, customErrors.NewBadRequestError("the game is already cancelled")
+	}
+	// A game that was already over is told again, so that its players' stats drop it.
+	g.notifyFinished(game.ID)
+
+	game, err = g.FindGame(gameId)
+
+	return game, refunds, err
+}
+
+// UpdatePrizes replaces the prize table, an empty one makes the game unstaked.
+func (g *gameService) UpdatePrizes(
+	gameId uuid.UUID,
+	prizes []entities.GamePrize,
+	audit *entities.AuditEntry,
+) (*entities.Game, error) {
+	places := make(map[uint8]bool, len(prizes))
+	for index, prize := range prizes {
+		if prize.Place == 0 || places[prize.Place] {
+			return nil, customErrors.NewBadRequestError("prize places must be unique and start from 1")
+		}
+		if prize.Prize == 0 {
+			return nil, customErrors.NewBadRequestError("a prize must be positive")
+		}
+		places[prize.Place] = true
+		prizes[index].GameID = gameId
+	}
+	game, err := g.getGame(gameId)
+	if err != nil {
+		return nil, err
+	}
+	// The players who joined while the game had no prizes have not been asked to verify their email yet.
+	if len(prizes) > 0 && game.Status != dictionary.GameStatusStarted && game.Status != dictionary.GameStatusFinished {
+		for index := range game.Players {
+			canPlayStaked, err := g.canPlayStaked(&game.Players[index])
+			if err != nil {
+				return nil, err
+			}
+			if !canPlayStaked {
+				return nil, customErrors.NewBadRequestError(fmt.Sprintf(
+					"player %s has not verified their email and can't play for prizes", game.Players[index].ID,
+				))
+			}
+		}
+	}
+
+	replaced, err := g.gameRepository.ReplacePrizes(gameId, prizes, audit)
+	if err != nil {
+		return nil, err
+	}
+	if !replaced {
+		return nil, customErrors.NewBadRequestError("prizes can be changed only before the game starts")
+	}
 
 	return g.FindGame(gameId)
 }
 
-func (g *gameService) StartGame(gameId uuid.UUID) error {
-	//TODO implement me
-	panic("implement me")
+// makeMove is the single entry point for a throw, used for humans and bots alike.
+func (g *gameService) makeMove(game *entities.Game, playerId uuid.UUID, throw dictionary.Throw) error {
+	if !rules.IsValidThrow(throw) {
+		return customErrors.NewBadRequestError("throw is invalid")
+	}
+	switch game.Status {
+	case dictionary.GameStatusFinished:
+		return customErrors.NewBadRequestError("the game already over")
+	case dictionary.GameStatusStarted:
+	default:
+		return customErrors.NewBadRequestError("the game is not started yet")
+	}
+	if !isParticipant(game, playerId) {
+		return customErrors.NewForbiddenError("you can't participate in this game")
+	}
+	for _, result := range game.Result {
+		if result.PlayerID == playerId {
+			return customErrors.NewBadRequestError("you are out of this game")
+		}
+	}
+	for _, move := range game.Moves {
+		if move.Round == game.Round && move.PlayerID == playerId {
+			return customErrors.NewBadRequestError("you already made a move in this round")
+		}
+	}
+
+	move := entities.NewGameMove(game.ID, playerId, game.Round, throw)
+	if err := g.gameRepository.CreateMove(move); err != nil {
+		return err
+	}
+	game.Moves = append(game.Moves, *move)
+
+	return nil
+}
+
+// advance lets the bots of the game move and settles every round in which all active players have moved.
+func (g *gameService) advance(gameId uuid.UUID) error {
+	for {
+		game, err := g.getGame(gameId)
+		if err != nil {
+			return err
+		}
+		if game.Status != dictionary.GameStatusStarted {
+			return nil
+		}
+
+		if err := g.playBots(game); err != nil {
+			return err
+		}
+
+		active := activePlayers(game)
+		moves := roundMoves(game)
+		if len(moves) < len(active) {
+			return nil
+		}
+
+		activeIds := make([]uuid.UUID, 0, len(active))
+		for _, player := range active {
+			activeIds = append(activeIds, player.ID)
+		}
+
+		outcome := rules.CompleteRound(game.ID, activeIds, moves, game.Round)
+		completed, err := g.gameRepository.CompleteRound(
+			game,
+			outcome.Results,
+			rules.Payouts(game.Prizes, outcome.Results),
+			outcome.Finished,
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+		if !completed {
+			return nil
+		}
+		if outcome.Finished {
+			g.notifyFinished(game.ID)
+			return nil
+		}
+	}
+}
+
+func (g *gameService) notifyFinished(gameId uuid.UUID) {
+	if len(g.listeners) == 0 {
+		return
+	}
+
+	game, err := g.gameRepository.FindById(gameId)
+	if err != nil {
+		log.Printf("Failed to load finished game %s: %s\n", gameId, err.Error())
+		return
+	}
+
+	for _, listener := range g.listeners {
+		listener.GameFinished(game)
+	}
+}
+
+func (g *gameService) playBots(game *entities.Game) error {
+	moved := make(map[uuid.UUID]bool)
+	for _, move := range roundMoves(game) {
+		moved[move.PlayerID] = true
+	}
+
+	for _, player := range activePlayers(game) {
+		if !player.IsBuiltInBot() || moved[player.ID] {
+			continue
+		}
+
+		strategy, err := g.strategy(player.BotStrategy)
+		if err != nil {
+			return err
+		}
+		if err := g.makeMove(game, player.ID, strategy.Next(player.ID, game.Moves)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *gameService) strategy(name dictionary.BotStrategy) (interfaces.BotStrategy, error) {
+	return bots.NewStrategy(name, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+}
+
+func (g *gameService) checkStrategy(name dictionary.BotStrategy) error {
+	if _, err := g.strategy(name); err != nil {
+		return customErrors.NewBadRequestError("bot strategy is invalid")
+	}
+
+	return nil
 }
 
 func (g *gameService) checkUser(playerId uuid.UUID) error {
-	_, err := g.playerRepository.FindById(playerId)
+	_, err := g.getPlayer(playerId)
+
+	return err
+}
+
+// enterGame records the game a person is in, the bots are not around to be invited.
+func (g *gameService) enterGame(player *entities.Player, gameId uuid.UUID) {
+	if player.IsBot {
+		return
+	}
+
+	g.presence.EnterGame(player.ID, gameId)
+}
+
+// accountHolder returns the person behind the player, the owner of a bot that plays through an api key.
+func (g *gameService) accountHolder(player *entities.Player) (*entities.Player, error) {
+	if player.OwnerID == nil {
+		return player, nil
+	}
+
+	return g.getPlayer(*player.OwnerID)
+}
+
+// canPlayStaked asks the owner of a bot of an api key, it is the owner who must be verified.
+func (g *gameService) canPlayStaked(player *entities.Player) (bool, error) {
+	holder, err := g.accountHolder(player)
+	if err != nil {
+		return false, err
+	}
+
+	return holder.CanPlayStaked(), nil
+}
+
+func (g *gameService) getPlayer(playerId uuid.UUID) (*entities.Player, error) {
+	player, err := g.playerRepository.FindById(playerId)
 	if err != nil {
 		var notFoundErr *customErrors.NotFoundError
 		if errors.As(err, &notFoundErr) {
-			return customErrors.NewWrongLoginError("Unauthorized")
+			return nil, customErrors.NewWrongLoginError("Unauthorized")
 		}
 
-		return err
+		return nil, err
 	}
 
-	return nil
+	return player, nil
 }
 
 func (g *gameService) getGame(gameId uuid.UUID) (*entities.Game, error) {
@@ -89,3 +563,88 @@ func (g *gameService) getGame(gameId uuid.UUID) (*entities.Game, error) {
 
 	return game, nil
 }
+
+func isParticipant(game *entities.Game, playerId uuid.UUID) bool {
+	for _, player := range game.Players {
+		if player.ID == playerId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func activePlayers(game *entities.Game) []entities.Player {
+	finished := make(map[uuid.UUID]bool, len(game.Result))
+	for _, result := range game.Result {
+		finished[result.PlayerID] = true
+	}
+
+	active := make([]entities.Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		if !finished[player.ID] {
+			active = append(active, player)
+		}
+	}
+
+	return active
+}
+
+func forcedResults(game *entities.Game, winnerId uuid.UUID) ([]entities.GameResult, error) {
+	active := activePlayers(game)
+	results := make([]entities.GameResult, 0, len(active))
+	for _, player := range active {
+		if player.ID != winnerId {
+			results = append(results, entities.GameResult{GameID: game.ID, PlayerID: player.ID, Place: 2})
+		}
+	}
+	if len(results) == len(active) {
+		return nil, customErrors.NewBadRequestError("the winner must be an active player of the game")
+	}
+
+	return append(results, entities.GameResult{GameID: game.ID, PlayerID: winnerId, Place: 1}), nil
+}
+
+func roundMoves(game *entities.Game) []entities.GameMove {
+	moves := make([]entities.GameMove, 0, len(game.Players))
+	for _, move := range game.Moves {
+		if move.Round == game.Round {
+			moves = append(moves, move)
+		}
+	}
+
+	return moves
+}
+
+// checkNotBlocked keeps a player out of the games of the players who blocked them,
+// a bot that plays through an api key stands for its owner on either side.
+func (g *gameService) checkNotBlocked(game *entities.Game, playerId uuid.UUID) error {
+	player, err := g.getPlayer(playerId)
+	if err != nil {
+		return err
+	}
+
+	playerIds := make([]uuid.UUID, 0, len(game.Players))
+	for _, participant := range game.Players {
+		playerIds = append(playerIds, participant.ID)
+		if participant.OwnerID != nil {
+			playerIds = append(playerIds, *participant.OwnerID)
+		}
+	}
+	blockedIds := []uuid.UUID{player.ID}
+	if player.OwnerID != nil {
+		blockedIds = append(blockedIds, *player.OwnerID)
+	}
+
+	for _, blockedId := range blockedIds {
+		blocked, err := g.friendRepository.IsBlocked(playerIds, blockedId)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return customErrors.NewForbiddenError("you can't join this game")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"log"
+	"slices"
+	"time"
+)
+
+const (
+	defaultLeaderboardSize = 10
+	maxLeaderboardSize     = 100
+	// leaderboardMinGames keeps a lucky first game off the top of the win rate board.
+	leaderboardMinGames = 5
+)
+
+// leaderboardEndOfTime closes the all-time period.
+var leaderboardEndOfTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+type leaderboardService struct {
+	leaderboardRepository interfaces.RepositoryLeaderboard
+	playerRepository      interfaces.RepositoryPlayer
+}
+
+func newLeaderboardService(
+	leaderboardRepository interfaces.RepositoryLeaderboard,
+	playerRepository interfaces.RepositoryPlayer,
+) *leaderboardService {
+	return &leaderboardService{
+		leaderboardRepository: leaderboardRepository,
+		playerRepository:      playerRepository,
+	}
+}
+
+// Board ranks the current period of the window, the players with the same score share the rank.
+func (l *leaderboardService) Board(
+	playerId uuid.UUID,
+	board dictionary.LeaderboardBoard,
+	window dictionary.LeaderboardWindow,
+	limit int,
+) (*entities.Leaderboard, error) {
+	if board == "" {
+		board = dictionary.LeaderboardBoardPoints
+	}
+	if !slices.Contains(dictionary.LeaderboardBoards, board) {
+		return nil, customErrors.NewBadRequestError("board is invalid")
+	}
+	if window == "" {
+		window = dictionary.LeaderboardWindowAllTime
+	}
+	if !slices.Contains(dictionary.LeaderboardWindows, window) {
+		return nil, customErrors.NewBadRequestError("window is invalid")
+	}
+	if limit <= 0 {
+		limit = defaultLeaderboardSize
+	}
+	limit = min(limit, maxLeaderboardSize)
+
+	periodStart, _ := leaderboardPeriod(window, time.Now())
+	minGames := leaderboardMinimum(board)
+	top, err := l.leaderboardRepository.FindTop(board, window, periodStart, minGames, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	leaderboard := &entities.Leaderboard{
+		Board:       board,
+		Window:      window,
+		PeriodStart: periodStart,
+		Entries:     rankEntries(board, top),
+	}
+	for _, entry := range leaderboard.Entries {
+		if entry.Entry.PlayerID == playerId {
+			leaderboard.Own = entry
+		}
+	}
+	if leaderboard.Own.Rank == 0 {
+		leaderboard.Own, err = l.own(playerId, board, window, periodStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return leaderboard, nil
+}
+
+// GameFinished refreshes the players of the game in the periods it ended in and in the current ones,
+// a cancelled game leaves the periods it counted in and takes its refunds into the current ones.
+func (l *leaderboardService) GameFinished(game *entities.Game) {
+	now := time.Now()
+	times := []time.Time{now}
+	if !game.FinishedAt.IsZero() {
+		times = append(times, game.FinishedAt)
+	}
+
+	for _, player := range game.Players {
+		if player.IsBot {
+			continue
+		}
+		if err := l.refresh(player.ID, times...); err != nil {
+			log.Printf("Failed to update leaderboard of player %s: %s\n", player.ID, err.Error())
+		}
+	}
+}
+
+func (l *leaderboardService) PointsChanged(playerId uuid.UUID) {
+	if err := l.refresh(playerId, time.Now()); err != nil {
+		log.Printf("Failed to update leaderboard of player %s: %s\n", playerId, err.Error())
+	}
+}
+
+// Backfill enters the players who played before the leaderboards were kept.
+func (l *leaderboardService) Backfill() error {
+	playerIds, err := l.leaderboardRepository.FindUnranked()
+	if err != nil {
+		return err
+	}
+
+	for _, playerId := range playerIds {
+		if err := l.refresh(playerId, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *leaderboardService) own(
+	playerId uuid.UUID,
+	board dictionary.LeaderboardBoard,
+	window dictionary.LeaderboardWindow,
+	periodStart time.Time,
+) (entities.RankedEntry, error) {
+	entry, err := l.leaderboardRepository.Find(window, periodStart, playerId)
+	var notFoundErr *customErrors.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		player, err := l.playerRepository.FindById(playerId)
+		if err != nil {
+			return entities.RankedEntry{}, err
+		}
+		entry = entities.NewLeaderboardEntry(window, periodStart, playerId, 0, 0, 0)
+		entry.Player = *player
+
+		return entities.RankedEntry{Entry: *entry}, nil
+	}
+	if err != nil {
+		return entities.RankedEntry{}, err
+	}
+
+	minGames := leaderboardMinimum(board)
+	if entry.GamesPlayed < minGames || (entry.GamesPlayed == 0 && entry.Points == 0) {
+		return entities.RankedEntry{Entry: *entry}, nil
+	}
+	ahead, err := l.leaderboardRepository.CountAhead(board, entry, minGames)
+	if err != nil {
+		return entities.RankedEntry{}, err
+	}
+
+	return entities.RankedEntry{Rank: uint(ahead) + 1, Entry: *entry}, nil
+}
+
+// refresh recounts the entries of the player in every window for the periods around the given times.
+func (l *leaderboardService) refresh(playerId uuid.UUID, times ...time.Time) error {
+	for _, window := range dictionary.LeaderboardWindows {
+		refreshed := make(map[int64]bool, len(times))
+		for _, at := range times {
+			from, to := leaderboardPeriod(window, at)
+			if refreshed[from.Unix()] {
+				continue
+			}
+			refreshed[from.Unix()] = true
+
+			entry, err := l.leaderboardRepository.Tally(playerId, window, from, to)
+			if err != nil {
+				return err
+			}
+			if err := l.leaderboardRepository.Save(entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// leaderboardPeriod returns the bounds of the period of the window the time falls in, weeks start on Monday.
+func leaderboardPeriod(window dictionary.LeaderboardWindow, at time.Time) (time.Time, time.Time) {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	switch window {
+	case dictionary.LeaderboardWindowMonthly:
+		start := day.AddDate(0, 0, 1-day.Day())
+		return start, start.AddDate(0, 1, 0)
+	case dictionary.LeaderboardWindowWeekly:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7)
+	}
+
+	return time.Time{}, leaderboardEndOfTime
+}
+
+// rankEntries ranks the entries sorted by the score of the board, the entries with the same score share the rank.
+func rankEntries(board dictionary.LeaderboardBoard, top []entities.LeaderboardEntry) []entities.RankedEntry {
+	ranked := make([]entities.RankedEntry, 0, len(top))
+	for index, entry := range top {
+		rank := uint(index + 1)
+		if index > 0 && leaderboardScore(board, &entry) == leaderboardScore(board, &top[index-1]) {
+			rank = ranked[index-1].Rank
+		}
+		ranked = append(ranked, entities.RankedEntry{Rank: rank, Entry: entry})
+	}
+
+	return ranked
+}
+
+func leaderboardMinimum(board dictionary.LeaderboardBoard) uint {
+	if board == dictionary.LeaderboardBoardWinRate {
+		return leaderboardMinGames
+	}
+
+	return 0
+}
+
+func leaderboardScore(board dictionary.LeaderboardBoard, entry *entities.LeaderboardEntry) float64 {
+	switch board {
+	case dictionary.LeaderboardBoardWins:
+		return float64(entry.GamesWon)
+	case dictionary.LeaderboardBoardWinRate:
+		return entry.WinRate
+	}
+
+	return float64(entry.Points)
+}
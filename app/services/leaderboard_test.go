@@ -0,0 +1,131 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"testing"
+	"time"
+)
+
+type leaderboardPeriodTestCase struct {
+	window        dictionary.LeaderboardWindow
+	at            time.Time
+	expectedStart time.Time
+	expectedEnd   time.Time
+	name          string
+}
+
+type rankEntriesTestCase struct {
+	board    dictionary.LeaderboardBoard
+	top      []entities.LeaderboardEntry
+	expected []uint
+	name     string
+}
+
+func TestLeaderboardPeriod(t *testing.T) {
+	testCases := []leaderboardPeriodTestCase{
+		{
+			window:        dictionary.LeaderboardWindowMonthly,
+			at:            time.Date(2024, time.February, 29, 23, 59, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			name:          "monthly",
+		},
+		{
+			window:        dictionary.LeaderboardWindowMonthly,
+			at:            time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			name:          "monthly over the new year",
+		},
+		{
+			window:        dictionary.LeaderboardWindowWeekly,
+			at:            time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC),
+			name:          "weekly from Monday",
+		},
+		{
+			window:        dictionary.LeaderboardWindowWeekly,
+			at:            time.Date(2024, time.May, 19, 23, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC),
+			name:          "Sunday ends the week",
+		},
+		{
+			window:        dictionary.LeaderboardWindowWeekly,
+			at:            time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC),
+			name:          "Monday starts the week",
+		},
+		{
+			window:      dictionary.LeaderboardWindowAllTime,
+			at:          time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC),
+			expectedEnd: leaderboardEndOfTime,
+			name:        "all time",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			start, end := leaderboardPeriod(tCase.window, tCase.at)
+			assert.Equal(tt, tCase.expectedStart, start)
+			assert.Equal(tt, tCase.expectedEnd, end)
+		})
+	}
+}
+
+func TestRankEntries(t *testing.T) {
+	testCases := []rankEntriesTestCase{
+		{
+			board: dictionary.LeaderboardBoardPoints,
+			top: []entities.LeaderboardEntry{
+				{Points: 100},
+				{Points: 10, GamesWon: 3},
+				{Points: 10},
+				{Points: -5},
+			},
+			expected: []uint{1, 2, 2, 4},
+			name:     "the same points share the rank",
+		},
+		{
+			board: dictionary.LeaderboardBoardWins,
+			top: []entities.LeaderboardEntry{
+				{GamesWon: 2, Points: 50},
+				{GamesWon: 2},
+				{GamesWon: 2},
+				{GamesWon: 1, Points: 50},
+			},
+			expected: []uint{1, 1, 1, 4},
+			name:     "the wins are compared on the wins board",
+		},
+		{
+			board: dictionary.LeaderboardBoardWinRate,
+			top: []entities.LeaderboardEntry{
+				{WinRate: 0.8},
+				{WinRate: 0.6, GamesWon: 6},
+				{WinRate: 0.6, GamesWon: 3},
+			},
+			expected: []uint{1, 2, 2},
+			name:     "the win rate is compared on the win rate board",
+		},
+		{
+			board:    dictionary.LeaderboardBoardPoints,
+			expected: []uint{},
+			name:     "no entries",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			ranks := make([]uint, 0, len(tCase.top))
+			for index, entry := range rankEntries(tCase.board, tCase.top) {
+				assert.Equal(tt, tCase.top[index], entry.Entry)
+				ranks = append(ranks, entry.Rank)
+			}
+			assert.Equal(tt, tCase.expected, ranks)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"knb/app/config"
+	"knb/app/entities"
+	"knb/app/interfaces"
+	"strings"
+	"time"
+)
+
+const (
+	// loginFailureWindow is how long a failure counts, a quiet account or IP starts over after it.
+	loginFailureWindow = 24 * time.Hour
+	maxLoginLength     = 255
+
+	loginThrottleAccountPrefix = "account:"
+	loginThrottleIpPrefix      = "ip:"
+)
+
+// loginThrottleService slows down password guessing: after the free attempts an account or an IP
+// is locked out for a period that doubles with every next failure.
+type loginThrottleService struct {
+	throttleRepository interfaces.RepositoryLoginThrottle
+	failureRepository  interfaces.RepositoryLoginFailure
+	config             config.LoginThrottleConfig
+}
+
+func newLoginThrottleService(
+	throttleRepository interfaces.RepositoryLoginThrottle,
+	failureRepository interfaces.RepositoryLoginFailure,
+	config config.LoginThrottleConfig,
+) *loginThrottleService {
+	return &loginThrottleService{throttleRepository, failureRepository, config}
+}
+
+// Check returns how long the account or the IP is still locked out.
+func (l *loginThrottleService) Check(account, ip string) (time.Duration, error) {
+	throttles, err := l.throttleRepository.Find([]string{accountThrottleKey(account), ipThrottleKey(ip)})
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, throttle := range throttles {
+		retryAfter = max(retryAfter, throttle.RetryAfter(now))
+	}
+
+	return retryAfter, nil
+}
+
+// Failed audits the failure and counts it for the account and the IP, it returns the lockout it caused.
+func (l *loginThrottleService) Failed(account string, failure *entities.LoginFailure) (time.Duration, error) {
+	if len(failure.Login) > maxLoginLength {
+		failure.Login = failure.Login[:maxLoginLength]
+	}
+	if len(failure.UserAgent) > maxUserAgentLength {
+		failure.UserAgent = failure.UserAgent[:maxUserAgentLength]
+	}
+	if err := l.failureRepository.Create(failure); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	accountThrottle, err := l.throttleRepository.Fail(
+		accountThrottleKey(account), now, loginFailureWindow,
+		func(failures int) time.Duration {
+			return l.lockout(failures, l.config.AccountFreeAttempts)
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	ipThrottle, err := l.throttleRepository.Fail(
+		ipThrottleKey(failure.IP), now, loginFailureWindow,
+		func(failures int) time.Duration {
+			return l.lockout(failures, l.config.IpFreeAttempts)
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return max(accountThrottle.RetryAfter(now), ipThrottle.RetryAfter(now)), nil
+}
+
+// Succeeded forgets the failures of the account, the ones of the IP keep counting.
+func (l *loginThrottleService) Succeeded(account string) error {
+	return l.throttleRepository.Reset(accountThrottleKey(account))
+}
+
+// lockout is zero for the free attempts and then BaseLockout, doubled for every next failure, up to MaxLockout.
+func (l *loginThrottleService) lockout(failures, freeAttempts int) time.Duration {
+	if freeAttempts <= 0 || failures <= freeAttempts {
+		return 0
+	}
+
+	lockout := l.config.BaseLockout
+	for i := freeAttempts + 1; i < failures && lockout < l.config.MaxLockout; i++ {
+		lockout *= 2
+	}
+
+	return min(lockout, l.config.MaxLockout)
+}
+
+func accountThrottleKey(account string) string {
+	return loginThrottleAccountPrefix + strings.ToLower(strings.TrimSpace(account))
+}
+
+func ipThrottleKey(ip string) string {
+	return loginThrottleIpPrefix + ip
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+const (
+	argon2idPrefix  = "$argon2id$"
+	argon2idMemory  = 64 * 1024
+	argon2idTime    = 3
+	argon2idThreads = 2
+	argon2idSaltLen = 16
+	argon2idKeyLen  = 32
+)
+
+type argon2idParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+// dummyPasswordHash is verified against when the login is unknown, so that the answer
+// takes as long as for a wrong password and does not tell which logins exist.
+const dummyPasswordHash = "$argon2id$v=19$m=65536,t=3,p=2$a25iLWR1bW15LXNhbHQhIQ$ErhyRA5kUQ+GzNdGBe+vbmRcZs3/c36Ckzg0+8cutYw"
+
+var currentArgon2idParams = argon2idParams{
+	memory:  argon2idMemory,
+	time:    argon2idTime,
+	threads: argon2idThreads,
+}
+
+// GeneratePasswordHash hashes the password with Argon2id and a random salt
+// in the PHC string format: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>.
+func (s *securityService) GeneratePasswordHash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	params := currentArgon2idParams
+	key := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, argon2idKeyLen)
+
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		params.memory, params.time, params.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// VerifyPassword checks the password against an Argon2id hash or a legacy SHA-1 one.
+// needsRehash is set when the password matches a hash that is weaker than the current one.
+func (s *securityService) VerifyPassword(password, hash string) (valid, needsRehash bool) {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		legacy := legacyPasswordHash(password)
+		if hash == "" || subtle.ConstantTimeCompare([]byte(legacy), []byte(hash)) != 1 {
+			return false, false
+		}
+
+		return true, true
+	}
+
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return false, false
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(computed, key) != 1 {
+		return false, false
+	}
+
+	return true, params != currentArgon2idParams || len(key) != argon2idKeyLen
+}
+
+func decodeArgon2idHash(hash string) (argon2idParams, []byte, []byte, error) {
+	var params argon2idParams
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errors.New("password hash has invalid format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, errors.New("password hash has unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	if len(key) == 0 {
+		return params, nil, nil, errors.New("password hash has empty key")
+	}
+
+	return params, salt, key, nil
+}
+
+// legacyPasswordHash reproduces the SHA-1 hashes stored before Argon2id: the hex of the
+// static salt followed by the digest. It is used only to verify and upgrade old passwords.
+func legacyPasswordHash(password string) string {
+	hash := sha1.New()
+	hash.Write([]byte(password))
+
+	return fmt.Sprintf("%x", hash.Sum([]byte(legacySalt)))
+}
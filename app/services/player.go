@@ -0,0 +1,327 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"io"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"knb/app/repositories"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	minDisplayNameLength = 3
+	maxDisplayNameLength = 32
+	maxAvatarUrlLength   = 1024
+	MaxAvatarBytes       = 2 << 20
+)
+
+var (
+	localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+	// reservedDisplayNamePrefixes keep the players from passing for the built-in bots.
+	reservedDisplayNamePrefixes = []string{"bot "}
+	avatarExtensions            = map[string]string{
+		"image/png":  ".png",
+		"image/jpeg": ".jpg",
+		"image/gif":  ".gif",
+		"image/webp": ".webp",
+	}
+)
+
+type playerService struct {
+	playerRepository interfaces.RepositoryPlayer
+	gameRepository   interfaces.GameRepository
+	pointsRepository interfaces.RepositoryPoints
+	chatRepository   interfaces.RepositoryChat
+	presence         interfaces.ServicePresence
+	avatarStorage    interfaces.AvatarStorage
+	security         interfaces.ServiceSecurity
+	publicUrl        string
+	coolingOff       time.Duration
+}
+
+func newPlayerService(
+	playerRepository interfaces.RepositoryPlayer,
+	gameRepository interfaces.GameRepository,
+	pointsRepository interfaces.RepositoryPoints,
+	chatRepository interfaces.RepositoryChat,
+	presence interfaces.ServicePresence,
+	avatarStorage interfaces.AvatarStorage,
+	security interfaces.ServiceSecurity,
+	publicUrl string,
+	coolingOff time.Duration,
+) *playerService {
+	return &playerService{
+		playerRepository: playerRepository,
+		gameRepository:   gameRepository,
+		pointsRepository: pointsRepository,
+		chatRepository:   chatRepository,
+		presence:         presence,
+		avatarStorage:    avatarStorage,
+		security:         security,
+		publicUrl:        publicUrl,
+		coolingOff:       coolingOff,
+	}
+}
+
+func (p *playerService) Profile(playerId uuid.UUID) (*entities.Player, error) {
+	return p.playerRepository.FindById(playerId)
+}
+
+func (p *playerService) UpdateProfile(playerId uuid.UUID, update *entities.ProfileUpdate) (*entities.Player, error) {
+	player, err := p.playerRepository.FindById(playerId)
+	if err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+
+	previousAvatarFile := player.AvatarFile
+	if update.DisplayName != nil {
+		displayName, err := normalizeDisplayName(*update.DisplayName)
+		if err != nil {
+			return nil, err
+		}
+		player.SetDisplayName(displayName)
+	}
+	if update.AvatarURL != nil {
+		avatarUrl := strings.TrimSpace(*update.AvatarURL)
+		if avatarUrl != "" && !isValidAvatarUrl(avatarUrl) {
+			return nil, customErrors.NewBadRequestError("avatar_url must be an http or https URL")
+		}
+		player.AvatarURL, player.AvatarFile = avatarUrl, ""
+	}
+	if update.Locale != nil {
+		if !localePattern.MatchString(*update.Locale) {
+			return nil, customErrors.NewBadRequestError("locale must be a language tag like en or pt-BR")
+		}
+		player.Locale = *update.Locale
+	}
+
+	if err := p.saveProfile(player); err != nil {
+		return nil, err
+	}
+	p.deleteAvatarFile(previousAvatarFile, player.AvatarFile)
+
+	return player, nil
+}
+
+// UploadAvatar stores a PNG, JPEG, GIF or WebP image, the type is detected from the content.
+func (p *playerService) UploadAvatar(playerId uuid.UUID, data []byte) (*entities.Player, error) {
+	player, err := p.playerRepository.FindById(playerId)
+	if err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+	if len(data) == 0 {
+		return nil, customErrors.NewBadRequestError("avatar is empty")
+	}
+	if len(data) > MaxAvatarBytes {
+		return nil, customErrors.NewBadRequestError(fmt.Sprintf("avatar must not exceed %d bytes", MaxAvatarBytes))
+	}
+	extension, ok := avatarExtensions[http.DetectContentType(data)]
+	if !ok {
+		return nil, customErrors.NewBadRequestError("avatar must be a PNG, JPEG, GIF or WebP image")
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	// A new name for every upload, so the caches never serve the previous image.
+	name := fmt.Sprintf("%s-%s%s", player.ID, hex.EncodeToString(suffix), extension)
+	if err := p.avatarStorage.Save(name, data); err != nil {
+		return nil, err
+	}
+
+	previousAvatarFile := player.AvatarFile
+	player.AvatarFile = name
+	player.AvatarURL = fmt.Sprintf("%s/avatars/%s", p.publicUrl, name)
+	if err := p.saveProfile(player); err != nil {
+		p.deleteAvatarFile(name, "")
+		return nil, err
+	}
+	p.deleteAvatarFile(previousAvatarFile, name)
+
+	return player, nil
+}
+
+func (p *playerService) Avatar(name string) (io.ReadSeekCloser, time.Time, error) {
+	return p.avatarStorage.Open(name)
+}
+
+func (p *playerService) Export(playerId uuid.UUID) (*entities.PlayerExport, error) {
+	player, err := p.playerRepository.FindById(playerId)
+	if err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+	roles, err := p.playerRepository.FindRoles(playerId)
+	if err != nil {
+		return nil, err
+	}
+	games, err := p.gameRepository.FindForExport(playerId)
+	if err != nil {
+		return nil, err
+	}
+	points, err := p.pointsRepository.FindByPlayer(playerId, -1)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := p.chatRepository.FindByPlayer(playerId)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := p.chatRepository.FindReportsByReporter(playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.PlayerExport{
+		Player:       player,
+		Roles:        roles,
+		Games:        games,
+		Points:       points,
+		ChatMessages: messages,
+		ChatReports:  reports,
+		ExportedAt:   time.Now(),
+	}, nil
+}
+
+// ScheduleDeletion deletes the account once the cooling-off period is over, until then the player
+// keeps playing and can take it back.
+func (p *playerService) ScheduleDeletion(playerId uuid.UUID, password string) (*entities.Player, error) {
+	player, err := p.playerRepository.FindById(playerId)
+	if err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+	if valid, _ := p.security.VerifyPassword(password, player.Password); !valid {
+		return nil, customErrors.NewBadRequestError("password is incorrect")
+	}
+	if player.DeletionScheduledAt != nil {
+		return nil, customErrors.NewBadRequestError("the account deletion is already scheduled")
+	}
+
+	scheduledAt := time.Now().Add(p.coolingOff)
+	if err := p.playerRepository.ScheduleDeletion(playerId, &scheduledAt); err != nil {
+		return nil, err
+	}
+	player.DeletionScheduledAt = &scheduledAt
+
+	return player, nil
+}
+
+func (p *playerService) CancelDeletion(playerId uuid.UUID) (*entities.Player, error) {
+	player, err := p.playerRepository.FindById(playerId)
+	if err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+	if player.DeletionScheduledAt == nil {
+		return nil, customErrors.NewBadRequestError("the account deletion is not scheduled")
+	}
+
+	if err := p.playerRepository.ScheduleDeletion(playerId, nil); err != nil {
+		return nil, err
+	}
+	player.DeletionScheduledAt = nil
+
+	return player, nil
+}
+
+// DeleteDue anonymises the accounts whose cooling-off period is over.
+func (p *playerService) DeleteDue() error {
+	now := time.Now()
+	players, err := p.playerRepository.FindDueDeletions(now)
+	if err != nil {
+		return err
+	}
+
+	for index := range players {
+		player := &players[index]
+		avatarFile := player.AvatarFile
+		anonymised, err := p.playerRepository.Anonymise(player, now)
+		if err != nil {
+			return err
+		}
+		if anonymised {
+			p.presence.LeftGames(player.ID)
+			p.deleteAvatarFile(avatarFile, "")
+			log.Printf("Deleted the account of player %s\n", player.ID)
+		}
+	}
+
+	return nil
+}
+
+func (p *playerService) saveProfile(player *entities.Player) error {
+	err := p.playerRepository.UpdateProfile(player)
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) && pgError.Code == repositories.UniqueViolation {
+		return customErrors.NewUniqueViolationError("This display name is already taken")
+	}
+
+	return err
+}
+
+// deleteAvatarFile removes the replaced upload, a leftover file only wastes space.
+func (p *playerService) deleteAvatarFile(name, current string) {
+	if name == "" || name == current {
+		return
+	}
+
+	if err := p.avatarStorage.Delete(name); err != nil {
+		log.Printf("Failed to delete avatar %s: %s\n", name, err.Error())
+	}
+}
+
+// normalizeDisplayName trims and collapses the spaces, then checks the length and the characters:
+// letters, digits, spaces, '_', '-' and '.', starting with a letter or a digit.
+func normalizeDisplayName(displayName string) (string, error) {
+	displayName = strings.Join(strings.Fields(displayName), " ")
+
+	length := utf8.RuneCountInString(displayName)
+	if length < minDisplayNameLength || length > maxDisplayNameLength {
+		return "", customErrors.NewBadRequestError(fmt.Sprintf(
+			"display name must be from %d to %d characters long",
+			minDisplayNameLength, maxDisplayNameLength,
+		))
+	}
+	for i, r := range displayName {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			continue
+		}
+		if i > 0 && (r == ' ' || r == '_' || r == '-' || r == '.') {
+			continue
+		}
+
+		return "", customErrors.NewBadRequestError(
+			"display name may contain only letters, digits, spaces, '_', '-' and '.' and must start with a letter or a digit",
+		)
+	}
+	for _, prefix := range reservedDisplayNamePrefixes {
+		if strings.HasPrefix(strings.ToLower(displayName), prefix) {
+			return "", customErrors.NewBadRequestError("this display name is reserved")
+		}
+	}
+
+	return displayName, nil
+}
+
+func isValidAvatarUrl(avatarUrl string) bool {
+	if len(avatarUrl) > maxAvatarUrlLength {
+		return false
+	}
+
+	parsed, err := url.Parse(avatarUrl)
+
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
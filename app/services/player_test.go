@@ -0,0 +1,71 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type normalizeDisplayNameTestCase struct {
+	displayName   string
+	expected      string
+	expectedError string
+	name          string
+}
+
+func TestNormalizeDisplayName(t *testing.T) {
+	testCases := []normalizeDisplayNameTestCase{
+		{
+			displayName: "  Johnny   Doe ",
+			expected:    "Johnny Doe",
+			name:        "spaces are collapsed",
+		},
+		{
+			displayName: "Jöhn_Doe-2.0",
+			expected:    "Jöhn_Doe-2.0",
+			name:        "letters of any alphabet and the separators",
+		},
+		{
+			displayName: "Жук",
+			expected:    "Жук",
+			name:        "the length is counted in characters",
+		},
+		{
+			displayName:   " a ",
+			expectedError: "display name must be from 3 to 32 characters long",
+			name:          "too short",
+		},
+		{
+			displayName:   "abcdefghijklmnopqrstuvwxyz1234567",
+			expectedError: "display name must be from 3 to 32 characters long",
+			name:          "too long",
+		},
+		{
+			displayName:   "_john",
+			expectedError: "display name may contain only letters, digits, spaces, '_', '-' and '.' and must start with a letter or a digit",
+			name:          "starts with a separator",
+		},
+		{
+			displayName:   "john<script>",
+			expectedError: "display name may contain only letters, digits, spaces, '_', '-' and '.' and must start with a letter or a digit",
+			name:          "a forbidden character",
+		},
+		{
+			displayName:   "BOT  smith",
+			expectedError: "this display name is reserved",
+			name:          "passes for a bot",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			displayName, err := normalizeDisplayName(tCase.displayName)
+			if tCase.expectedError != "" {
+				assert.EqualError(tt, err, tCase.expectedError)
+				return
+			}
+			if assert.NoError(tt, err) {
+				assert.Equal(tt, tCase.expected, displayName)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"knb/app/interfaces"
+	"knb/app/repositories"
+	"log"
+	"time"
+)
+
+const (
+	// presenceTouchInterval spares the store a write for every request of an active player.
+	presenceTouchInterval = 30 * time.Second
+	// presenceRetention is how long the last sign of life of a player who went away is remembered.
+	presenceRetention = 7 * 24 * time.Hour
+
+	defaultLobbyPlayers = 50
+	maxLobbyPlayers     = 200
+)
+
+type presenceService struct {
+	presenceRepository interfaces.RepositoryPresence
+	playerRepository   interfaces.RepositoryPlayer
+	gameRepository     interfaces.GameRepository
+	friendRepository   interfaces.RepositoryFriend
+	ttl                time.Duration
+}
+
+func newPresenceService(
+	presenceRepository interfaces.RepositoryPresence,
+	playerRepository interfaces.RepositoryPlayer,
+	gameRepository interfaces.GameRepository,
+	friendRepository interfaces.RepositoryFriend,
+	ttl time.Duration,
+) *presenceService {
+	return &presenceService{
+		presenceRepository: presenceRepository,
+		playerRepository:   playerRepository,
+		gameRepository:     gameRepository,
+		friendRepository:   friendRepository,
+		ttl:                ttl,
+	}
+}
+
+// Touch counts a request of the player as a sign of life, a failure must not fail the request.
+func (p *presenceService) Touch(playerId uuid.UUID) {
+	now := time.Now()
+	if err := p.presenceRepository.Touch(playerId, now, now.Add(-presenceTouchInterval)); err != nil {
+		log.Printf("Failed to record presence of player %s: %s\n", playerId, err.Error())
+	}
+}
+
+func (p *presenceService) EnterGame(playerId, gameId uuid.UUID) {
+	if err := p.presenceRepository.SetGame(playerId, gameId, time.Now()); err != nil {
+		log.Printf("Failed to record game of player %s: %s\n", playerId, err.Error())
+	}
+}
+
+// GameFinished sends the players of the game back to the lobby.
+func (p *presenceService) GameFinished(game *entities.Game) {
+	if err := p.presenceRepository.ClearGame(game.ID); err != nil {
+		log.Printf("Failed to clear presence in game %s: %s\n", game.ID, err.Error())
+	}
+}
+
+// LeftGames sends the player back to the lobby when they were taken out of the game they are seen in,
+// by a block, a ban or the deletion of the account.
+func (p *presenceService) LeftGames(playerId uuid.UUID) {
+	presences, err := p.presenceRepository.Find([]uuid.UUID{playerId})
+	if err != nil {
+		log.Printf("Failed to read presence of player %s: %s\n", playerId, err.Error())
+		return
+	}
+	if len(presences) == 0 || presences[0].GameID == nil {
+		return
+	}
+
+	gameId := *presences[0].GameID
+	game, err := p.gameRepository.FindById(gameId)
+	if err != nil && err.Error() != repositories.RecordNotFoundError {
+		log.Printf("Failed to read game %s: %s\n", gameId, err.Error())
+		return
+	}
+	if err == nil && isParticipant(game, playerId) {
+		return
+	}
+	if err := p.presenceRepository.LeaveGame(playerId, gameId); err != nil {
+		log.Printf("Failed to clear presence of player %s: %s\n", playerId, err.Error())
+	}
+}
+
+// Find leaves out the players who have not been seen lately.
+func (p *presenceService) Find(playerIds []uuid.UUID) (map[uuid.UUID]entities.PlayerPresence, error) {
+	presences, err := p.presenceRepository.Find(playerIds)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	found := make(map[uuid.UUID]entities.PlayerPresence, len(presences))
+	for _, presence := range presences {
+		found[presence.PlayerID] = p.playerPresence(presence, now)
+	}
+
+	return found, nil
+}
+
+// Visible keeps a player who blocked the viewer, or the owner of the viewing bot, out of their sight.
+func (p *presenceService) Visible(viewerId, playerId uuid.UUID) (entities.PlayerPresence, error) {
+	viewer, err := p.playerRepository.FindById(viewerId)
+	if err != nil {
+		return entities.PlayerPresence{}, err
+	}
+	viewerIds := []uuid.UUID{viewer.ID}
+	if viewer.OwnerID != nil {
+		viewerIds = append(viewerIds, *viewer.OwnerID)
+	}
+	for _, id := range viewerIds {
+		blocked, err := p.friendRepository.IsBlocked([]uuid.UUID{playerId}, id)
+		if err != nil {
+			return entities.PlayerPresence{}, err
+		}
+		if blocked {
+			return entities.PlayerPresence{}, nil
+		}
+	}
+
+	presences, err := p.Find([]uuid.UUID{playerId})
+	if err != nil {
+		return entities.PlayerPresence{}, err
+	}
+
+	return presences[playerId], nil
+}
+
+// Online lists the people who are around, the most recently active first.
+func (p *presenceService) Online(limit int) ([]entities.OnlinePlayer, error) {
+	if limit <= 0 {
+		limit = defaultLobbyPlayers
+	}
+	limit = min(limit, maxLobbyPlayers)
+
+	now := time.Now()
+	online := make([]entities.OnlinePlayer, 0, limit)
+	// The memory store keeps the bots too, so the pages are read until the list is full.
+	for offset := 0; len(online) < limit; offset += limit {
+		presences, err := p.presenceRepository.FindSince(now.Add(-p.ttl), limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		playerIds := make([]uuid.UUID, 0, len(presences))
+		for _, presence := range presences {
+			playerIds = append(playerIds, presence.PlayerID)
+		}
+		players, err := p.playerRepository.FindByIds(playerIds)
+		if err != nil {
+			return nil, err
+		}
+		byId := make(map[uuid.UUID]*entities.Player, len(players))
+		for index := range players {
+			byId[players[index].ID] = &players[index]
+		}
+
+		for _, presence := range presences {
+			player, ok := byId[presence.PlayerID]
+			if !ok || player.IsBot || player.AnonymisedAt != nil {
+				continue
+			}
+			online = append(online, entities.OnlinePlayer{Player: player, Presence: p.playerPresence(presence, now)})
+			if len(online) == limit {
+				break
+			}
+		}
+		if len(presences) < limit {
+			break
+		}
+	}
+
+	return online, nil
+}
+
+func (p *presenceService) Cleanup() error {
+	return p.presenceRepository.Cleanup(time.Now().Add(-presenceRetention))
+}
+
+func (p *presenceService) playerPresence(presence entities.Presence, now time.Time) entities.PlayerPresence {
+	lastSeenAt := presence.LastSeenAt
+	playerPresence := entities.PlayerPresence{
+		Online:     now.Sub(lastSeenAt) < p.ttl,
+		LastSeenAt: &lastSeenAt,
+	}
+	if playerPresence.Online {
+		playerPresence.GameID = presence.GameID
+	}
+
+	return playerPresence
+}
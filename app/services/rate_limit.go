@@ -0,0 +1,77 @@
+package services
+
+import (
+	"knb/app/config"
+	"knb/app/dictionary"
+	"sync"
+	"time"
+)
+
+type rateLimitRule struct {
+	limit  int
+	window time.Duration
+}
+
+type rateLimitWindow struct {
+	startedAt time.Time
+	count     int
+}
+
+// rateLimitService is a fixed-window limiter kept in the memory of the instance.
+type rateLimitService struct {
+	mu        sync.Mutex
+	rules     map[dictionary.RateLimit]rateLimitRule
+	windows   map[string]*rateLimitWindow
+	cleanedAt time.Time
+}
+
+func newRateLimitService(config config.RateLimitConfig) *rateLimitService {
+	return &rateLimitService{
+		rules: map[dictionary.RateLimit]rateLimitRule{
+			dictionary.RateLimitPlayerRequests: {config.PlayerRequestsPerMinute, time.Minute},
+			dictionary.RateLimitBotRequests:    {config.BotRequestsPerMinute, time.Minute},
+			dictionary.RateLimitBotGames:       {config.BotGamesPerHour, time.Hour},
+			dictionary.RateLimitChatMessages:   {config.ChatMessagesPerMinute, time.Minute},
+		},
+		windows: make(map[string]*rateLimitWindow),
+	}
+}
+
+func (r *rateLimitService) Allow(kind dictionary.RateLimit, identity string) (bool, time.Duration) {
+	rule, ok := r.rules[kind]
+	if !ok || rule.limit <= 0 {
+		return true, 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	key := string(kind) + ":" + identity
+	window, ok := r.windows[key]
+	if !ok || now.Sub(window.startedAt) >= rule.window {
+		r.cleanup(now)
+		window = &rateLimitWindow{startedAt: now}
+		r.windows[key] = window
+	}
+
+	if window.count >= rule.limit {
+		return false, rule.window - now.Sub(window.startedAt)
+	}
+	window.count++
+
+	return true, 0
+}
+
+func (r *rateLimitService) cleanup(now time.Time) {
+	if now.Sub(r.cleanedAt) < time.Hour {
+		return
+	}
+	r.cleanedAt = now
+
+	for key, window := range r.windows {
+		if now.Sub(window.startedAt) >= time.Hour {
+			delete(r.windows, key)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"slices"
+)
+
+type roleService struct {
+	playerRepository interfaces.RepositoryPlayer
+}
+
+func newRoleService(playerRepository interfaces.RepositoryPlayer) *roleService {
+	return &roleService{playerRepository}
+}
+
+func (r *roleService) Roles(playerId uuid.UUID) (entities.Roles, error) {
+	if _, err := r.playerRepository.FindById(playerId); err != nil {
+		return nil, err
+	}
+
+	return r.playerRepository.FindRoles(playerId)
+}
+
+// Grant reaches the access tokens of the player at their next refresh.
+// grantedBy is nil when the role is granted from the command line.
+func (r *roleService) Grant(playerId uuid.UUID, role dictionary.Role, grantedBy *uuid.UUID) (entities.Roles, error) {
+	if err := checkRole(role); err != nil {
+		return nil, err
+	}
+	player, err := r.playerRepository.FindById(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if player.IsBot {
+		return nil, customErrors.NewBadRequestError("bots can't have roles")
+	}
+
+	audit := roleAudit(grantedBy, dictionary.AuditActionRoleGrant, playerId, role)
+	if _, err := r.playerRepository.GrantRole(playerId, role, grantedBy, audit); err != nil {
+		return nil, err
+	}
+
+	return r.playerRepository.FindRoles(playerId)
+}
+
+// Revoke ends the sessions of the player, the roles embedded in their access tokens must not outlive it.
+func (r *roleService) Revoke(playerId uuid.UUID, role dictionary.Role, revokedBy *uuid.UUID) (entities.Roles, error) {
+	if err := checkRole(role); err != nil {
+		return nil, err
+	}
+	if _, err := r.playerRepository.FindById(playerId); err != nil {
+		return nil, err
+	}
+	// Keeps an admin from locking themselves out by accident.
+	if revokedBy != nil && *revokedBy == playerId && role == dictionary.RoleAdmin {
+		return nil, customErrors.NewBadRequestError("you can't revoke your own admin role")
+	}
+
+	audit := roleAudit(revokedBy, dictionary.AuditActionRoleRevoke, playerId, role)
+	if _, err := r.playerRepository.RevokeRole(playerId, role, audit); err != nil {
+		return nil, err
+	}
+
+	return r.playerRepository.FindRoles(playerId)
+}
+
+// roleAudit is written only when the role of the player actually changes.
+func roleAudit(
+	actorId *uuid.UUID,
+	action dictionary.AuditAction,
+	playerId uuid.UUID,
+	role dictionary.Role,
+) *entities.AuditEntry {
+	return entities.NewAuditEntry(
+		actorId, action, dictionary.AuditTargetPlayer, playerId, "", map[string]interface{}{"role": role},
+	)
+}
+
+func checkRole(role dictionary.Role) error {
+	if !slices.Contains(dictionary.Roles, role) {
+		return customErrors.NewBadRequestError(fmt.Sprintf("role '%s' doesn't exist", role))
+	}
+
+	return nil
+}
@@ -1,73 +1,342 @@
 package services
 
 import (
-	"crypto/sha1"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"fmt"
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"knb/app/config"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"strings"
 	"time"
 )
 
 const (
-	salt     = "8yU9nA`tx$=k-XPB/4@(ctf[n$Me;#Mhk^T]jBZC/K"
-	tokenTTL = 12 * time.Hour
+	legacySalt     = "8yU9nA`tx$=k-XPB/4@(ctf[n$Me;#Mhk^T]jBZC/K"
+	accessTokenTTL = 15 * time.Minute
+
+	apiKeyPrefix      = "knb_"
+	apiKeyBytes       = 32
+	apiKeyShownPrefix = 12
+
+	refreshTokenPrefix = "knbr_"
+	refreshTokenBytes  = 32
+
+	passwordResetTokenPrefix = "knbp_"
+	passwordResetTokenBytes  = 32
+
+	csrfTokenBytes = 32
+
+	emailVerificationPurpose = "email_verification"
+	emailVerificationTTL     = 48 * time.Hour
+
+	loginChallengePurpose = "login_challenge"
+	loginChallengeTTL     = 5 * time.Minute
 )
 
 type securityService struct {
-	tokenSigningKey string
+	tokenKeys  *tokenKeySet
+	issuer     string
+	audience   string
+	leeway     time.Duration
+	totpIssuer string
 }
 
-func newSecurityService(tokenSigningKey string) *securityService {
-	return &securityService{tokenSigningKey}
+func newSecurityService(authConfig config.AuthConfig) (*securityService, error) {
+	tokenKeys, err := newTokenKeySet(authConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &securityService{
+		tokenKeys:  tokenKeys,
+		issuer:     authConfig.TokenIssuer,
+		audience:   authConfig.TokenAudience,
+		leeway:     authConfig.TokenLeeway,
+		totpIssuer: authConfig.TotpIssuer,
+	}, nil
 }
 
 type tokenClaims struct {
-	jwt.StandardClaims
-	PlayerId string `json:"player_id"`
+	jwt.RegisteredClaims
+	PlayerId  string         `json:"player_id"`
+	SessionId string         `json:"session_id"`
+	Roles     entities.Roles `json:"roles,omitempty"`
 }
 
-func (s *securityService) GeneratePasswordHash(password string) string {
-	hash := sha1.New()
-	hash.Write([]byte(password))
+// GenerateAuthToken embeds the roles so the permission checks don't hit the database,
+// a change of the roles reaches the token at the next refresh.
+func (s *securityService) GenerateAuthToken(
+	playerId, sessionId uuid.UUID,
+	roles entities.Roles,
+) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(accessTokenTTL)
+	signingKey := s.tokenKeys.active
+	token := jwt.NewWithClaims(
+		signingKey.method,
+		&tokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    s.issuer,
+				Subject:   playerId.String(),
+				Audience:  jwt.ClaimStrings{s.audience},
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+				NotBefore: jwt.NewNumericDate(now),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+			PlayerId:  playerId.String(),
+			SessionId: sessionId.String(),
+			Roles:     roles,
+		},
+	)
+	token.Header["kid"] = signingKey.id
+
+	signed, err := token.SignedString(signingKey.signKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
 
-	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
+	return signed, expiresAt, nil
 }
 
-func (s *securityService) GenerateAuthToken(playerId uuid.UUID) (string, error) {
+// ParseAuthToken validates the signature, exp, nbf, iss and aud of an access token.
+// The returned errors tell an expired token, that has to be refreshed, from a bogus one.
+func (s *securityService) ParseAuthToken(
+	accessToken string,
+) (playerId, sessionId uuid.UUID, roles entities.Roles, err error) {
+	claims := &tokenClaims{}
+	_, err = jwt.ParseWithClaims(
+		accessToken,
+		claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return s.tokenKeys.verificationKey(token, time.Now())
+		},
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithLeeway(s.leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, nil, tokenError(err)
+	}
+
+	playerId, err = uuid.Parse(claims.PlayerId)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, nil, customErrors.NewTokenClaimsError("Unauthorized")
+	}
+	sessionId, err = uuid.Parse(claims.SessionId)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, nil, customErrors.NewTokenClaimsError("Unauthorized")
+	}
+
+	return playerId, sessionId, claims.Roles, nil
+}
+
+func tokenError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return customErrors.NewTokenExpiredError("Unauthorized")
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return customErrors.NewTokenSignatureError("Unauthorized")
+	case errors.Is(err, jwt.ErrTokenInvalidClaims):
+		return customErrors.NewTokenClaimsError("Unauthorized")
+	}
+
+	return customErrors.NewTokenMalformedError("Unauthorized")
+}
+
+type emailVerificationClaims struct {
+	jwt.RegisteredClaims
+	Purpose string `json:"purpose"`
+	Email   string `json:"email"`
+}
+
+// GenerateEmailVerificationToken signs the link sent to a new player, it stays valid only for the same email.
+func (s *securityService) GenerateEmailVerificationToken(playerId uuid.UUID, email string) (string, error) {
+	now := time.Now()
+	signingKey := s.tokenKeys.active
 	token := jwt.NewWithClaims(
-		jwt.SigningMethodHS256,
-		&tokenClaims{
-			StandardClaims: jwt.StandardClaims{
-				ExpiresAt: time.Now().Add(tokenTTL).Unix(),
-				IssuedAt:  time.Now().Unix(),
+		signingKey.method,
+		&emailVerificationClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    s.issuer,
+				Subject:   playerId.String(),
+				Audience:  jwt.ClaimStrings{s.audience},
+				ExpiresAt: jwt.NewNumericDate(now.Add(emailVerificationTTL)),
+				IssuedAt:  jwt.NewNumericDate(now),
 			},
-			PlayerId: playerId.String(),
+			Purpose: emailVerificationPurpose,
+			Email:   email,
 		},
 	)
+	token.Header["kid"] = signingKey.id
 
-	return token.SignedString([]byte(s.tokenSigningKey))
+	return token.SignedString(signingKey.signKey)
 }
 
-func (s *securityService) ParseAuthToken(accessToken string) (string, error) {
-	token, err := jwt.ParseWithClaims(
-		accessToken,
-		&tokenClaims{},
+func (s *securityService) ParseEmailVerificationToken(verificationToken string) (uuid.UUID, string, error) {
+	claims := &emailVerificationClaims{}
+	_, err := jwt.ParseWithClaims(
+		verificationToken,
+		claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return s.tokenKeys.verificationKey(token, time.Now())
+		},
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithLeeway(s.leeway),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, "", tokenError(err)
+	}
+	if claims.Purpose != emailVerificationPurpose {
+		return uuid.Nil, "", customErrors.NewTokenClaimsError("verification link is invalid")
+	}
+
+	playerId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, "", customErrors.NewTokenClaimsError("verification link is invalid")
+	}
+
+	return playerId, claims.Email, nil
+}
+
+type loginChallengeClaims struct {
+	jwt.RegisteredClaims
+	Purpose string `json:"purpose"`
+}
+
+// GenerateLoginChallengeToken proves the password step of a two-factor login, it can't be used as an access token.
+func (s *securityService) GenerateLoginChallengeToken(playerId uuid.UUID) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(loginChallengeTTL)
+	signingKey := s.tokenKeys.active
+	token := jwt.NewWithClaims(
+		signingKey.method,
+		&loginChallengeClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    s.issuer,
+				Subject:   playerId.String(),
+				Audience:  jwt.ClaimStrings{s.audience},
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+			Purpose: loginChallengePurpose,
+		},
+	)
+	token.Header["kid"] = signingKey.id
+
+	signed, err := token.SignedString(signingKey.signKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+func (s *securityService) ParseLoginChallengeToken(challengeToken string) (uuid.UUID, error) {
+	claims := &loginChallengeClaims{}
+	_, err := jwt.ParseWithClaims(
+		challengeToken,
+		claims,
 		func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("invalid signed method")
-			}
-			return []byte(s.tokenSigningKey), nil
+			return s.tokenKeys.verificationKey(token, time.Now())
 		},
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithLeeway(s.leeway),
+		jwt.WithExpirationRequired(),
 	)
 	if err != nil {
+		return uuid.Nil, tokenError(err)
+	}
+	if claims.Purpose != loginChallengePurpose {
+		return uuid.Nil, customErrors.NewTokenClaimsError("Unauthorized")
+	}
+
+	playerId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, customErrors.NewTokenClaimsError("Unauthorized")
+	}
+
+	return playerId, nil
+}
+
+func (s *securityService) PublicTokenKeys() []entities.TokenPublicKey {
+	return s.tokenKeys.publicKeys(time.Now())
+}
+
+func (s *securityService) GenerateApiKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, apiKeyBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, key[:apiKeyShownPrefix], s.HashApiKey(key), nil
+}
+
+func (s *securityService) IsApiKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+func (s *securityService) HashApiKey(key string) string {
+	return sha256Hex(key)
+}
+
+func (s *securityService) GenerateRefreshToken() (token, hash string, err error) {
+	token, err = randomToken(refreshTokenPrefix, refreshTokenBytes)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, s.HashRefreshToken(token), nil
+}
+
+func (s *securityService) HashRefreshToken(token string) string {
+	return sha256Hex(token)
+}
+
+func (s *securityService) GeneratePasswordResetToken() (token, hash string, err error) {
+	token, err = randomToken(passwordResetTokenPrefix, passwordResetTokenBytes)
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, s.HashPasswordResetToken(token), nil
+}
+
+func (s *securityService) HashPasswordResetToken(token string) string {
+	return sha256Hex(token)
+}
+
+func (s *securityService) GenerateCsrfToken() (string, error) {
+	secret := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
 		return "", err
 	}
 
-	claims, ok := token.Claims.(*tokenClaims)
-	if !ok {
-		return "", errors.New("token claims are not type *tokenClaims")
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func randomToken(prefix string, size int) (string, error) {
+	secret := make([]byte, size)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
 	}
 
-	return claims.PlayerId, err
+	return prefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func sha256Hex(value string) string {
+	hash := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(hash[:])
 }
@@ -3,19 +3,114 @@ package services
 import (
 	"knb/app/config"
 	"knb/app/interfaces"
+	"knb/app/mailer"
+	"knb/app/moderation"
 	"knb/app/repositories"
+	"knb/app/storage"
+	"log"
 )
 
 type Service struct {
-	Security interfaces.ServiceSecurity
-	Auth     interfaces.ServiceAuth
-	Game     interfaces.ServiceGame
+	Security      interfaces.ServiceSecurity
+	Auth          interfaces.ServiceAuth
+	Game          interfaces.ServiceGame
+	ApiKey        interfaces.ServiceApiKey
+	RateLimit     interfaces.ServiceRateLimit
+	Tournament    interfaces.ServiceTournament
+	TwoFactor     interfaces.ServiceTwoFactor
+	LoginThrottle interfaces.ServiceLoginThrottle
+	Player        interfaces.ServicePlayer
+	Role          interfaces.ServiceRole
+	Admin         interfaces.ServiceAdmin
+	Friend        interfaces.ServiceFriend
+	Presence      interfaces.ServicePresence
+	Chat          interfaces.ServiceChat
+	Stats         interfaces.ServiceStats
+	Leaderboard   interfaces.ServiceLeaderboard
 }
 
 func NewService(repository *repositories.Repository, config *config.Config) *Service {
+	security, err := newSecurityService(config.AuthConfig)
+	if err != nil {
+		log.Fatalf("Failed to load token signing keys: %s\n", err.Error())
+	}
+	auth := newAuthService(
+		repository.Player,
+		repository.Session,
+		repository.PasswordReset,
+		repository.Ban,
+		security,
+		mailer.NewMailer(config.MailConfig),
+		config.AppPublicUrl,
+		config.PasswordResetUrl,
+	)
+	loginThrottleRepository := repository.LoginThrottle
+	if config.LoginThrottleConfig.InMemory() {
+		loginThrottleRepository = repositories.NewMemoryLoginThrottleRepository()
+	}
+	presenceRepository := repository.Presence
+	if config.PresenceConfig.InMemory() {
+		presenceRepository = repositories.NewMemoryPresenceRepository()
+	}
+	presence := newPresenceService(
+		presenceRepository,
+		repository.Player,
+		repository.Game,
+		repository.Friend,
+		config.PresenceConfig.TTL,
+	)
+	game := newGameService(repository.Game, repository.Player, repository.Friend, presence)
+	tournament := newTournamentService(repository.Tournament, repository.Game, repository.Player)
+	game.AddFinishedListener(tournament)
+	game.AddFinishedListener(presence)
+	stats := newStatsService(repository.Stats, repository.Game, repository.Player)
+	game.AddFinishedListener(stats)
+	leaderboard := newLeaderboardService(repository.Leaderboard, repository.Player)
+	game.AddFinishedListener(leaderboard)
+
 	return &Service{
-		Security: newSecurityService(config.AuthConfig.TokenSigningKey),
-		Auth:     newAuthService(repository.Player),
-		Game:     newGameService(repository.Game, repository.Player),
+		Security:   security,
+		Auth:       auth,
+		Game:       game,
+		ApiKey:     newApiKeyService(repository.ApiKey, repository.Player, repository.Ban, security),
+		RateLimit:  newRateLimitService(config.RateLimitConfig),
+		Tournament: tournament,
+		TwoFactor:  newTwoFactorService(repository.TwoFactor, repository.Player, security),
+		Player: newPlayerService(
+			repository.Player,
+			repository.Game,
+			repository.Points,
+			repository.Chat,
+			presence,
+			storage.NewLocalAvatarStorage(config.AvatarDir),
+			security,
+			config.AppPublicUrl,
+			config.AccountDeletionCoolingOff,
+		),
+		LoginThrottle: newLoginThrottleService(
+			loginThrottleRepository,
+			repository.LoginFailure,
+			config.LoginThrottleConfig,
+		),
+		Role: newRoleService(repository.Player),
+		Admin: newAdminService(
+			game,
+			repository.Player,
+			repository.Game,
+			repository.Points,
+			repository.Ban,
+			repository.Audit,
+			leaderboard,
+			presence,
+		),
+		Friend:   newFriendService(repository.Friend, repository.Player, repository.Game, presence),
+		Presence: presence,
+		Chat: newChatService(
+			repository.Chat,
+			repository.Game,
+			moderation.NewProfanityFilter(config.ChatProfanityWords),
+		),
+		Stats:       stats,
+		Leaderboard: leaderboard,
 	}
 }
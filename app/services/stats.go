@@ -0,0 +1,262 @@
+package services
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"knb/app/rules"
+	"log"
+	"time"
+)
+
+type statsService struct {
+	statsRepository  interfaces.RepositoryStats
+	gameRepository   interfaces.GameRepository
+	playerRepository interfaces.RepositoryPlayer
+}
+
+func newStatsService(
+	statsRepository interfaces.RepositoryStats,
+	gameRepository interfaces.GameRepository,
+	playerRepository interfaces.RepositoryPlayer,
+) *statsService {
+	return &statsService{
+		statsRepository:  statsRepository,
+		gameRepository:   gameRepository,
+		playerRepository: playerRepository,
+	}
+}
+
+// Find is an empty record for a player who has not finished a game yet.
+func (s *statsService) Find(playerId uuid.UUID) (*entities.PlayerStats, error) {
+	if _, err := s.playerRepository.FindById(playerId); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.statsRepository.Find(playerId)
+	var notFoundErr *customErrors.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		stats, _ = newStatsTally(&entities.PlayerStats{PlayerID: playerId}, nil).result()
+		return stats, nil
+	}
+
+	return stats, err
+}
+
+// HeadToHead is an empty record for players who never met.
+func (s *statsService) HeadToHead(playerId, opponentId uuid.UUID) (*entities.PlayerOpponentStats, error) {
+	if playerId == opponentId {
+		return nil, customErrors.NewBadRequestError("a player has no record against themselves")
+	}
+	if _, err := s.playerRepository.FindById(opponentId); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.statsRepository.FindOpponent(playerId, opponentId)
+	var notFoundErr *customErrors.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return &entities.PlayerOpponentStats{PlayerID: playerId, OpponentID: opponentId}, nil
+	}
+
+	return stats, err
+}
+
+// GameFinished adds the game to the stats of the people in it, a cancelled game is dropped by a rebuild.
+func (s *statsService) GameFinished(game *entities.Game) {
+	for _, player := range game.Players {
+		if player.IsBot {
+			continue
+		}
+		var err error
+		if game.CancelledAt != nil {
+			err = s.rebuild(player.ID)
+		} else {
+			err = s.add(player.ID, game)
+		}
+		if err != nil {
+			log.Printf("Failed to update stats of player %s: %s\n", player.ID, err.Error())
+		}
+	}
+}
+
+// Backfill builds the stats of the players whose games ended before the stats were kept.
+func (s *statsService) Backfill() error {
+	playerIds, err := s.statsRepository.FindUnbuilt()
+	if err != nil {
+		return err
+	}
+
+	for _, playerId := range playerIds {
+		if err := s.rebuild(playerId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *statsService) add(playerId uuid.UUID, game *entities.Game) error {
+	opponentIds := make([]uuid.UUID, 0, len(game.Players))
+	for _, player := range game.Players {
+		if player.ID != playerId && !player.IsBot {
+			opponentIds = append(opponentIds, player.ID)
+		}
+	}
+
+	return s.statsRepository.Apply(playerId, opponentIds, func(
+		stats *entities.PlayerStats,
+		opponents []entities.PlayerOpponentStats,
+	) (*entities.PlayerStats, []entities.PlayerOpponentStats) {
+		tally := newStatsTally(stats, opponents)
+		tally.add(game)
+		stats, opponents = tally.result()
+		stats.UpdatedAt = time.Now()
+
+		return stats, opponents
+	})
+}
+
+// rebuild counts all the finished games of the player over again.
+func (s *statsService) rebuild(playerId uuid.UUID) error {
+	games, err := s.gameRepository.FindFinishedByPlayer(playerId)
+	if err != nil {
+		return err
+	}
+
+	tally := newStatsTally(&entities.PlayerStats{PlayerID: playerId}, nil)
+	for index := range games {
+		tally.add(&games[index])
+	}
+	stats, opponents := tally.result()
+	stats.UpdatedAt = time.Now()
+
+	return s.statsRepository.Replace(stats, opponents)
+}
+
+// statsTally adds finished games up into the stats of a player.
+type statsTally struct {
+	stats       *entities.PlayerStats
+	ruleSets    map[dictionary.GameRuleSet]*entities.PlayerRuleSetStats
+	throws      map[dictionary.Throw]uint
+	opponents   map[uuid.UUID]*entities.PlayerOpponentStats
+	opponentIds []uuid.UUID
+}
+
+func newStatsTally(stats *entities.PlayerStats, opponents []entities.PlayerOpponentStats) *statsTally {
+	tally := &statsTally{
+		stats:       stats,
+		ruleSets:    make(map[dictionary.GameRuleSet]*entities.PlayerRuleSetStats, len(dictionary.GameRuleSets)),
+		throws:      make(map[dictionary.Throw]uint, len(dictionary.Throws)),
+		opponents:   make(map[uuid.UUID]*entities.PlayerOpponentStats, len(opponents)),
+		opponentIds: make([]uuid.UUID, 0, len(opponents)),
+	}
+	for index := range stats.RuleSets {
+		ruleSet := stats.RuleSets[index]
+		tally.ruleSets[ruleSet.RuleSet] = &ruleSet
+	}
+	for _, throw := range stats.Throws {
+		tally.throws[throw.Throw] = throw.Count
+	}
+	for index := range opponents {
+		tally.opponent(opponents[index].OpponentID, &opponents[index])
+	}
+
+	return tally
+}
+
+func (t *statsTally) add(game *entities.Game) {
+	playerId := t.stats.PlayerID
+	for _, move := range game.Moves {
+		if move.PlayerID == playerId {
+			t.throws[move.Throw]++
+		}
+	}
+
+	places := make(map[uuid.UUID]uint8, len(game.Result))
+	for _, result := range game.Result {
+		places[result.PlayerID] = result.Place
+	}
+	// an abandoned game gave no place, it was not played out
+	place, ok := places[playerId]
+	if !ok {
+		return
+	}
+
+	won := place == 1
+	t.stats.GamesPlayed++
+	ruleSet := rules.RuleSet(game)
+	if t.ruleSets[ruleSet] == nil {
+		t.ruleSets[ruleSet] = &entities.PlayerRuleSetStats{PlayerID: playerId, RuleSet: ruleSet}
+	}
+	t.ruleSets[ruleSet].GamesPlayed++
+	if won {
+		t.stats.GamesWon++
+		t.ruleSets[ruleSet].GamesWon++
+		t.stats.CurrentWinStreak++
+		t.stats.LongestWinStreak = max(t.stats.LongestWinStreak, t.stats.CurrentWinStreak)
+	} else {
+		t.stats.CurrentWinStreak = 0
+	}
+	if len(game.Players) > rules.MinPlayers {
+		t.stats.MultiPlayerGames++
+		t.stats.PlaceSum += uint(place)
+	}
+
+	for _, player := range game.Players {
+		opponentPlace, ok := places[player.ID]
+		if player.ID == playerId || player.IsBot || !ok {
+			continue
+		}
+		opponent := t.opponent(player.ID, nil)
+		opponent.GamesPlayed++
+		switch {
+		case place < opponentPlace:
+			opponent.Wins++
+		case place > opponentPlace:
+			opponent.Losses++
+		default:
+			opponent.Draws++
+		}
+	}
+}
+
+// opponent returns the record against the opponent, starting it from the given one or an empty one.
+func (t *statsTally) opponent(opponentId uuid.UUID, record *entities.PlayerOpponentStats) *entities.PlayerOpponentStats {
+	if t.opponents[opponentId] != nil {
+		return t.opponents[opponentId]
+	}
+	if record == nil {
+		record = &entities.PlayerOpponentStats{PlayerID: t.stats.PlayerID, OpponentID: opponentId}
+	}
+	t.opponents[opponentId] = record
+	t.opponentIds = append(t.opponentIds, opponentId)
+
+	return record
+}
+
+// result puts the rule sets and the throws back in the order of the dictionary.
+func (t *statsTally) result() (*entities.PlayerStats, []entities.PlayerOpponentStats) {
+	t.stats.RuleSets = make([]entities.PlayerRuleSetStats, 0, len(t.ruleSets))
+	for _, ruleSet := range dictionary.GameRuleSets {
+		if t.ruleSets[ruleSet] != nil {
+			t.stats.RuleSets = append(t.stats.RuleSets, *t.ruleSets[ruleSet])
+		}
+	}
+	t.stats.Throws = make([]entities.PlayerThrowStats, 0, len(dictionary.Throws))
+	for _, throw := range dictionary.Throws {
+		t.stats.Throws = append(t.stats.Throws, entities.PlayerThrowStats{
+			PlayerID: t.stats.PlayerID,
+			Throw:    throw,
+			Count:    t.throws[throw],
+		})
+	}
+	opponents := make([]entities.PlayerOpponentStats, 0, len(t.opponentIds))
+	for _, opponentId := range t.opponentIds {
+		opponents = append(opponents, *t.opponents[opponentId])
+	}
+
+	return t.stats, opponents
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"testing"
+)
+
+var (
+	statsPlayerOne   = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	statsPlayerTwo   = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	statsPlayerThree = uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+)
+
+type statsTallyTestCase struct {
+	places   [][]uint8
+	expected entities.PlayerStats
+	name     string
+}
+
+// statsGame is a finished game of the players with the given places, a zero place is a player who got none.
+func statsGame(places ...uint8) *entities.Game {
+	playerIds := []uuid.UUID{statsPlayerOne, statsPlayerTwo, statsPlayerThree}
+	game := &entities.Game{ID: uuid.New()}
+	for index, place := range places {
+		game.Players = append(game.Players, entities.Player{ID: playerIds[index]})
+		if place > 0 {
+			game.Result = append(game.Result, entities.GameResult{GameID: game.ID, PlayerID: playerIds[index], Place: place})
+		}
+	}
+
+	return game
+}
+
+func TestStatsTally(t *testing.T) {
+	testCases := []statsTallyTestCase{
+		{
+			places: [][]uint8{{1, 2}, {1, 2}, {2, 1}, {1, 2}},
+			expected: entities.PlayerStats{
+				GamesPlayed:      4,
+				GamesWon:         3,
+				CurrentWinStreak: 1,
+				LongestWinStreak: 2,
+			},
+			name: "a loss ends the streak",
+		},
+		{
+			places: [][]uint8{{1, 2, 3}, {3, 1, 2}, {2, 1}},
+			expected: entities.PlayerStats{
+				GamesPlayed:      3,
+				GamesWon:         1,
+				LongestWinStreak: 1,
+				MultiPlayerGames: 2,
+				PlaceSum:         4,
+			},
+			name: "places count in the games of more than two",
+		},
+		{
+			places: [][]uint8{{1, 2}, {0, 0}, {1, 2}},
+			expected: entities.PlayerStats{
+				GamesPlayed:      2,
+				GamesWon:         2,
+				CurrentWinStreak: 2,
+				LongestWinStreak: 2,
+			},
+			name: "an abandoned game keeps the streak",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			tally := newStatsTally(&entities.PlayerStats{PlayerID: statsPlayerOne}, nil)
+			for _, places := range tCase.places {
+				tally.add(statsGame(places...))
+			}
+			stats, _ := tally.result()
+
+			assert.Equal(tt, tCase.expected.GamesPlayed, stats.GamesPlayed)
+			assert.Equal(tt, tCase.expected.GamesWon, stats.GamesWon)
+			assert.Equal(tt, tCase.expected.CurrentWinStreak, stats.CurrentWinStreak)
+			assert.Equal(tt, tCase.expected.LongestWinStreak, stats.LongestWinStreak)
+			assert.Equal(tt, tCase.expected.MultiPlayerGames, stats.MultiPlayerGames)
+			assert.Equal(tt, tCase.expected.PlaceSum, stats.PlaceSum)
+		})
+	}
+}
+
+func TestStatsTallyAddsUp(t *testing.T) {
+	games := []*entities.Game{statsGame(1, 2, 3), statsGame(2, 1), statsGame(2, 2, 1)}
+	games[0].Moves = []entities.GameMove{
+		*entities.NewGameMove(games[0].ID, statsPlayerOne, 1, dictionary.ThrowRock),
+		*entities.NewGameMove(games[0].ID, statsPlayerTwo, 1, dictionary.ThrowPaper),
+	}
+
+	rebuilt := newStatsTally(&entities.PlayerStats{PlayerID: statsPlayerOne}, nil)
+	for _, game := range games {
+		rebuilt.add(game)
+	}
+	expectedStats, expectedOpponents := rebuilt.result()
+
+	// the games are added one by one to what the previous one left, as the stored stats are
+	stats, opponents := newStatsTally(&entities.PlayerStats{PlayerID: statsPlayerOne}, nil).result()
+	for _, game := range games {
+		tally := newStatsTally(stats, opponents)
+		tally.add(game)
+		stats, opponents = tally.result()
+	}
+
+	assert.Equal(t, expectedStats, stats)
+	assert.ElementsMatch(t, expectedOpponents, opponents)
+	assert.Equal(t, []entities.PlayerOpponentStats{
+		{PlayerID: statsPlayerOne, OpponentID: statsPlayerTwo, GamesPlayed: 3, Wins: 1, Losses: 1, Draws: 1},
+		{PlayerID: statsPlayerOne, OpponentID: statsPlayerThree, GamesPlayed: 2, Wins: 1, Losses: 1},
+	}, expectedOpponents)
+	for _, throw := range stats.Throws {
+		if throw.Throw == dictionary.ThrowRock {
+			assert.Equal(t, uint(1), throw.Count)
+		} else {
+			assert.Zero(t, throw.Count)
+		}
+	}
+}
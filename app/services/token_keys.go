@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"knb/app/config"
+	"knb/app/entities"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// hmacKeyId names the TOKEN_SIGNING_KEY secret, tokens issued before key ids have no kid and use it.
+	hmacKeyId  = "hs256"
+	keyFileExt = ".pem"
+)
+
+var errUnknownTokenKey = errors.New("token signing key is unknown")
+
+type tokenKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	retiredAt *time.Time
+}
+
+// tokenKeySet signs with the active key and verifies with every key that isn't past its grace period.
+type tokenKeySet struct {
+	active *tokenKey
+	keys   map[string]*tokenKey
+	grace  time.Duration
+}
+
+func newTokenKeySet(authConfig config.AuthConfig) (*tokenKeySet, error) {
+	keySet := &tokenKeySet{
+		keys:  make(map[string]*tokenKey),
+		grace: authConfig.TokenKeyGracePeriod,
+	}
+	keySet.keys[hmacKeyId] = &tokenKey{
+		id:        hmacKeyId,
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(authConfig.TokenSigningKey),
+		verifyKey: []byte(authConfig.TokenSigningKey),
+	}
+
+	if authConfig.TokenKeysDir != "" {
+		files, err := filepath.Glob(filepath.Join(authConfig.TokenKeysDir, "*"+keyFileExt))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			key, err := loadTokenKey(file)
+			if err != nil {
+				return nil, err
+			}
+			keySet.keys[key.id] = key
+		}
+	}
+
+	for keyId, retiredAt := range authConfig.TokenRetiredKeys {
+		key, ok := keySet.keys[keyId]
+		if !ok {
+			return nil, fmt.Errorf("retired token key %s is not found", keyId)
+		}
+		key.retiredAt = &retiredAt
+	}
+
+	activeKeyId := authConfig.TokenActiveKeyId
+	if activeKeyId == "" {
+		activeKeyId = hmacKeyId
+	}
+	active, ok := keySet.keys[activeKeyId]
+	if !ok {
+		return nil, fmt.Errorf("active token key %s is not found", activeKeyId)
+	}
+	if active.retiredAt != nil {
+		return nil, fmt.Errorf("active token key %s is retired", activeKeyId)
+	}
+	keySet.active = active
+
+	return keySet, nil
+}
+
+// loadTokenKey reads a PKCS #8 (RSA or Ed25519) or PKCS #1 (RSA) private key, the file name is the key id.
+func loadTokenKey(file string) (*tokenKey, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("token key %s is not a PEM file", file)
+	}
+
+	var privateKey interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("token key %s: %w", file, err)
+	}
+
+	key := &tokenKey{
+		id:      strings.TrimSuffix(filepath.Base(file), keyFileExt),
+		signKey: privateKey,
+	}
+	switch privateKey := privateKey.(type) {
+	case *rsa.PrivateKey:
+		key.method = jwt.SigningMethodRS256
+		key.verifyKey = &privateKey.PublicKey
+	case ed25519.PrivateKey:
+		key.method = jwt.SigningMethodEdDSA
+		key.verifyKey = privateKey.Public()
+	default:
+		return nil, fmt.Errorf("token key %s has unsupported type %T", file, privateKey)
+	}
+
+	return key, nil
+}
+
+func (k *tokenKey) usable(now time.Time, grace time.Duration) bool {
+	return k.retiredAt == nil || now.Before(k.retiredAt.Add(grace))
+}
+
+func (k *tokenKey) public() bool {
+	return k.method != jwt.SigningMethodHS256
+}
+
+// verificationKey finds the key of a token header, kid-less tokens were signed with the HMAC secret.
+func (s *tokenKeySet) verificationKey(token *jwt.Token, now time.Time) (interface{}, error) {
+	keyId, _ := token.Header["kid"].(string)
+	if keyId == "" {
+		keyId = hmacKeyId
+	}
+
+	key, ok := s.keys[keyId]
+	if !ok || !key.usable(now, s.grace) {
+		return nil, errUnknownTokenKey
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("invalid signed method")
+	}
+
+	return key.verifyKey, nil
+}
+
+// publicKeys are the asymmetric keys other services may verify player tokens with.
+func (s *tokenKeySet) publicKeys(now time.Time) []entities.TokenPublicKey {
+	publicKeys := make([]entities.TokenPublicKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		if !key.public() || !key.usable(now, s.grace) {
+			continue
+		}
+
+		publicKeys = append(publicKeys, entities.TokenPublicKey{
+			ID:        key.id,
+			Algorithm: key.method.Alg(),
+			Key:       key.verifyKey.(crypto.PublicKey),
+		})
+	}
+	sort.Slice(publicKeys, func(i, j int) bool {
+		return publicKeys[i].ID < publicKeys[j].ID
+	})
+
+	return publicKeys
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP as in RFC 6238 with the parameters every authenticator app supports: SHA-1, 6 digits, 30 seconds.
+const (
+	totpSecretBytes = 20
+	totpDigits      = 6
+	totpPeriod      = 30
+	// totpSkewSteps accepts the codes of the neighbouring periods for clock drift.
+	totpSkewSteps = 1
+
+	recoveryCodeBytes = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (s *securityService) GenerateTotpSecret() (string, error) {
+	secret := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TotpURI builds the otpauth:// URI the authenticator apps read from a QR code.
+func (s *securityService) TotpURI(account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", s.totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + s.totpIssuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// VerifyTotp checks the code against the periods around now and returns the matched time step,
+// the caller has to remember it so the same code can't be replayed.
+func (s *securityService) VerifyTotp(secret, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkewSteps; step <= current+totpSkewSteps; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func totpCode(key []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1_000_000)
+}
+
+// GenerateRecoveryCode returns a one-time code like "abcdefgh-ijklmnop" and its hash.
+func (s *securityService) GenerateRecoveryCode() (code, hash string, err error) {
+	secret := make([]byte, recoveryCodeBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	encoded := strings.ToLower(totpEncoding.EncodeToString(secret))
+	code = encoded[:len(encoded)/2] + "-" + encoded[len(encoded)/2:]
+
+	return code, s.HashRecoveryCode(code), nil
+}
+
+// HashRecoveryCode ignores the case and the separators, players type the codes by hand.
+func (s *securityService) HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	return sha256Hex(normalized)
+}
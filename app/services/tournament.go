@@ -0,0 +1,489 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"knb/app/repositories"
+	"log"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	minTournamentPlayers     = 2
+	defaultNoShowMinutes     = 10
+	maxTournamentNameLength  = 255
+	tournamentSeedingDefault = dictionary.TournamentSeedingRandom
+)
+
+type tournamentService struct {
+	tournamentRepository interfaces.RepositoryTournament
+	gameRepository       interfaces.GameRepository
+	playerRepository     interfaces.RepositoryPlayer
+	mu                   sync.Mutex
+}
+
+func newTournamentService(
+	tournamentRepository interfaces.RepositoryTournament,
+	gameRepository interfaces.GameRepository,
+	playerRepository interfaces.RepositoryPlayer,
+) *tournamentService {
+	return &tournamentService{
+		tournamentRepository: tournamentRepository,
+		gameRepository:       gameRepository,
+		playerRepository:     playerRepository,
+	}
+}
+
+func (t *tournamentService) Create(
+	ownerId uuid.UUID,
+	name string,
+	tournamentType dictionary.TournamentType,
+	seeding dictionary.TournamentSeeding,
+	noShowMinutes uint,
+	rounds uint,
+) (*entities.Tournament, error) {
+	if _, err := t.playerRepository.FindById(ownerId); err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+	if _, ok := newTournamentFormat(tournamentType); !ok {
+		return nil, customErrors.NewBadRequestError("tournament type is invalid")
+	}
+	if seeding == "" {
+		seeding = tournamentSeedingDefault
+	}
+	if seeding != dictionary.TournamentSeedingRandom && seeding != dictionary.TournamentSeedingPoints {
+		return nil, customErrors.NewBadRequestError("tournament seeding is invalid")
+	}
+	if name == "" || len(name) > maxTournamentNameLength {
+		return nil, customErrors.NewBadRequestError("tournament name is invalid")
+	}
+	if noShowMinutes == 0 {
+		noShowMinutes = defaultNoShowMinutes
+	}
+	if rounds != 0 && tournamentType != dictionary.TournamentTypeSwiss {
+		return nil, customErrors.NewBadRequestError("rounds can be set only for a swiss tournament")
+	}
+
+	tournament := entities.NewTournament(ownerId, name, tournamentType, seeding, noShowMinutes, rounds)
+	if err := t.tournamentRepository.Create(tournament); err != nil {
+		return nil, err
+	}
+
+	return tournament, nil
+}
+
+func (t *tournamentService) Find(tournamentId uuid.UUID) (*entities.Tournament, error) {
+	tournament, err := t.tournamentRepository.FindById(tournamentId)
+	if err != nil {
+		if err.Error() == repositories.RecordNotFoundError {
+			return nil, customErrors.NewNotFoundError(fmt.Sprintf("tournament with id %s not found", tournamentId))
+		}
+
+		return nil, err
+	}
+
+	return tournament, nil
+}
+
+func (t *tournamentService) Join(playerId, tournamentId uuid.UUID) (*entities.Tournament, error) {
+	if _, err := t.playerRepository.FindById(playerId); err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+	tournament, err := t.Find(tournamentId)
+	if err != nil {
+		return nil, err
+	}
+	if tournament.Status != dictionary.TournamentStatusRegistration {
+		return nil, customErrors.NewBadRequestError("tournament registration is closed")
+	}
+	for _, player := range tournament.Players {
+		if player.ID == playerId {
+			return nil, customErrors.NewBadRequestError("you already joined to this tournament")
+		}
+	}
+
+	if err := t.tournamentRepository.AddPlayer(tournament, playerId); err != nil {
+		return nil, err
+	}
+
+	return t.Find(tournamentId)
+}
+
+func (t *tournamentService) Start(playerId, tournamentId uuid.UUID) (*entities.Tournament, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tournament, err := t.Find(tournamentId)
+	if err != nil {
+		return nil, err
+	}
+	if tournament.OwnerID != playerId {
+		return nil, customErrors.NewForbiddenError("only the organiser can start the tournament")
+	}
+	if tournament.Status != dictionary.TournamentStatusRegistration {
+		return nil, customErrors.NewBadRequestError("the tournament has already started")
+	}
+	if len(tournament.Players) < minTournamentPlayers {
+		return nil, customErrors.NewBadRequestError("not enough players")
+	}
+
+	format, _ := newTournamentFormat(tournament.Type)
+	seeded := seedPlayers(tournament)
+	seeds := make(map[uuid.UUID]uint, len(seeded))
+	for index, playerId := range seeded {
+		seeds[playerId] = uint(index + 1)
+	}
+
+	if tournament.Type == dictionary.TournamentTypeSwiss {
+		if tournament.Rounds == 0 {
+			tournament.Rounds = swissRounds(len(seeded))
+		}
+		// players can't meet twice as long as there are fewer rounds than opponents
+		tournament.Rounds = min(tournament.Rounds, uint(len(seeded)-1))
+	}
+
+	matches := format.initialMatches(tournament, seeded)
+	if err := t.tournamentRepository.Start(tournament, seeds, matches); err != nil {
+		return nil, err
+	}
+
+	if err := t.resolve(tournamentId); err != nil {
+		return nil, err
+	}
+
+	return t.Find(tournamentId)
+}
+
+func (t *tournamentService) Standings(tournamentId uuid.UUID) ([]entities.TournamentStanding, error) {
+	tournament, err := t.Find(tournamentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.standings(tournament, tournamentMatches(tournament))
+}
+
+// GameFinished advances the winner of the tournament match played in the game.
+func (t *tournamentService) GameFinished(game *entities.Game) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	match, err := t.tournamentRepository.FindMatchByGame(game.ID)
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if !errors.As(err, &notFoundErr) {
+			log.Printf("Failed to find tournament match of game %s: %s\n", game.ID, err.Error())
+		}
+		return
+	}
+	if match.Status != dictionary.TournamentMatchStatusPlaying {
+		return
+	}
+
+	winners := make([]uuid.UUID, 0, 1)
+	var loser *uuid.UUID
+	for _, result := range game.Result {
+		if result.Place == 1 {
+			winners = append(winners, result.PlayerID)
+		} else {
+			playerId := result.PlayerID
+			loser = &playerId
+		}
+	}
+
+	if len(winners) != 1 {
+		tournament, err := t.Find(match.TournamentID)
+		if err != nil {
+			log.Printf("Failed to find tournament %s: %s\n", match.TournamentID, err.Error())
+			return
+		}
+
+		// league formats score a draw, eliminations need a winner
+		if format, _ := newTournamentFormat(tournament.Type); format.allowsDraws() {
+			err = t.finishMatch(match.TournamentID, match.ID, nil, nil)
+		} else {
+			err = t.startMatchGame(tournament, match)
+		}
+		if err != nil {
+			log.Printf("Failed to settle drawn tournament match %s: %s\n", match.ID, err.Error())
+		}
+		return
+	}
+
+	if err := t.finishMatch(match.TournamentID, match.ID, &winners[0], loser); err != nil {
+		log.Printf("Failed to advance tournament match %s: %s\n", match.ID, err.Error())
+	}
+}
+
+// CheckNoShows settles the matches whose game hasn't started before the deadline:
+// the player who is ready advances, when nobody is the match has no winner.
+func (t *tournamentService) CheckNoShows() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	matches, err := t.tournamentRepository.FindOverdueMatches(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, match := range matches {
+		if match.GameID == nil {
+			continue
+		}
+		if err := t.settleNoShow(match); err != nil {
+			log.Printf("Failed to settle no-show of tournament match %s: %s\n", match.ID, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// settleNoShow abandons the game of the overdue match and advances the player who showed up.
+func (t *tournamentService) settleNoShow(match entities.TournamentMatch) error {
+	game, err := t.gameRepository.FindById(*match.GameID)
+	if err != nil {
+		return err
+	}
+	if game.Status != dictionary.GameStatusWaiting {
+		return nil
+	}
+
+	gamePlayers, err := t.gameRepository.FindGamePlayers(game.ID)
+	if err != nil {
+		return err
+	}
+	ready := make([]uuid.UUID, 0, len(gamePlayers))
+	absent := make([]uuid.UUID, 0, len(gamePlayers))
+	for _, gamePlayer := range gamePlayers {
+		if gamePlayer.Ready {
+			ready = append(ready, gamePlayer.PlayerID)
+		} else {
+			absent = append(absent, gamePlayer.PlayerID)
+		}
+	}
+
+	var winner, loser *uuid.UUID
+	results := make([]entities.GameResult, 0, 2)
+	if len(ready) == 1 && len(absent) == 1 {
+		winner, loser = &ready[0], &absent[0]
+		results = append(
+			results,
+			entities.GameResult{GameID: game.ID, PlayerID: *winner, Place: 1},
+			entities.GameResult{GameID: game.ID, PlayerID: *loser, Place: 2},
+		)
+	}
+
+	if err := t.gameRepository.Abandon(game, results, nil); err != nil {
+		return err
+	}
+
+	return t.finishMatch(match.TournamentID, match.ID, winner, loser)
+}
+
+func (t *tournamentService) finishMatch(tournamentId, matchId uuid.UUID, winner, loser *uuid.UUID) error {
+	tournament, err := t.Find(tournamentId)
+	if err != nil {
+		return err
+	}
+
+	matches := tournamentMatches(tournament)
+	for _, match := range matches {
+		if match.ID == matchId {
+			if err := t.completeMatch(match, matches, winner, loser); err != nil {
+				return err
+			}
+		}
+	}
+
+	return t.resolveMatches(tournament, matches)
+}
+
+func (t *tournamentService) resolve(tournamentId uuid.UUID) error {
+	tournament, err := t.Find(tournamentId)
+	if err != nil {
+		return err
+	}
+
+	return t.resolveMatches(tournament, tournamentMatches(tournament))
+}
+
+// resolveMatches starts the games of every playable match, settles byes and empty matches,
+// asks the format for the next round and finishes the tournament once nothing is left to play.
+func (t *tournamentService) resolveMatches(tournament *entities.Tournament, matches []*entities.TournamentMatch) error {
+	if tournament.Status != dictionary.TournamentStatusRunning {
+		return nil
+	}
+	format, _ := newTournamentFormat(tournament.Type)
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, match := range matches {
+			if match.Status != dictionary.TournamentMatchStatusPending || !format.playable(match, matches) {
+				continue
+			}
+
+			changed = true
+			players := match.Players()
+			switch len(players) {
+			case 2:
+				if err := t.startMatchGame(tournament, match); err != nil {
+					return err
+				}
+			case 1:
+				if err := t.completeMatch(match, matches, &players[0], nil); err != nil {
+					return err
+				}
+			default:
+				if err := t.completeMatch(match, matches, nil, nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		if changed {
+			continue
+		}
+		for _, match := range matches {
+			if match.Status != dictionary.TournamentMatchStatusFinished {
+				return nil
+			}
+		}
+
+		standings, err := t.standings(tournament, matches)
+		if err != nil {
+			return err
+		}
+		next := format.nextMatches(tournament, matches, standings)
+		if len(next) == 0 {
+			return t.tournamentRepository.Finish(tournament, format.winner(tournament, matches, standings))
+		}
+		if err := t.tournamentRepository.CreateMatches(next); err != nil {
+			return err
+		}
+		matches = append(matches, next...)
+		changed = true
+	}
+
+	return nil
+}
+
+func (t *tournamentService) completeMatch(
+	match *entities.TournamentMatch,
+	matches []*entities.TournamentMatch,
+	winner, loser *uuid.UUID,
+) error {
+	match.Status = dictionary.TournamentMatchStatusFinished
+	match.WinnerID = winner
+	match.LoserID = loser
+	if err := t.tournamentRepository.SaveMatch(match); err != nil {
+		return err
+	}
+
+	for _, next := range matches {
+		changed := false
+		if winner != nil && isLinked(match.NextMatchID, next.ID) {
+			next.SetPlayer(match.NextSlot, *winner)
+			changed = true
+		}
+		if loser != nil && isLinked(match.LoserNextMatchID, next.ID) {
+			next.SetPlayer(match.LoserNextSlot, *loser)
+			changed = true
+		}
+		if changed {
+			if err := t.tournamentRepository.SaveMatch(next); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *tournamentService) startMatchGame(tournament *entities.Tournament, match *entities.TournamentMatch) error {
+	game, err := t.gameRepository.CreateGame(match.Players()...)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(time.Duration(tournament.NoShowMinutes) * time.Minute)
+	match.GameID = &game.ID
+	match.Deadline = &deadline
+	match.Status = dictionary.TournamentMatchStatusPlaying
+
+	return t.tournamentRepository.SaveMatch(match)
+}
+
+// standings are computed from the results of the games played in the tournament matches.
+func (t *tournamentService) standings(
+	tournament *entities.Tournament,
+	matches []*entities.TournamentMatch,
+) ([]entities.TournamentStanding, error) {
+	tournamentPlayers, err := t.tournamentRepository.FindTournamentPlayers(tournament.ID)
+	if err != nil {
+		return nil, err
+	}
+	seeds := make(map[uuid.UUID]uint, len(tournamentPlayers))
+	for _, tournamentPlayer := range tournamentPlayers {
+		seeds[tournamentPlayer.PlayerID] = tournamentPlayer.Seed
+	}
+
+	gameIds := make([]uuid.UUID, 0, len(matches))
+	for _, match := range matches {
+		if match.GameID != nil {
+			gameIds = append(gameIds, *match.GameID)
+		}
+	}
+	results, err := t.gameRepository.FindResults(gameIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return computeStandings(seeds, matches, results), nil
+}
+
+func seedPlayers(tournament *entities.Tournament) []uuid.UUID {
+	players := append([]entities.Player(nil), tournament.Players...)
+	if tournament.Seeding == dictionary.TournamentSeedingPoints {
+		sort.SliceStable(players, func(i, j int) bool {
+			return players[i].Points > players[j].Points
+		})
+	} else {
+		rand.Shuffle(len(players), func(i, j int) {
+			players[i], players[j] = players[j], players[i]
+		})
+	}
+
+	seeded := make([]uuid.UUID, 0, len(players))
+	for _, player := range players {
+		seeded = append(seeded, player.ID)
+	}
+
+	return seeded
+}
+
+func tournamentMatches(tournament *entities.Tournament) []*entities.TournamentMatch {
+	matches := make([]*entities.TournamentMatch, 0, len(tournament.Matches))
+	for index := range tournament.Matches {
+		matches = append(matches, &tournament.Matches[index])
+	}
+
+	return matches
+}
+
+func unauthorizedIfNotFound(err error) error {
+	var notFoundErr *customErrors.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return customErrors.NewWrongLoginError("Unauthorized")
+	}
+
+	return err
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+)
+
+type tournamentFormat interface {
+	// initialMatches builds the matches known at the start from players ordered by seed.
+	initialMatches(tournament *entities.Tournament, seeded []uuid.UUID) []*entities.TournamentMatch
+	// playable reports whether a pending match may get its game.
+	playable(match *entities.TournamentMatch, matches []*entities.TournamentMatch) bool
+	// allowsDraws tells whether a drawn game settles the match or has to be replayed.
+	allowsDraws() bool
+	// nextMatches is called once every known match is finished and returns the next round, if any.
+	nextMatches(
+		tournament *entities.Tournament,
+		matches []*entities.TournamentMatch,
+		standings []entities.TournamentStanding,
+	) []*entities.TournamentMatch
+	winner(
+		tournament *entities.Tournament,
+		matches []*entities.TournamentMatch,
+		standings []entities.TournamentStanding,
+	) *uuid.UUID
+}
+
+func newTournamentFormat(tournamentType dictionary.TournamentType) (tournamentFormat, bool) {
+	switch tournamentType {
+	case dictionary.TournamentTypeSingleElimination:
+		return &eliminationFormat{double: false}, true
+	case dictionary.TournamentTypeDoubleElimination:
+		return &eliminationFormat{double: true}, true
+	case dictionary.TournamentTypeRoundRobin:
+		return &roundRobinFormat{}, true
+	case dictionary.TournamentTypeSwiss:
+		return &swissFormat{}, true
+	}
+
+	return nil, false
+}
+
+type eliminationFormat struct {
+	double bool
+}
+
+func (f *eliminationFormat) initialMatches(
+	tournament *entities.Tournament,
+	seeded []uuid.UUID,
+) []*entities.TournamentMatch {
+	size, rounds := 1, 0
+	for size < len(seeded) {
+		size *= 2
+		rounds++
+	}
+
+	matches := make([]*entities.TournamentMatch, 0, 2*size)
+	upper := make([][]*entities.TournamentMatch, rounds+1)
+	for round := 1; round <= rounds; round++ {
+		for position := 0; position < size>>round; position++ {
+			match := entities.NewTournamentMatch(
+				tournament.ID, dictionary.TournamentBracketUpper, uint(round), uint(position),
+			)
+			upper[round] = append(upper[round], match)
+			matches = append(matches, match)
+		}
+	}
+	for round := 1; round < rounds; round++ {
+		for position, match := range upper[round] {
+			linkWinner(match, upper[round+1][position/2], uint8(position%2+1))
+		}
+	}
+
+	for index, seed := range seedOrder(size) {
+		if seed <= len(seeded) {
+			upper[1][index/2].SetPlayer(uint8(index%2+1), seeded[seed-1])
+		}
+	}
+
+	if !f.double {
+		return matches
+	}
+
+	// the lower bracket alternates rounds between its own survivors and the losers dropping from the upper one
+	lowerRounds := 2 * (rounds - 1)
+	lower := make([][]*entities.TournamentMatch, lowerRounds+1)
+	for round := 1; round <= lowerRounds; round++ {
+		for position := 0; position < size>>((round+1)/2+1); position++ {
+			match := entities.NewTournamentMatch(
+				tournament.ID, dictionary.TournamentBracketLower, uint(round), uint(position),
+			)
+			lower[round] = append(lower[round], match)
+			matches = append(matches, match)
+		}
+	}
+	for round := 1; round < lowerRounds; round++ {
+		for position, match := range lower[round] {
+			if round%2 == 1 {
+				linkWinner(match, lower[round+1][position], 1)
+			} else {
+				linkWinner(match, lower[round+1][position/2], uint8(position%2+1))
+			}
+		}
+	}
+	for round := 1; round <= rounds && lowerRounds > 0; round++ {
+		for position, match := range upper[round] {
+			if round == 1 {
+				linkLoser(match, lower[1][position/2], uint8(position%2+1))
+			} else {
+				linkLoser(match, lower[2*(round-1)][position], 2)
+			}
+		}
+	}
+
+	final := entities.NewTournamentMatch(tournament.ID, dictionary.TournamentBracketFinal, 1, 0)
+	matches = append(matches, final)
+	linkWinner(upper[rounds][0], final, 1)
+	if lowerRounds > 0 {
+		linkWinner(lower[lowerRounds][0], final, 2)
+	} else {
+		linkLoser(upper[rounds][0], final, 2)
+	}
+
+	return matches
+}
+
+func (f *eliminationFormat) playable(match *entities.TournamentMatch, matches []*entities.TournamentMatch) bool {
+	for _, feeder := range matches {
+		if feeder.Status == dictionary.TournamentMatchStatusFinished {
+			continue
+		}
+		if isLinked(feeder.NextMatchID, match.ID) || isLinked(feeder.LoserNextMatchID, match.ID) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *eliminationFormat) allowsDraws() bool {
+	return false
+}
+
+func (f *eliminationFormat) nextMatches(
+	_ *entities.Tournament,
+	_ []*entities.TournamentMatch,
+	_ []entities.TournamentStanding,
+) []*entities.TournamentMatch {
+	return nil
+}
+
+func (f *eliminationFormat) winner(
+	_ *entities.Tournament,
+	matches []*entities.TournamentMatch,
+	_ []entities.TournamentStanding,
+) *uuid.UUID {
+	for _, match := range matches {
+		if match.NextMatchID == nil && match.Bracket != dictionary.TournamentBracketLower {
+			if f.double == (match.Bracket == dictionary.TournamentBracketFinal) {
+				return match.WinnerID
+			}
+		}
+	}
+
+	return nil
+}
+
+// seedOrder places seeds so that the top seeds meet as late as possible: 1-8, 4-5, 2-7, 3-6 for eight slots.
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+
+	return order
+}
+
+func linkWinner(match, next *entities.TournamentMatch, slot uint8) {
+	match.NextMatchID = &next.ID
+	match.NextSlot = slot
+}
+
+func linkLoser(match, next *entities.TournamentMatch, slot uint8) {
+	match.LoserNextMatchID = &next.ID
+	match.LoserNextSlot = slot
+}
+
+func isLinked(link *uuid.UUID, matchId uuid.UUID) bool {
+	return link != nil && *link == matchId
+}
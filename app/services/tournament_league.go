@@ -0,0 +1,219 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"math/bits"
+)
+
+// roundRobinFormat schedules everyone against everyone with the circle method, one round at a time.
+type roundRobinFormat struct{}
+
+func (f *roundRobinFormat) initialMatches(
+	tournament *entities.Tournament,
+	seeded []uuid.UUID,
+) []*entities.TournamentMatch {
+	circle := append([]uuid.UUID(nil), seeded...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, uuid.Nil)
+	}
+
+	matches := make([]*entities.TournamentMatch, 0, len(circle)*len(circle)/2)
+	for round := 1; round < len(circle); round++ {
+		for position := 0; position < len(circle)/2; position++ {
+			home, away := circle[position], circle[len(circle)-1-position]
+			if home == uuid.Nil || away == uuid.Nil {
+				continue
+			}
+
+			match := entities.NewTournamentMatch(
+				tournament.ID, dictionary.TournamentBracketLeague, uint(round), uint(position),
+			)
+			match.SetPlayer(1, home)
+			match.SetPlayer(2, away)
+			matches = append(matches, match)
+		}
+
+		last := circle[len(circle)-1]
+		copy(circle[2:], circle[1:len(circle)-1])
+		circle[1] = last
+	}
+
+	return matches
+}
+
+func (f *roundRobinFormat) playable(match *entities.TournamentMatch, matches []*entities.TournamentMatch) bool {
+	return previousRoundsFinished(match, matches)
+}
+
+func (f *roundRobinFormat) allowsDraws() bool {
+	return true
+}
+
+func (f *roundRobinFormat) nextMatches(
+	_ *entities.Tournament,
+	_ []*entities.TournamentMatch,
+	_ []entities.TournamentStanding,
+) []*entities.TournamentMatch {
+	return nil
+}
+
+func (f *roundRobinFormat) winner(
+	_ *entities.Tournament,
+	_ []*entities.TournamentMatch,
+	standings []entities.TournamentStanding,
+) *uuid.UUID {
+	return standingsLeader(standings)
+}
+
+// swissFormat pairs players with equal scores round by round and never lets the same pair meet twice
+// unless there is no other way to complete the round.
+type swissFormat struct{}
+
+func swissRounds(players int) uint {
+	return uint(bits.Len(uint(players - 1)))
+}
+
+func (f *swissFormat) initialMatches(
+	tournament *entities.Tournament,
+	seeded []uuid.UUID,
+) []*entities.TournamentMatch {
+	half := (len(seeded) + 1) / 2
+	matches := make([]*entities.TournamentMatch, 0, half)
+	for position := 0; position < half; position++ {
+		match := entities.NewTournamentMatch(
+			tournament.ID, dictionary.TournamentBracketLeague, 1, uint(position),
+		)
+		match.SetPlayer(1, seeded[position])
+		if position+half < len(seeded) {
+			match.SetPlayer(2, seeded[position+half])
+		}
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+func (f *swissFormat) playable(match *entities.TournamentMatch, matches []*entities.TournamentMatch) bool {
+	return previousRoundsFinished(match, matches)
+}
+
+func (f *swissFormat) allowsDraws() bool {
+	return true
+}
+
+func (f *swissFormat) nextMatches(
+	tournament *entities.Tournament,
+	matches []*entities.TournamentMatch,
+	standings []entities.TournamentStanding,
+) []*entities.TournamentMatch {
+	round := uint(0)
+	met := make(map[[2]uuid.UUID]bool)
+	hadBye := make(map[uuid.UUID]bool)
+	for _, match := range matches {
+		round = max(round, match.Round)
+		players := match.Players()
+		if len(players) == 2 {
+			met[[2]uuid.UUID{players[0], players[1]}] = true
+			met[[2]uuid.UUID{players[1], players[0]}] = true
+		} else if len(players) == 1 {
+			hadBye[players[0]] = true
+		}
+	}
+	if round >= tournament.Rounds {
+		return nil
+	}
+
+	ranked := make([]uuid.UUID, 0, len(standings))
+	for _, standing := range standings {
+		ranked = append(ranked, standing.PlayerID)
+	}
+
+	var bye *uuid.UUID
+	if len(ranked)%2 == 1 {
+		index := len(ranked) - 1
+		for candidate := len(ranked) - 1; candidate >= 0; candidate-- {
+			if !hadBye[ranked[candidate]] {
+				index = candidate
+				break
+			}
+		}
+		bye = &ranked[index]
+		ranked = append(append([]uuid.UUID(nil), ranked[:index]...), ranked[index+1:]...)
+	}
+
+	pairs, ok := swissPairs(ranked, met, false)
+	if !ok {
+		pairs, _ = swissPairs(ranked, met, true)
+	}
+
+	next := make([]*entities.TournamentMatch, 0, len(pairs)+1)
+	for position, pair := range pairs {
+		match := entities.NewTournamentMatch(
+			tournament.ID, dictionary.TournamentBracketLeague, round+1, uint(position),
+		)
+		match.SetPlayer(1, pair[0])
+		match.SetPlayer(2, pair[1])
+		next = append(next, match)
+	}
+	if bye != nil {
+		match := entities.NewTournamentMatch(
+			tournament.ID, dictionary.TournamentBracketLeague, round+1, uint(len(pairs)),
+		)
+		match.SetPlayer(1, *bye)
+		next = append(next, match)
+	}
+
+	return next
+}
+
+func (f *swissFormat) winner(
+	_ *entities.Tournament,
+	_ []*entities.TournamentMatch,
+	standings []entities.TournamentStanding,
+) *uuid.UUID {
+	return standingsLeader(standings)
+}
+
+// swissPairs pairs every player with the highest ranked opponent available, backtracking on rematches.
+func swissPairs(ranked []uuid.UUID, met map[[2]uuid.UUID]bool, allowRematch bool) ([][2]uuid.UUID, bool) {
+	if len(ranked) == 0 {
+		return nil, true
+	}
+
+	player := ranked[0]
+	for index := 1; index < len(ranked); index++ {
+		opponent := ranked[index]
+		if !allowRematch && met[[2]uuid.UUID{player, opponent}] {
+			continue
+		}
+
+		rest := make([]uuid.UUID, 0, len(ranked)-2)
+		rest = append(rest, ranked[1:index]...)
+		rest = append(rest, ranked[index+1:]...)
+		if pairs, ok := swissPairs(rest, met, allowRematch); ok {
+			return append([][2]uuid.UUID{{player, opponent}}, pairs...), true
+		}
+	}
+
+	return nil, false
+}
+
+func previousRoundsFinished(match *entities.TournamentMatch, matches []*entities.TournamentMatch) bool {
+	for _, other := range matches {
+		if other.Round < match.Round && other.Status != dictionary.TournamentMatchStatusFinished {
+			return false
+		}
+	}
+
+	return true
+}
+
+func standingsLeader(standings []entities.TournamentStanding) *uuid.UUID {
+	if len(standings) == 0 || standings[0].Played+standings[0].Byes == 0 {
+		return nil
+	}
+
+	return &standings[0].PlayerID
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"knb/app/entities"
+	"sort"
+)
+
+const (
+	scoreWin  = 1.0
+	scoreDraw = 0.5
+)
+
+type matchOutcome struct {
+	players []uuid.UUID
+	winner  *uuid.UUID
+	draw    bool
+}
+
+// computeStandings ranks players by score, Buchholz (sum of the opponents' scores),
+// the score of the games between the tied players and finally by seed.
+func computeStandings(
+	seeds map[uuid.UUID]uint,
+	matches []*entities.TournamentMatch,
+	results []entities.GameResult,
+) []entities.TournamentStanding {
+	gameResults := make(map[uuid.UUID][]entities.GameResult)
+	for _, result := range results {
+		gameResults[result.GameID] = append(gameResults[result.GameID], result)
+	}
+
+	standings := make(map[uuid.UUID]*entities.TournamentStanding, len(seeds))
+	for playerId, seed := range seeds {
+		standings[playerId] = &entities.TournamentStanding{PlayerID: playerId, Seed: seed}
+	}
+
+	outcomes := make([]matchOutcome, 0, len(matches))
+	for _, match := range matches {
+		players := match.Players()
+		if match.GameID == nil {
+			if len(players) == 1 && match.WinnerID != nil {
+				if standing, ok := standings[players[0]]; ok {
+					standing.Byes++
+					standing.Score += scoreWin
+				}
+			}
+			continue
+		}
+
+		outcome, ok := gameOutcome(players, gameResults[*match.GameID])
+		if !ok {
+			continue
+		}
+		outcomes = append(outcomes, outcome)
+
+		for _, playerId := range players {
+			standing, ok := standings[playerId]
+			if !ok {
+				continue
+			}
+			standing.Played++
+			for _, opponentId := range players {
+				if opponentId != playerId {
+					standing.Opponents = append(standing.Opponents, opponentId)
+				}
+			}
+
+			switch {
+			case outcome.draw:
+				standing.Draws++
+				standing.Score += scoreDraw
+			case *outcome.winner == playerId:
+				standing.Wins++
+				standing.Score += scoreWin
+			default:
+				standing.Losses++
+			}
+		}
+	}
+
+	for _, standing := range standings {
+		for _, opponentId := range standing.Opponents {
+			if opponent, ok := standings[opponentId]; ok {
+				standing.Buchholz += opponent.Score
+			}
+		}
+	}
+
+	ranked := make([]entities.TournamentStanding, 0, len(standings))
+	for _, standing := range standings {
+		ranked = append(ranked, *standing)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		if ranked[i].Buchholz != ranked[j].Buchholz {
+			return ranked[i].Buchholz > ranked[j].Buchholz
+		}
+		return ranked[i].Seed < ranked[j].Seed
+	})
+
+	applyHeadToHead(ranked, outcomes)
+
+	return ranked
+}
+
+// applyHeadToHead reorders every group tied on score and Buchholz by the points scored in their mutual games.
+func applyHeadToHead(ranked []entities.TournamentStanding, outcomes []matchOutcome) {
+	for start := 0; start < len(ranked); {
+		end := start + 1
+		for end < len(ranked) &&
+			ranked[end].Score == ranked[start].Score &&
+			ranked[end].Buchholz == ranked[start].Buchholz {
+			end++
+		}
+
+		if end-start > 1 {
+			tied := make(map[uuid.UUID]bool, end-start)
+			for _, standing := range ranked[start:end] {
+				tied[standing.PlayerID] = true
+			}
+
+			points := make(map[uuid.UUID]float64, end-start)
+			for _, outcome := range outcomes {
+				if len(outcome.players) != 2 || !tied[outcome.players[0]] || !tied[outcome.players[1]] {
+					continue
+				}
+				if outcome.draw {
+					points[outcome.players[0]] += scoreDraw
+					points[outcome.players[1]] += scoreDraw
+				} else {
+					points[*outcome.winner] += scoreWin
+				}
+			}
+
+			group := ranked[start:end]
+			for index := range group {
+				group[index].HeadToHead = points[group[index].PlayerID]
+			}
+			sort.SliceStable(group, func(i, j int) bool {
+				return group[i].HeadToHead > group[j].HeadToHead
+			})
+		}
+
+		start = end
+	}
+}
+
+func gameOutcome(players []uuid.UUID, results []entities.GameResult) (matchOutcome, bool) {
+	if len(players) != 2 || len(results) == 0 {
+		return matchOutcome{}, false
+	}
+
+	winners := make([]uuid.UUID, 0, len(results))
+	for _, result := range results {
+		if result.Place == 1 {
+			winners = append(winners, result.PlayerID)
+		}
+	}
+
+	switch len(winners) {
+	case 1:
+		return matchOutcome{players: players, winner: &winners[0]}, true
+	case 0:
+		return matchOutcome{}, false
+	}
+
+	return matchOutcome{players: players, draw: true}, true
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"knb/app/entities"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"strings"
+	"time"
+)
+
+const recoveryCodesCount = 10
+
+type twoFactorService struct {
+	twoFactorRepository interfaces.RepositoryTwoFactor
+	playerRepository    interfaces.RepositoryPlayer
+	security            interfaces.ServiceSecurity
+}
+
+func newTwoFactorService(
+	twoFactorRepository interfaces.RepositoryTwoFactor,
+	playerRepository interfaces.RepositoryPlayer,
+	security interfaces.ServiceSecurity,
+) *twoFactorService {
+	return &twoFactorService{
+		twoFactorRepository,
+		playerRepository,
+		security,
+	}
+}
+
+func (t *twoFactorService) Status(playerId uuid.UUID) (bool, int64, error) {
+	twoFactor, err := t.find(playerId)
+	if err != nil {
+		return false, 0, err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return false, 0, nil
+	}
+
+	recoveryCodesLeft, err := t.twoFactorRepository.CountRecoveryCodes(twoFactor.PlayerID)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return true, recoveryCodesLeft, nil
+}
+
+// Enroll generates a new secret, it protects the logins only after Confirm proves the app has it.
+func (t *twoFactorService) Enroll(playerId uuid.UUID) (*entities.TwoFactorEnrollment, error) {
+	player, err := t.playerRepository.FindById(playerId)
+	if err != nil {
+		return nil, unauthorizedIfNotFound(err)
+	}
+	if player.IsBot {
+		return nil, customErrors.NewBadRequestError("bots can't use two-factor authentication")
+	}
+
+	twoFactor, err := t.find(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.IsEnabled() {
+		return nil, customErrors.NewBadRequestError("two-factor authentication is already enabled")
+	}
+
+	secret, err := t.security.GenerateTotpSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.twoFactorRepository.SavePending(entities.NewTwoFactor(playerId, secret)); err != nil {
+		return nil, err
+	}
+
+	return &entities.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    t.security.TotpURI(player.Email, secret),
+	}, nil
+}
+
+func (t *twoFactorService) Confirm(playerId uuid.UUID, code string) ([]string, error) {
+	twoFactor, err := t.find(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, customErrors.NewBadRequestError("two-factor authentication enrollment was not started")
+	}
+	if twoFactor.IsEnabled() {
+		return nil, customErrors.NewBadRequestError("two-factor authentication is already enabled")
+	}
+
+	step, valid := t.security.VerifyTotp(twoFactor.Secret, normalizeCode(code), time.Now())
+	if !valid {
+		return nil, customErrors.NewBadRequestError("code is incorrect")
+	}
+
+	codes, recoveryCodes, err := t.generateRecoveryCodes(playerId)
+	if err != nil {
+		return nil, err
+	}
+	confirmed, err := t.twoFactorRepository.Confirm(playerId, step, recoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, customErrors.NewBadRequestError("code is incorrect")
+	}
+
+	return codes, nil
+}
+
+func (t *twoFactorService) Disable(playerId uuid.UUID, code string) error {
+	twoFactor, err := t.findEnabled(playerId)
+	if err != nil {
+		return err
+	}
+	if err := t.checkCode(twoFactor, code, customErrors.NewBadRequestError("code is incorrect")); err != nil {
+		return err
+	}
+
+	return t.twoFactorRepository.Delete(playerId)
+}
+
+func (t *twoFactorService) RegenerateRecoveryCodes(playerId uuid.UUID, code string) ([]string, error) {
+	twoFactor, err := t.findEnabled(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.checkCode(twoFactor, code, customErrors.NewBadRequestError("code is incorrect")); err != nil {
+		return nil, err
+	}
+
+	codes, recoveryCodes, err := t.generateRecoveryCodes(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.twoFactorRepository.ReplaceRecoveryCodes(playerId, recoveryCodes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Challenge returns nil when the player logs in with the password alone.
+func (t *twoFactorService) Challenge(playerId uuid.UUID) (*entities.LoginChallenge, error) {
+	twoFactor, err := t.find(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return nil, nil
+	}
+
+	challengeToken, expiresAt, err := t.security.GenerateLoginChallengeToken(playerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.LoginChallenge{Token: challengeToken, ExpiresAt: expiresAt}, nil
+}
+
+// CompleteChallenge checks the second factor of a login, a TOTP or a recovery code.
+func (t *twoFactorService) CompleteChallenge(challengeToken, code string) (uuid.UUID, error) {
+	playerId, err := t.security.ParseLoginChallengeToken(challengeToken)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	twoFactor, err := t.find(playerId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return uuid.Nil, customErrors.NewWrongLoginError("Unauthorized")
+	}
+	if err := t.checkCode(twoFactor, code, customErrors.NewWrongLoginError("Code is incorrect")); err != nil {
+		return uuid.Nil, err
+	}
+
+	return playerId, nil
+}
+
+// checkCode accepts a TOTP code once or an unused recovery code, otherwise it returns the given error.
+func (t *twoFactorService) checkCode(twoFactor *entities.TwoFactor, code string, invalidErr error) error {
+	code = normalizeCode(code)
+
+	var accepted bool
+	var err error
+	if step, valid := t.security.VerifyTotp(twoFactor.Secret, code, time.Now()); valid {
+		accepted, err = t.twoFactorRepository.UseStep(twoFactor.PlayerID, step)
+	} else {
+		accepted, err = t.twoFactorRepository.UseRecoveryCode(twoFactor.PlayerID, t.security.HashRecoveryCode(code))
+	}
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return invalidErr
+	}
+
+	return nil
+}
+
+func (t *twoFactorService) generateRecoveryCodes(playerId uuid.UUID) ([]string, []*entities.RecoveryCode, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	recoveryCodes := make([]*entities.RecoveryCode, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, codeHash, err := t.security.GenerateRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, code)
+		recoveryCodes = append(recoveryCodes, entities.NewRecoveryCode(playerId, codeHash))
+	}
+
+	return codes, recoveryCodes, nil
+}
+
+// find returns nil when the player has never started the enrollment.
+func (t *twoFactorService) find(playerId uuid.UUID) (*entities.TwoFactor, error) {
+	twoFactor, err := t.twoFactorRepository.Find(playerId)
+	if err != nil {
+		var notFoundErr *customErrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return twoFactor, nil
+}
+
+func (t *twoFactorService) findEnabled(playerId uuid.UUID) (*entities.TwoFactor, error) {
+	twoFactor, err := t.find(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return nil, customErrors.NewBadRequestError("two-factor authentication is not enabled")
+	}
+
+	return twoFactor, nil
+}
+
+func normalizeCode(code string) string {
+	return strings.TrimSpace(strings.ReplaceAll(code, " ", ""))
+}
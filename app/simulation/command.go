@@ -0,0 +1,104 @@
+package simulation
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const CommandName = "simulate"
+
+// Run executes `knb simulate` and returns the process exit code.
+func Run(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	flags.SetOutput(out)
+	games := flags.Int("games", 1000, "number of games to play")
+	players := flags.String("players", "markov,random", "comma-separated strategies, one per seat")
+	seed := flags.Uint64("seed", 1, "random seed, the same seed replays the same games")
+	prizes := flags.String("prizes", "", "comma-separated prize per place, e.g. 10,5")
+	plugins := flags.String("plugins", "", "comma-separated paths of strategy plugins")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	for _, path := range splitList(*plugins) {
+		if err := loadPlugin(path); err != nil {
+			_, _ = fmt.Fprintf(out, "Failed to load plugin %s: %s\n", path, err.Error())
+			return 1
+		}
+	}
+
+	prizeTable, err := parsePrizes(*prizes)
+	if err != nil {
+		_, _ = fmt.Fprintln(out, err.Error())
+		return 2
+	}
+
+	strategies := make([]dictionary.BotStrategy, 0)
+	for _, name := range splitList(*players) {
+		strategies = append(strategies, dictionary.BotStrategy(name))
+	}
+
+	report, err := Simulate(Options{
+		Games:      *games,
+		Strategies: strategies,
+		Seed:       *seed,
+		Prizes:     prizeTable,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Simulation failed: %s\n", err.Error())
+		return 1
+	}
+
+	printReport(out, report)
+
+	return 0
+}
+
+func printReport(out io.Writer, report *Report) {
+	_, _ = fmt.Fprintf(
+		out, "Games: %d, average rounds per game: %.2f\n\n",
+		report.Games, float64(report.Rounds)/float64(report.Games),
+	)
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "Seat\tStrategy\tWins\tDraws\tLosses\tWin rate\t95% CI\tAvg place\tAvg points")
+	for _, seat := range report.Seats {
+		low, high := seat.WinRateInterval()
+		_, _ = fmt.Fprintf(
+			writer, "%d\t%s\t%d\t%d\t%d\t%.1f%%\t%.1f%%-%.1f%%\t%.2f\t%.2f\n",
+			seat.Seat, seat.Strategy, seat.Wins, seat.Draws, seat.Losses,
+			seat.WinRate()*100, low*100, high*100, seat.AveragePlace(), seat.AveragePoints(),
+		)
+	}
+	_ = writer.Flush()
+}
+
+func parsePrizes(value string) ([]entities.GamePrize, error) {
+	prizes := make([]entities.GamePrize, 0)
+	for place, prizeValue := range splitList(value) {
+		prize, err := strconv.ParseUint(prizeValue, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("prize %q is invalid", prizeValue)
+		}
+		prizes = append(prizes, entities.GamePrize{Place: uint8(place + 1), Prize: uint(prize)})
+	}
+
+	return prizes, nil
+}
+
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,34 @@
+package simulation
+
+import (
+	"fmt"
+	"knb/app/bots"
+	"knb/app/interfaces"
+	"math/rand/v2"
+	"plugin"
+)
+
+const pluginSymbol = "NewStrategy"
+
+// loadPlugin registers a strategy built with `go build -buildmode=plugin`
+// that exports `func NewStrategy(rnd *rand.Rand) interfaces.BotStrategy`.
+func loadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return err
+	}
+
+	symbol, err := p.Lookup(pluginSymbol)
+	if err != nil {
+		return err
+	}
+
+	factory, ok := symbol.(func(rnd *rand.Rand) interfaces.BotStrategy)
+	if !ok {
+		return fmt.Errorf("%s has an unexpected signature", pluginSymbol)
+	}
+
+	bots.Register(factory(rand.New(rand.NewPCG(0, 0))).Name(), factory)
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package simulation
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"knb/app/bots"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"knb/app/interfaces"
+	"knb/app/rules"
+	"math"
+	"math/rand/v2"
+)
+
+const confidenceZ = 1.96
+
+type Options struct {
+	Games      int
+	Strategies []dictionary.BotStrategy
+	Seed       uint64
+	Prizes     []entities.GamePrize
+}
+
+type SeatReport struct {
+	Seat     int
+	Strategy dictionary.BotStrategy
+	Games    int
+	Wins     int
+	Draws    int
+	Losses   int
+	PlaceSum int
+	Points   uint
+}
+
+type Report struct {
+	Games  int
+	Rounds int
+	Seats  []SeatReport
+}
+
+// Simulate plays the games in memory with the same rules and settlement the game service uses.
+func Simulate(options Options) (*Report, error) {
+	if options.Games < 1 {
+		return nil, fmt.Errorf("games count must be positive")
+	}
+	if len(options.Strategies) < rules.MinPlayers {
+		return nil, fmt.Errorf("at least %d strategies are required", rules.MinPlayers)
+	}
+
+	report := &Report{
+		Games: options.Games,
+		Seats: make([]SeatReport, len(options.Strategies)),
+	}
+	for seat, strategy := range options.Strategies {
+		report.Seats[seat] = SeatReport{Seat: seat + 1, Strategy: strategy}
+	}
+
+	for gameNumber := 0; gameNumber < options.Games; gameNumber++ {
+		rnd := rand.New(rand.NewPCG(options.Seed, uint64(gameNumber)))
+
+		seats := make([]uuid.UUID, len(options.Strategies))
+		strategies := make(map[uuid.UUID]interfaces.BotStrategy, len(options.Strategies))
+		for seat, name := range options.Strategies {
+			strategy, err := bots.NewStrategy(name, rand.New(rand.NewPCG(rnd.Uint64(), rnd.Uint64())))
+			if err != nil {
+				return nil, err
+			}
+			seats[seat] = seatId(rnd)
+			strategies[seats[seat]] = strategy
+		}
+
+		results, rounds := playGame(seats, strategies)
+		report.Rounds += rounds
+
+		payouts := rules.Payouts(options.Prizes, results)
+		winners := 0
+		for _, result := range results {
+			if result.Place == 1 {
+				winners++
+			}
+		}
+
+		for seat, playerId := range seats {
+			seatReport := &report.Seats[seat]
+			seatReport.Games++
+			seatReport.Points += payouts[playerId]
+			for _, result := range results {
+				if result.PlayerID != playerId {
+					continue
+				}
+
+				seatReport.PlaceSum += int(result.Place)
+				switch {
+				case result.Place == 1 && winners == 1:
+					seatReport.Wins++
+				case result.Place == 1:
+					seatReport.Draws++
+				default:
+					seatReport.Losses++
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func playGame(seats []uuid.UUID, strategies map[uuid.UUID]interfaces.BotStrategy) ([]entities.GameResult, int) {
+	gameId := uuid.Nil
+	active := append([]uuid.UUID(nil), seats...)
+	moves := make([]entities.GameMove, 0)
+	results := make([]entities.GameResult, 0, len(seats))
+
+	for round := uint(1); ; round++ {
+		roundMoves := make([]entities.GameMove, 0, len(active))
+		for _, playerId := range active {
+			throw := strategies[playerId].Next(playerId, moves)
+			roundMoves = append(roundMoves, *entities.NewGameMove(gameId, playerId, round, throw))
+		}
+		moves = append(moves, roundMoves...)
+
+		outcome := rules.CompleteRound(gameId, active, roundMoves, round)
+		results = append(results, outcome.Results...)
+		if outcome.Finished {
+			return results, int(round)
+		}
+
+		decided := make(map[uuid.UUID]bool, len(outcome.Results))
+		for _, result := range outcome.Results {
+			decided[result.PlayerID] = true
+		}
+		remaining := active[:0]
+		for _, playerId := range active {
+			if !decided[playerId] {
+				remaining = append(remaining, playerId)
+			}
+		}
+		active = remaining
+	}
+}
+
+func seatId(rnd *rand.Rand) uuid.UUID {
+	var id uuid.UUID
+	for i := range id {
+		id[i] = byte(rnd.UintN(256))
+	}
+
+	return id
+}
+
+func (s SeatReport) WinRate() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+
+	return float64(s.Wins) / float64(s.Games)
+}
+
+// WinRateInterval is the 95% Wilson score interval of the win rate.
+func (s SeatReport) WinRateInterval() (float64, float64) {
+	if s.Games == 0 {
+		return 0, 0
+	}
+
+	n := float64(s.Games)
+	p := s.WinRate()
+	z2 := confidenceZ * confidenceZ
+	denominator := 1 + z2/n
+	centre := (p + z2/(2*n)) / denominator
+	half := confidenceZ * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / denominator
+
+	return math.Max(0, centre-half), math.Min(1, centre+half)
+}
+
+func (s SeatReport) AveragePlace() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+
+	return float64(s.PlaceSum) / float64(s.Games)
+}
+
+func (s SeatReport) AveragePoints() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+
+	return float64(s.Points) / float64(s.Games)
+}
@@ -0,0 +1,89 @@
+package simulation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"knb/app/dictionary"
+	"knb/app/entities"
+	"testing"
+)
+
+type simulateTestCase struct {
+	options       Options
+	expectedError string
+	name          string
+}
+
+func TestSimulateReplaysSeed(t *testing.T) {
+	options := Options{
+		Games: 50,
+		Strategies: []dictionary.BotStrategy{
+			dictionary.BotStrategyRandom,
+			dictionary.BotStrategyFrequency,
+			dictionary.BotStrategyMarkov,
+			dictionary.BotStrategyWinStay,
+		},
+		Seed:   42,
+		Prizes: []entities.GamePrize{{Place: 1, Prize: 10}},
+	}
+
+	first, err := Simulate(options)
+	if !assert.NoError(t, err) {
+		return
+	}
+	second, err := Simulate(options)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, first, second)
+
+	wins := 0
+	for _, seat := range first.Seats {
+		assert.Equal(t, options.Games, seat.Games)
+		assert.Equal(t, seat.Games, seat.Wins+seat.Draws+seat.Losses)
+		wins += seat.Wins
+	}
+	assert.Positive(t, wins)
+	assert.GreaterOrEqual(t, first.Rounds, options.Games)
+
+	options.Seed++
+	other, err := Simulate(options)
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, first, other)
+	}
+}
+
+func TestSimulateValidation(t *testing.T) {
+	testCases := []simulateTestCase{
+		{
+			options: Options{
+				Strategies: []dictionary.BotStrategy{dictionary.BotStrategyRandom, dictionary.BotStrategyRandom},
+			},
+			expectedError: "games count must be positive",
+			name:          "no games",
+		},
+		{
+			options: Options{
+				Games:      1,
+				Strategies: []dictionary.BotStrategy{dictionary.BotStrategyRandom},
+			},
+			expectedError: "at least 2 strategies are required",
+			name:          "a single strategy",
+		},
+		{
+			options: Options{
+				Games:      1,
+				Strategies: []dictionary.BotStrategy{dictionary.BotStrategyRandom, "unknown"},
+			},
+			expectedError: `unknown bot strategy "unknown"`,
+			name:          "an unknown strategy",
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(tt *testing.T) {
+			report, err := Simulate(tCase.options)
+			assert.Nil(tt, report)
+			assert.EqualError(tt, err, tCase.expectedError)
+		})
+	}
+}
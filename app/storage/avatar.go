@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	customErrors "knb/app/errors"
+	"knb/app/interfaces"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// localAvatarStorage keeps the uploaded avatars as files in a directory of the instance.
+type localAvatarStorage struct {
+	dir string
+}
+
+func NewLocalAvatarStorage(dir string) interfaces.AvatarStorage {
+	return &localAvatarStorage{dir}
+}
+
+func (l *localAvatarStorage) Save(name string, data []byte) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (l *localAvatarStorage) Open(name string) (io.ReadSeekCloser, time.Time, error) {
+	path, err := l.path(name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, time.Time{}, customErrors.NewNotFoundError("avatar was not found")
+		}
+
+		return nil, time.Time{}, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, time.Time{}, err
+	}
+
+	return file, info.ModTime(), nil
+}
+
+func (l *localAvatarStorage) Delete(name string) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path keeps the files inside the directory whatever name is requested.
+func (l *localAvatarStorage) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name[0] == '.' {
+		return "", customErrors.NewNotFoundError("avatar was not found")
+	}
+
+	return filepath.Join(l.dir, name), nil
+}
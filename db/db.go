@@ -40,16 +40,88 @@ func (db *DB) NewPostgresDb(config *config.DbConfig) error {
 }
 
 func (db *DB) Migrate() error {
-	return db.db.AutoMigrate(
+	if err := db.db.SetupJoinTable(&entities.Game{}, "Players", &entities.GamePlayer{}); err != nil {
+		return err
+	}
+	if err := db.db.SetupJoinTable(&entities.Tournament{}, "Players", &entities.TournamentPlayer{}); err != nil {
+		return err
+	}
+
+	if err := db.db.AutoMigrate(
 		&entities.Player{},
 		&entities.Game{},
 		&entities.GamePrize{},
 		&entities.GameResult{},
-	)
+		&entities.GameMove{},
+		&entities.ApiKey{},
+		&entities.Tournament{},
+		&entities.TournamentMatch{},
+		&entities.Session{},
+		&entities.RefreshToken{},
+		&entities.PasswordResetToken{},
+		&entities.TwoFactor{},
+		&entities.RecoveryCode{},
+		&entities.LoginThrottle{},
+		&entities.LoginFailure{},
+		&entities.PlayerRole{},
+		&entities.AuditEntry{},
+		&entities.PointsTransaction{},
+		&entities.Ban{},
+		&entities.Friendship{},
+		&entities.Block{},
+		&entities.Presence{},
+		&entities.ChatMessage{},
+		&entities.ChatReport{},
+		&entities.PlayerStats{},
+		&entities.PlayerRuleSetStats{},
+		&entities.PlayerThrowStats{},
+		&entities.PlayerOpponentStats{},
+		&entities.LeaderboardEntry{},
+	); err != nil {
+		return err
+	}
+
+	return db.backfillDisplayNames()
+}
+
+// backfillDisplayNames names the players registered before the display names could be set.
+func (db *DB) backfillDisplayNames() error {
+	return db.db.Exec(`
+		UPDATE players
+		SET display_name = 'Player ' || LEFT(id::text, 8),
+			display_name_key = LOWER('Player ' || LEFT(id::text, 8))
+		WHERE NOT is_bot AND (display_name IS NULL OR display_name = '')`,
+	).Error
 }
 
 func (db *DB) DropMigrate() error {
 	return db.db.Migrator().DropTable(
+		&entities.LeaderboardEntry{},
+		&entities.PlayerOpponentStats{},
+		&entities.PlayerThrowStats{},
+		&entities.PlayerRuleSetStats{},
+		&entities.PlayerStats{},
+		&entities.ChatReport{},
+		&entities.ChatMessage{},
+		&entities.Presence{},
+		&entities.Block{},
+		&entities.Friendship{},
+		&entities.Ban{},
+		&entities.PointsTransaction{},
+		&entities.AuditEntry{},
+		&entities.PlayerRole{},
+		&entities.LoginFailure{},
+		&entities.LoginThrottle{},
+		&entities.RecoveryCode{},
+		&entities.TwoFactor{},
+		&entities.PasswordResetToken{},
+		&entities.RefreshToken{},
+		&entities.Session{},
+		&entities.TournamentMatch{},
+		&entities.TournamentPlayer{},
+		&entities.Tournament{},
+		&entities.ApiKey{},
+		&entities.GameMove{},
 		&entities.GameResult{},
 		&entities.GamePlayer{},
 		&entities.GamePrize{},
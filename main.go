@@ -3,6 +3,7 @@ package main
 import (
 	"knb/app"
 	"knb/app/config"
+	"knb/app/simulation"
 	"log"
 	"os"
 	"os/signal"
@@ -14,14 +15,22 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == simulation.CommandName {
+		os.Exit(simulation.Run(os.Args[2:], os.Stdout))
+	}
+
 	appConfig, err := new(config.Config).Init(envFilePath)
 	if err != nil {
 		log.Fatalf("Failed initializing config: %s\n", err.Error())
 		return
 	}
 
-	println("App starting...")
 	application := app.NewApplication(appConfig)
+	if len(os.Args) > 1 && os.Args[1] == app.RoleCommandName {
+		os.Exit(application.RunRoleCommand(os.Args[2:], os.Stdout))
+	}
+
+	println("App starting...")
 	application.Run()
 
 	quit := make(chan os.Signal, 1)
@@ -16,7 +16,11 @@ func NewFixtures(db *gorm.DB, service *services.Service) *Fixture {
 
 func (f *Fixture) LoadPlayersFixture() error {
 	for _, player := range createPlayersTestFixtures() {
-		player.Password = f.service.Security.GeneratePasswordHash(player.Password)
+		password, err := f.service.Security.GeneratePasswordHash(player.Password)
+		if err != nil {
+			return err
+		}
+		player.Password = password
 		if err := f.db.Create(&player).Error; err != nil {
 			return err
 		}